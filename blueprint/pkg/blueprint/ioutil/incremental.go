@@ -0,0 +1,236 @@
+package ioutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+)
+
+// The file, within an output directory, that records the artifacts that Blueprint generated
+// to that directory and the hashes of their contents.  See [GenerateIncrementally].
+const ManifestFileName = ".blueprint-manifest.json"
+
+const manifestVersion = 1
+
+type manifest struct {
+	Version int               `json:"version"`
+	Files   map[string]string `json:"files"` // Slash-separated path within the output directory -> sha256 of contents
+}
+
+// The outcome of [GenerateIncrementally]
+type GenerateStats struct {
+	Written   []string // Files that were created or whose contents changed
+	Unchanged []string // Files whose contents were already up to date, and were not touched
+	Removed   []string // Files from a previous generation that are no longer generated
+}
+
+// Generates artifacts to outputDir, only rewriting files whose contents have changed.
+//
+// generate is called to produce the artifacts in a fresh staging directory next to outputDir.  The
+// staged files are then compared, by content hash, to the hashes recorded by the previous generation.
+// Files that are unchanged are left untouched, so that their modification times are preserved and
+// tools such as docker and go build can reuse their caches.  Files that were generated previously but are no longer
+// generated, e.g. because a node was removed from the application, are deleted.
+//
+// The files generated to outputDir are recorded in the [ManifestFileName] file.  Files in outputDir that
+// are not in the manifest, i.e. that weren't generated by Blueprint, are never deleted.  To avoid
+// clobbering unrelated files, outputDir must be empty, absent, or previously generated by Blueprint.
+//
+// If generate returns an error, outputDir is left unmodified.
+func GenerateIncrementally(outputDir string, generate func(stagingDir string) error) (*GenerateStats, error) {
+	outputDir = filepath.Clean(outputDir)
+	previous, err := readManifest(outputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stage next to outputDir, so that relative paths between generated files are the same
+	parent, base := filepath.Split(outputDir)
+	if parent == "" {
+		parent = "."
+	}
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return nil, blueprint.Errorf("unable to create output directory %v due to %v", outputDir, err.Error())
+	}
+	stagingDir, err := os.MkdirTemp(parent, "."+base+".staging-")
+	if err != nil {
+		return nil, blueprint.Errorf("unable to create staging directory for %v due to %v", outputDir, err.Error())
+	}
+	defer os.RemoveAll(stagingDir)
+
+	if err := generate(stagingDir); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, blueprint.Errorf("unable to create output directory %v due to %v", outputDir, err.Error())
+	}
+	return syncDir(stagingDir, outputDir, previous)
+}
+
+// Reads the manifest of a previous generation.  Returns an empty manifest if outputDir doesn't exist
+// or is empty, and an error if outputDir contains files that weren't generated by Blueprint.
+func readManifest(outputDir string) (*manifest, error) {
+	m := &manifest{Version: manifestVersion, Files: make(map[string]string)}
+	entries, err := os.ReadDir(outputDir)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && len(entries) == 0) {
+		return m, nil
+	} else if err != nil {
+		return nil, blueprint.Errorf("unable to read output directory %v due to %v", outputDir, err.Error())
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, ManifestFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, blueprint.Errorf("output directory %v already exists and was not generated by Blueprint", outputDir)
+	} else if err != nil {
+		return nil, blueprint.Errorf("unable to read %v due to %v", ManifestFileName, err.Error())
+	}
+	if err := json.Unmarshal(data, m); err != nil || m.Version != manifestVersion {
+		return nil, blueprint.Errorf("output directory %v has an invalid or outdated %v; delete the directory and regenerate", outputDir, ManifestFileName)
+	}
+	if m.Files == nil {
+		m.Files = make(map[string]string)
+	}
+	return m, nil
+}
+
+// Copies changed files from stagingDir to outputDir and removes stale files
+func syncDir(stagingDir, outputDir string, previous *manifest) (*GenerateStats, error) {
+	stats := &GenerateStats{}
+	next := &manifest{Version: manifestVersion, Files: make(map[string]string)}
+
+	err := filepath.WalkDir(stagingDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(stagingDir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		dst := filepath.Join(outputDir, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return ensureDir(dst, info.Mode().Perm())
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			next.Files[filepath.ToSlash(rel)] = hashBytes([]byte(target))
+			if existing, err := os.Readlink(dst); err == nil && existing == target {
+				stats.Unchanged = append(stats.Unchanged, rel)
+				return nil
+			}
+			if err := os.RemoveAll(dst); err != nil {
+				return err
+			}
+			stats.Written = append(stats.Written, rel)
+			return os.Symlink(target, dst)
+		case info.Mode().IsRegular():
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			// The manifest records what was previously generated, so existing files aren't reread
+			hash := hashBytes(data)
+			next.Files[filepath.ToSlash(rel)] = hash
+			if previous.Files[filepath.ToSlash(rel)] == hash && isRegularFile(dst, int64(len(data))) {
+				stats.Unchanged = append(stats.Unchanged, rel)
+				if existing, err := os.Lstat(dst); err == nil && existing.Mode().Perm() != info.Mode().Perm() {
+					return os.Chmod(dst, info.Mode().Perm())
+				}
+				return nil
+			}
+			if existing, err := os.Lstat(dst); err == nil && !existing.Mode().IsRegular() {
+				if err := os.RemoveAll(dst); err != nil {
+					return err
+				}
+			}
+			stats.Written = append(stats.Written, rel)
+			if err := os.WriteFile(dst, data, info.Mode().Perm()); err != nil {
+				return err
+			}
+			// WriteFile doesn't change the permissions of an existing file
+			return os.Chmod(dst, info.Mode().Perm())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, blueprint.Errorf("unable to copy generated artifacts to %v due to %v", outputDir, err.Error())
+	}
+
+	// Remove files that were previously generated but no longer are
+	var stale []string
+	for path := range previous.Files {
+		if _, stillGenerated := next.Files[path]; !stillGenerated {
+			stale = append(stale, path)
+		}
+	}
+	sort.Strings(stale)
+	for _, path := range stale {
+		dst := filepath.Join(outputDir, filepath.FromSlash(path))
+		if err := os.Remove(dst); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, blueprint.Errorf("unable to remove stale artifact %v due to %v", dst, err.Error())
+		}
+		removeEmptyParents(filepath.Dir(dst), outputDir)
+		stats.Removed = append(stats.Removed, filepath.FromSlash(path))
+	}
+
+	data, err := json.MarshalIndent(next, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, ManifestFileName), data, 0644); err != nil {
+		return nil, blueprint.Errorf("unable to write %v due to %v", ManifestFileName, err.Error())
+	}
+	return stats, nil
+}
+
+func ensureDir(path string, perm fs.FileMode) error {
+	if info, err := os.Lstat(path); err == nil {
+		if info.IsDir() {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return os.MkdirAll(path, perm|0700)
+}
+
+// Reports whether path is a regular file of the given size
+func isRegularFile(path string, size int64) bool {
+	info, err := os.Lstat(path)
+	return err == nil && info.Mode().IsRegular() && info.Size() == size
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Removes dir and its parents, up to but not including root, while they are empty
+func removeEmptyParents(dir, root string) {
+	for dir != root && len(dir) > len(root) {
+		entries, err := os.ReadDir(dir)
+		if err != nil || len(entries) > 0 {
+			return
+		}
+		if os.Remove(dir) != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
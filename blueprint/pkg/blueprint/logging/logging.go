@@ -16,6 +16,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 
 	"golang.org/x/exp/slog"
 	"golang.org/x/mod/modfile"
@@ -149,6 +150,9 @@ func (info *sourceFileInfo) String() string {
 
 var fileInfoCache = make(map[string]*sourceFileInfo)
 
+// Guards fileInfoCache; plugins can log concurrently when generating artifacts
+var fileInfoMu sync.Mutex
+
 /*
 Starting from the specified subdirectory, recurses through parent
 directories until finding a file with the specified name.
@@ -168,6 +172,8 @@ func findFileInParentDirectory(dir string, fileName string) string {
 }
 
 func getSourceFileInfo(fileName string) *sourceFileInfo {
+	fileInfoMu.Lock()
+	defer fileInfoMu.Unlock()
 	if info, exists := fileInfoCache[fileName]; exists {
 		return info
 	}
@@ -252,6 +258,36 @@ func (stack *Callstack) String() string {
 	return strings.Join(s, "\n")
 }
 
+// Modules whose frames are skipped by [Callstack.WiringCallsite]
+var compilerModules = []string{
+	"github.com/blueprint-uservices/blueprint/blueprint",
+	"github.com/blueprint-uservices/blueprint/plugins",
+}
+
+// Returns the first callsite in the stack that is outside of Blueprint's compiler and plugins.
+// Typically this is the line of the wiring spec that caused the stack to be captured.
+// If every frame belongs to the compiler or plugins, then the first callsite is returned.
+func (stack *Callstack) WiringCallsite() (Callsite, bool) {
+	if stack == nil || len(stack.Stack) == 0 {
+		return Callsite{}, false
+	}
+	for _, callsite := range stack.Stack {
+		if callsite.Source == nil || !isCompilerModule(callsite.Source.Module) {
+			return callsite, true
+		}
+	}
+	return stack.Stack[0], true
+}
+
+func isCompilerModule(module string) bool {
+	for _, m := range compilerModules {
+		if module == m {
+			return true
+		}
+	}
+	return false
+}
+
 // Gets the current callstack including file information.
 // Blueprint's wiring spec uses this so that logging statements and error messages
 // can be attributed back to the appropriate wiring spec line.
@@ -22,6 +22,11 @@ import (
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
 )
 
+func init() {
+	ir.RegisterJSONType[*BindConfig]()
+	ir.RegisterJSONType[*DialConfig]()
+}
+
 type (
 	// IR metadata node representing an address.
 	//
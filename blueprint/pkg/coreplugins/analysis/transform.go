@@ -0,0 +1,294 @@
+package analysis
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"golang.org/x/exp/slog"
+)
+
+// IRTransformPass is a Blueprint compiler pass that rewrites an ir.ApplicationNode after it has been
+// built and before artifacts are generated, for example to wrap every service with tracing, or to
+// strip fault injectors from a production build.
+//
+// Transform passes declare dependencies on other passes by name; [OrderTransformPasses] uses these
+// to decide the order in which passes run.
+type IRTransformPass interface {
+	Name() string
+	Dependencies() PassDependencies
+	Transform(spec wiring.WiringSpec, app *ir.ApplicationNode) error
+	ImplementsTransformPass()
+}
+
+// The dependencies of an [IRTransformPass] on other passes, by name
+type PassDependencies struct {
+	Requires []string // Passes that must be registered, and that run before this pass
+	After    []string // Passes that, if registered, run before this pass
+	Before   []string // Passes that, if registered, run after this pass
+}
+
+// Orders passes so that every pass runs after the passes it requires or declares it runs after, and
+// before the passes it declares it runs before.  Passes that aren't constrained relative to each other
+// run in the order they were provided.
+//
+// Returns an error if two passes have the same name, if a required pass isn't in passes, or if the
+// dependencies contain a cycle.
+func OrderTransformPasses(passes []IRTransformPass) ([]IRTransformPass, error) {
+	index := make(map[string]int)
+	for i, pass := range passes {
+		if _, exists := index[pass.Name()]; exists {
+			return nil, blueprint.Errorf("transform pass %v is registered more than once", pass.Name())
+		}
+		index[pass.Name()] = i
+	}
+
+	// runsAfter[i] are the indices of the passes that must run before pass i
+	runsAfter := make([]map[int]struct{}, len(passes))
+	for i := range passes {
+		runsAfter[i] = make(map[int]struct{})
+	}
+	for i, pass := range passes {
+		deps := pass.Dependencies()
+		for _, name := range deps.Requires {
+			j, exists := index[name]
+			if !exists {
+				return nil, blueprint.Errorf("transform pass %v requires pass %v, which is not registered", pass.Name(), name)
+			}
+			runsAfter[i][j] = struct{}{}
+		}
+		for _, name := range deps.After {
+			if j, exists := index[name]; exists {
+				runsAfter[i][j] = struct{}{}
+			}
+		}
+		for _, name := range deps.Before {
+			if j, exists := index[name]; exists {
+				runsAfter[j][i] = struct{}{}
+			}
+		}
+	}
+
+	// Repeatedly pick the first pass whose dependencies have all run
+	ordered := make([]IRTransformPass, 0, len(passes))
+	done := make([]bool, len(passes))
+	for len(ordered) < len(passes) {
+		next := -1
+		for i := range passes {
+			if !done[i] && allDone(runsAfter[i], done) {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			var cycle []string
+			for i, pass := range passes {
+				if !done[i] {
+					cycle = append(cycle, pass.Name())
+				}
+			}
+			return nil, blueprint.Errorf("transform passes %v have cyclic dependencies", strings.Join(cycle, ", "))
+		}
+		done[next] = true
+		ordered = append(ordered, passes[next])
+	}
+	return ordered, nil
+}
+
+func allDone(deps map[int]struct{}, done []bool) bool {
+	for j := range deps {
+		if !done[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// Orders passes using [OrderTransformPasses] then runs each of them on app.  Stops at the first pass that
+// returns an error.
+func RunTransformPasses(spec wiring.WiringSpec, app *ir.ApplicationNode, passes []IRTransformPass) error {
+	ordered, err := OrderTransformPasses(passes)
+	if err != nil {
+		return err
+	}
+	for _, pass := range ordered {
+		slog.Info("Running transform pass " + pass.Name() + " on the IR")
+		if err := pass.Transform(spec, app); err != nil {
+			return blueprint.Errorf("transform pass %v was unsuccessful due to %v", pass.Name(), err.Error())
+		}
+	}
+	return nil
+}
+
+// Replaces references to old with replacement throughout app, and returns the number of references that
+// were replaced.  References are exported fields of IR nodes, including slices of nodes such as the children
+// of namespaces.  If a slice would contain replacement twice, only the first is kept.
+//
+// If replacement is nil, old is removed from slices and other references to old are left unchanged.
+//
+// Returns an error if a field that refers to old cannot hold replacement.
+func ReplaceNode(app *ir.ApplicationNode, old ir.IRNode, replacement ir.IRNode) (int, error) {
+	oldPtr, ok := nodePointer(reflect.ValueOf(old))
+	if !ok {
+		return 0, blueprint.Errorf("cannot replace %v because it is not a pointer", old.Name())
+	}
+	r := &replacer{oldNode: old, old: oldPtr, replacement: replacement, visited: make(map[uintptr]struct{})}
+
+	// Visit every node reachable from app, not just namespace children, because some
+	// nodes contain other nodes without being namespaces
+	queue := []ir.IRNode{app}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		v := reflect.ValueOf(node)
+		ptr, ok := nodePointer(v)
+		if _, visited := r.visited[ptr]; !ok || visited || ptr == oldPtr {
+			continue
+		}
+		r.visited[ptr] = struct{}{}
+		if err := r.replaceIn(v.Elem(), v.Elem().Type().String()); err != nil {
+			return r.count, err
+		}
+		queue = append(queue, r.found...)
+		r.found = nil
+	}
+	return r.count, nil
+}
+
+type replacer struct {
+	oldNode     ir.IRNode
+	old         uintptr
+	replacement ir.IRNode
+	visited     map[uintptr]struct{}
+	found       []ir.IRNode // Nodes referenced by the node being visited
+	count       int
+}
+
+func nodePointer(v reflect.Value) (uintptr, bool) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return 0, false
+	}
+	return v.Pointer(), true
+}
+
+// Returns whether v holds old
+func (r *replacer) isOld(v reflect.Value) bool {
+	ptr, ok := nodePointer(v)
+	return ok && ptr == r.old
+}
+
+// Replaces references to old within v, which is a field of a node, and records any other nodes referenced by v
+func (r *replacer) replaceIn(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Type().Field(i); f.IsExported() {
+				if err := r.replaceIn(v.Field(i), path+"."+f.Name); err != nil {
+					return err
+				}
+			}
+		}
+	case reflect.Interface, reflect.Pointer:
+		if r.isOld(v) {
+			if r.replacement == nil {
+				return nil
+			}
+			replacement := reflect.ValueOf(r.replacement)
+			if !v.CanSet() || !replacement.Type().AssignableTo(v.Type()) {
+				return blueprint.Errorf("cannot replace %v with %v in %v of type %v", r.oldNode.Name(), r.replacement.Name(), path, v.Type())
+			}
+			v.Set(replacement)
+			r.count++
+		} else if v.CanInterface() && !v.IsNil() {
+			if node, isNode := v.Interface().(ir.IRNode); isNode {
+				r.found = append(r.found, node)
+			}
+		}
+	case reflect.Slice:
+		return r.replaceInSlice(v, path)
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if value := iter.Value(); value.CanInterface() {
+				if node, isNode := value.Interface().(ir.IRNode); isNode && !r.isOld(value) {
+					r.found = append(r.found, node)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (r *replacer) replaceInSlice(v reflect.Value, path string) error {
+	elem := v.Type().Elem()
+	if elem.Kind() != reflect.Interface && elem.Kind() != reflect.Pointer {
+		for i := 0; i < v.Len(); i++ {
+			if err := r.replaceIn(v.Index(i), path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var replacement reflect.Value
+	var replacementPtr uintptr
+	if r.replacement != nil {
+		replacement = reflect.ValueOf(r.replacement)
+		replacementPtr, _ = nodePointer(replacement)
+	}
+
+	contains := false
+	for i := 0; i < v.Len(); i++ {
+		if r.isOld(v.Index(i)) {
+			contains = true
+			break
+		}
+	}
+	if !contains {
+		for i := 0; i < v.Len(); i++ {
+			if err := r.replaceIn(v.Index(i), path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if !v.CanSet() {
+		return blueprint.Errorf("cannot replace %v in %v because it is not settable", r.oldNode.Name(), path)
+	}
+	if replacement.IsValid() && !replacement.Type().AssignableTo(elem) {
+		return blueprint.Errorf("cannot replace %v with %v in %v of type %v", r.oldNode.Name(), r.replacement.Name(), path, v.Type())
+	}
+
+	// Rebuild the slice, without duplicates of the replacement
+	updated := reflect.MakeSlice(v.Type(), 0, v.Len())
+	hasReplacement := false
+	for i := 0; i < v.Len(); i++ {
+		e := v.Index(i)
+		if r.isOld(e) {
+			r.count++
+			if !replacement.IsValid() {
+				continue
+			}
+			e = replacement
+		}
+		if ptr, ok := nodePointer(e); ok && replacement.IsValid() && ptr == replacementPtr {
+			if hasReplacement {
+				continue
+			}
+			hasReplacement = true
+		}
+		if e.CanInterface() && !(e.Kind() == reflect.Interface && e.IsNil()) {
+			if node, isNode := e.Interface().(ir.IRNode); isNode {
+				r.found = append(r.found, node)
+			}
+		}
+		updated = reflect.Append(updated, e)
+	}
+	v.Set(updated)
+	return nil
+}
@@ -0,0 +1,185 @@
+package irgraph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The differences between two graphs, as computed by [Diff].
+//
+// Nodes are matched by name and type rather than by ID, so that a node that moved to a
+// different namespace is reported as moved rather than as removed and added.
+type GraphDiff struct {
+	Added   []*Node      // Nodes that only exist in the second graph
+	Removed []*Node      // Nodes that only exist in the first graph
+	Moved   []NodeMove   // Nodes that exist in both graphs, but in different namespaces
+	Changed []NodeChange // Nodes that exist in both graphs, but with a different type or properties
+}
+
+// A node whose namespace placement differs between two graphs
+type NodeMove struct {
+	Name     string
+	From, To string // IDs of the namespaces containing the node in each graph; empty string is the application
+}
+
+// A node whose type or properties differ between two graphs
+type NodeChange struct {
+	Name       string
+	From, To   *Node
+	Properties []PropertyChange
+}
+
+// A property that differs between two graphs.  A property that only exists
+// on one of the nodes has the empty string for the other value.
+type PropertyChange struct {
+	Key      string
+	From, To string
+}
+
+// Computes the differences between graphs a and b.
+func Diff(a, b *Graph) *GraphDiff {
+	diff := &GraphDiff{}
+	as, bs := nodesByName(a), nodesByName(b)
+
+	names := sortedKeys(as)
+	for name := range bs {
+		if _, inA := as[name]; !inA {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		pairs, removed, added := pairNodes(as[name], bs[name])
+		diff.Removed = append(diff.Removed, removed...)
+		diff.Added = append(diff.Added, added...)
+		for _, pair := range pairs {
+			if pair[0].Namespace != pair[1].Namespace {
+				diff.Moved = append(diff.Moved, NodeMove{Name: name, From: pair[0].Namespace, To: pair[1].Namespace})
+			}
+			if change, changed := compareNodes(pair[0], pair[1]); changed {
+				diff.Changed = append(diff.Changed, change)
+			}
+		}
+	}
+	return diff
+}
+
+// Reports whether the graphs are the same
+func (d *GraphDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Moved) == 0 && len(d.Changed) == 0
+}
+
+func (d *GraphDiff) String() string {
+	if d.Empty() {
+		return "no differences"
+	}
+	var b strings.Builder
+	for _, node := range d.Removed {
+		fmt.Fprintf(&b, "- %s = %s in %s\n", node.Name, node.Type, namespaceName(node.Namespace))
+	}
+	for _, node := range d.Added {
+		fmt.Fprintf(&b, "+ %s = %s in %s\n", node.Name, node.Type, namespaceName(node.Namespace))
+	}
+	for _, move := range d.Moved {
+		fmt.Fprintf(&b, "~ %s moved from %s to %s\n", move.Name, namespaceName(move.From), namespaceName(move.To))
+	}
+	for _, change := range d.Changed {
+		if change.From.Type != change.To.Type {
+			fmt.Fprintf(&b, "~ %s changed type from %s to %s\n", change.Name, change.From.Type, change.To.Type)
+		}
+		for _, prop := range change.Properties {
+			fmt.Fprintf(&b, "~ %s.%s changed from %q to %q\n", change.Name, prop.Key, prop.From, prop.To)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func nodesByName(g *Graph) map[string][]*Node {
+	nodes := make(map[string][]*Node)
+	for _, node := range g.Nodes {
+		nodes[node.Name] = append(nodes[node.Name], node)
+	}
+	return nodes
+}
+
+func sortedKeys(m map[string][]*Node) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+/*
+Pairs up nodes with the same name, of which there can be several, e.g. in different processes.
+Returns the pairs, then the nodes of as and of bs that aren't paired.  Nodes are paired in order of:
+  - the same type and namespace
+  - the same type, since the node has moved
+  - the same namespace, since the node's type has changed
+*/
+func pairNodes(as, bs []*Node) (pairs [][2]*Node, unpairedA, unpairedB []*Node) {
+	unpairedA = sortedByNamespace(as)
+	unpairedB = sortedByNamespace(bs)
+	matches := []func(a, b *Node) bool{
+		func(a, b *Node) bool { return a.Type == b.Type && a.Namespace == b.Namespace },
+		func(a, b *Node) bool { return a.Type == b.Type },
+		func(a, b *Node) bool { return a.Namespace == b.Namespace },
+	}
+	for _, match := range matches {
+		var remainingA []*Node
+		for _, a := range unpairedA {
+			paired := false
+			for i, b := range unpairedB {
+				if match(a, b) {
+					pairs = append(pairs, [2]*Node{a, b})
+					unpairedB = append(unpairedB[:i:i], unpairedB[i+1:]...)
+					paired = true
+					break
+				}
+			}
+			if !paired {
+				remainingA = append(remainingA, a)
+			}
+		}
+		unpairedA = remainingA
+	}
+	return pairs, unpairedA, unpairedB
+}
+
+func sortedByNamespace(nodes []*Node) []*Node {
+	sorted := append([]*Node{}, nodes...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Namespace < sorted[j].Namespace })
+	return sorted
+}
+
+func compareNodes(a, b *Node) (NodeChange, bool) {
+	change := NodeChange{Name: a.Name, From: a, To: b}
+	keys := make(map[string]struct{})
+	for key := range a.Properties {
+		keys[key] = struct{}{}
+	}
+	for key := range b.Properties {
+		keys[key] = struct{}{}
+	}
+	var sorted []string
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	for _, key := range sorted {
+		if a.Properties[key] != b.Properties[key] {
+			change.Properties = append(change.Properties, PropertyChange{Key: key, From: a.Properties[key], To: b.Properties[key]})
+		}
+	}
+	return change, a.Type != b.Type || len(change.Properties) > 0
+}
+
+func namespaceName(id string) string {
+	if id == "" {
+		return "application"
+	}
+	return id
+}
@@ -0,0 +1,106 @@
+package irgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+)
+
+// Writes the graph as JSON
+func WriteJSON(w io.Writer, g *Graph) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// Writes the graph in Graphviz DOT format.
+//
+// Namespace nodes are rendered as clusters containing their children.  Arg edges are
+// solid and labelled with the field that holds the reference; address edges are dashed.
+func WriteDOT(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", quote(g.Application))
+	b.WriteString("  compound=true;\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [fontsize=10];\n")
+	b.WriteString("  edge [fontsize=8];\n")
+	g.writeDOTNodes(&b, "", "  ")
+	for _, edge := range g.Edges {
+		var attrs []string
+		if edge.Field != "" {
+			attrs = append(attrs, "label="+quote(edge.Field))
+		}
+		if edge.Kind == EdgeAddress {
+			attrs = append(attrs, "style=dashed")
+		}
+		if g.byID[edge.From].Kind == KindNamespace {
+			attrs = append(attrs, "ltail="+quote(clusterName(edge.From)))
+		}
+		if g.byID[edge.To].Kind == KindNamespace {
+			attrs = append(attrs, "lhead="+quote(clusterName(edge.To)))
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", quote(edge.From), quote(edge.To), strings.Join(attrs, ", "))
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (g *Graph) writeDOTNodes(b *strings.Builder, namespace string, indent string) {
+	for _, node := range g.Children(namespace) {
+		label := quote(node.Name + "\n" + node.Type)
+		if node.Kind == KindNamespace {
+			fmt.Fprintf(b, "%ssubgraph %s {\n", indent, quote(clusterName(node.ID)))
+			fmt.Fprintf(b, "%s  label=%s;\n", indent, label)
+			// Edges to and from a cluster must be attached to a node inside it
+			fmt.Fprintf(b, "%s  %s [shape=point, style=invis];\n", indent, quote(node.ID))
+			g.writeDOTNodes(b, node.ID, indent+"  ")
+			fmt.Fprintf(b, "%s}\n", indent)
+			continue
+		}
+		shape := "box"
+		switch node.Kind {
+		case KindConfig:
+			shape = "note"
+		case KindMetadata:
+			shape = "ellipse"
+		}
+		fmt.Fprintf(b, "%s%s [label=%s, shape=%s];\n", indent, quote(node.ID), label, shape)
+	}
+}
+
+func clusterName(id string) string {
+	return "cluster_" + id
+}
+
+// Quotes s as a DOT string
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// Exports the graph to the file at path, creating any directories that don't exist.
+// The format is determined by write, e.g. [WriteDOT] or [WriteJSON].
+func ExportToFile(g *Graph, path string, write func(io.Writer, *Graph) error) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return blueprint.Errorf("unable to create directory for %v due to %v", path, err.Error())
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return blueprint.Errorf("unable to create %v due to %v", path, err.Error())
+	}
+	defer f.Close()
+	if err := write(f, g); err != nil {
+		return blueprint.Errorf("unable to write IR graph to %v due to %v", path, err.Error())
+	}
+	return nil
+}
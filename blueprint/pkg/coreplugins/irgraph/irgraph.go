@@ -0,0 +1,371 @@
+// Package irgraph converts an application's IR into a graph of nodes and edges that can be
+// exported for use outside of Blueprint, for example to render architecture diagrams or to
+// feed the application's topology into other tools.
+//
+// Use [FromIR] to extract the graph from an [ir.ApplicationNode], then [WriteDOT] to export
+// it in Graphviz DOT format or [WriteJSON] to export it as JSON.
+//
+// The graph contains one node for every IR node in the application.  Namespace nodes such as
+// processes and containers contain their child nodes; in DOT output they are rendered as clusters.
+// Edges are discovered by inspecting the fields of each IR node for references to other IR nodes,
+// i.e. the arguments the node was built with.  The edge from an address to the server it points to
+// is an address edge.
+package irgraph
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/address"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+)
+
+// The kinds of node in a [Graph]
+const (
+	KindNamespace = "namespace" // The node implements [ir.HasIRChildren]
+	KindConfig    = "config"    // The node implements [ir.IRConfig]
+	KindMetadata  = "metadata"  // The node implements [ir.IRMetadata]
+	KindNode      = "node"      // Any other node
+)
+
+// The kinds of edge in a [Graph]
+const (
+	EdgeArg     = "arg"     // The source node was built with the destination node as an argument
+	EdgeAddress = "address" // The source node is an address and the destination is what it addresses
+)
+
+// The graph of an application's IR
+type Graph struct {
+	Application string  `json:"application"`
+	Nodes       []*Node `json:"nodes"`
+	Edges       []*Edge `json:"edges"`
+
+	byID map[string]*Node
+}
+
+// A node of the IR
+type Node struct {
+	ID         string            `json:"id"`                  // Unique within the graph; the path of namespaces containing the node, followed by its name
+	Name       string            `json:"name"`                // The name of the IR node
+	Type       string            `json:"type"`                // The Go type of the IR node
+	Kind       string            `json:"kind"`                // One of KindNamespace, KindConfig, KindMetadata, or KindNode
+	Namespace  string            `json:"namespace,omitempty"` // The ID of the namespace node containing this node; empty if the node is at the application level
+	Properties map[string]string `json:"properties,omitempty"`
+
+	node ir.IRNode
+}
+
+// A reference from one IR node to another
+type Edge struct {
+	From  string `json:"from"`  // ID of the referencing node
+	To    string `json:"to"`    // ID of the referenced node
+	Kind  string `json:"kind"`  // One of EdgeArg or EdgeAddress
+	Field string `json:"field"` // The field of the referencing node that holds the reference
+}
+
+// Returns the IR node that this graph node was extracted from
+func (n *Node) IRNode() ir.IRNode {
+	return n.node
+}
+
+// Returns the node with the specified ID, or nil if it doesn't exist
+func (g *Graph) Node(id string) *Node {
+	return g.byID[id]
+}
+
+// Returns the nodes that are directly contained in the namespace with the specified ID.
+// The empty string returns the application-level nodes.
+func (g *Graph) Children(id string) []*Node {
+	var children []*Node
+	for _, node := range g.Nodes {
+		if node.Namespace == id {
+			children = append(children, node)
+		}
+	}
+	return children
+}
+
+// Extracts the graph of nodes and edges from the application's IR.
+//
+// The nodes and edges of the graph are in a deterministic order, so that exporting
+// the same application twice produces identical output.
+func FromIR(app *ir.ApplicationNode) *Graph {
+	g := &Graph{
+		Application: app.Name(),
+		byID:        make(map[string]*Node),
+	}
+	ids := make(map[uintptr]string)
+	g.addNodes("", app.Children, ids)
+
+	// Some nodes, such as client pools, contain nodes without being namespaces.  Those nodes
+	// are added alongside the node that contains them.
+	for i := 0; i < len(g.Nodes); i++ {
+		var unlisted []ir.IRNode
+		findUnlisted(reflect.ValueOf(g.Nodes[i].node), ids, &unlisted, true)
+		g.addNodes(g.Nodes[i].Namespace, unlisted, ids)
+	}
+
+	for _, node := range g.Nodes {
+		g.addEdges(node, ids)
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Field < b.Field
+	})
+	return g
+}
+
+// Adds nodes depth-first, sorted by name within each namespace
+func (g *Graph) addNodes(namespace string, nodes []ir.IRNode, ids map[uintptr]string) {
+	sorted := append([]ir.IRNode{}, nodes...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name() < sorted[j].Name() })
+
+	for _, irnode := range sorted {
+		ptr, ok := pointerOf(reflect.ValueOf(irnode))
+		if !ok {
+			continue
+		}
+		if _, exists := ids[ptr]; exists {
+			// The same node can't be in two namespaces; keep the first
+			continue
+		}
+
+		node := &Node{
+			ID:         irnode.Name(),
+			Name:       irnode.Name(),
+			Type:       typeName(irnode),
+			Kind:       kindOf(irnode),
+			Namespace:  namespace,
+			Properties: properties(irnode),
+			node:       irnode,
+		}
+		if namespace != "" {
+			node.ID = namespace + "/" + node.Name
+		}
+		if _, exists := g.byID[node.ID]; exists {
+			continue
+		}
+		ids[ptr] = node.ID
+		g.byID[node.ID] = node
+		g.Nodes = append(g.Nodes, node)
+
+		if ns, isNamespace := irnode.(ir.HasIRChildren); isNamespace {
+			g.addNodes(node.ID, ns.GetNodes(), ids)
+		}
+	}
+}
+
+// Adds an edge for every field of node that refers to another node in the graph.  A namespace's
+// references to its own children are not edges; the children are already contained by the namespace.
+func (g *Graph) addEdges(node *Node, ids map[uintptr]string) {
+	refs := make(map[string]map[string]struct{})
+	findRefs(reflect.ValueOf(node.node), "", ids, refs, true)
+
+	edgeKind := EdgeArg
+	if _, isAddr := node.node.(address.Node); isAddr {
+		edgeKind = EdgeAddress
+	}
+
+	for to, fields := range refs {
+		target := g.byID[to]
+		if to == node.ID || target.Namespace == node.ID {
+			continue
+		}
+		for field := range fields {
+			kind := edgeKind
+			if edgeKind == EdgeAddress && target.Kind == KindConfig {
+				// An address's bind and dial configuration are args of the address
+				kind = EdgeArg
+			}
+			g.Edges = append(g.Edges, &Edge{From: node.ID, To: to, Kind: kind, Field: field})
+		}
+	}
+}
+
+// Recursively looks through v for references to nodes in ids, recording the name of the
+// top-level field that holds each reference.  Pointers are only followed at the top level;
+// pointers to other nodes are references, and pointers to anything else are ignored.
+func findRefs(v reflect.Value, field string, ids map[uintptr]string, refs map[string]map[string]struct{}, top bool) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if !v.IsNil() {
+			findRefs(v.Elem(), field, ids, refs, top)
+		}
+	case reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+		if !top {
+			if id, isNode := ids[v.Pointer()]; isNode {
+				if _, exists := refs[id]; !exists {
+					refs[id] = make(map[string]struct{})
+				}
+				refs[id][field] = struct{}{}
+			}
+			return
+		}
+		findRefs(v.Elem(), field, ids, refs, false)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			name := field
+			if name == "" && !(f.Anonymous && f.Type.Kind() == reflect.Struct) {
+				name = f.Name
+			}
+			findRefs(v.Field(i), name, ids, refs, false)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			findRefs(v.Index(i), field, ids, refs, false)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			findRefs(iter.Value(), field, ids, refs, false)
+		}
+	}
+}
+
+// Recursively looks through the exported fields of v for IR nodes that aren't in ids.  Like
+// [findRefs], pointers are only followed at the top level.
+func findUnlisted(v reflect.Value, ids map[uintptr]string, unlisted *[]ir.IRNode, top bool) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if !v.IsNil() {
+			findUnlisted(v.Elem(), ids, unlisted, top)
+		}
+	case reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+		if !top {
+			if _, isListed := ids[v.Pointer()]; !isListed && v.CanInterface() {
+				if node, isNode := v.Interface().(ir.IRNode); isNode {
+					if _, isApp := node.(*ir.ApplicationNode); !isApp {
+						*unlisted = append(*unlisted, node)
+					}
+				}
+			}
+			return
+		}
+		findUnlisted(v.Elem(), ids, unlisted, false)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				findUnlisted(v.Field(i), ids, unlisted, false)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			findUnlisted(v.Index(i), ids, unlisted, false)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			findUnlisted(iter.Value(), ids, unlisted, false)
+		}
+	}
+}
+
+func pointerOf(v reflect.Value) (uintptr, bool) {
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return 0, false
+	}
+	return v.Pointer(), true
+}
+
+func kindOf(node ir.IRNode) string {
+	switch node.(type) {
+	case ir.HasIRChildren:
+		return KindNamespace
+	case ir.IRConfig:
+		return KindConfig
+	case ir.IRMetadata:
+		return KindMetadata
+	default:
+		return KindNode
+	}
+}
+
+// Matches the package path of a type, e.g. the github.com/.../ in github.com/.../golang.Service
+var pkgPath = regexp.MustCompile(`[\w.\-]+(/[\w.\-]+)*/`)
+
+// Returns the unqualified type name of the node, e.g. goproc.Process
+func typeName(node ir.IRNode) string {
+	t := reflect.TypeOf(node)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return pkgPath.ReplaceAllString(t.String(), "")
+}
+
+// Returns the exported fields of node that have simple values, plus the value of
+// config nodes.  Fields of embedded structs are included.
+func properties(node ir.IRNode) map[string]string {
+	props := make(map[string]string)
+	v := reflect.ValueOf(node)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		addProperties(v, props)
+	}
+	if conf, isConfig := node.(ir.IRConfig); isConfig && conf.HasValue() {
+		props["value"] = conf.Value()
+	}
+	if len(props) == 0 {
+		return nil
+	}
+	return props
+}
+
+func addProperties(v reflect.Value, props map[string]string) {
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		fv := v.Field(i)
+		if f.Anonymous && fv.Kind() == reflect.Struct {
+			addProperties(fv, props)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if value, isSimple := simpleValue(fv); isSimple {
+			props[f.Name] = value
+		}
+	}
+}
+
+func simpleValue(v reflect.Value) (string, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return fmt.Sprint(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprint(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprint(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v.Float()), true
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return "", false
+		}
+		var values []string
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, v.Index(i).String())
+		}
+		return strings.Join(values, ","), true
+	}
+	return "", false
+}
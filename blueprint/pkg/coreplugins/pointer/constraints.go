@@ -0,0 +1,85 @@
+package pointer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint/logging"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+)
+
+// The category of modifiers that deploy a service over the network, such as RPC clients and servers,
+// and addresses.  Once an RPC modifier has been added to a pointer, subsequent modifiers are outside
+// the service's process boundary.
+const RPC = "rpc"
+
+var (
+	// Options for modifiers that deploy a service over the network.  [AddAddrModifier] uses these options.
+	RPCModifier = ModifierOpts{IsInterfaceNode: true, Categories: []string{RPC}}
+
+	// Options for modifiers that wrap a service within its process, and so must be added before
+	// the service is deployed over the network.
+	InProcessModifier = ModifierOpts{IsInterfaceNode: true, Before: []string{RPC}}
+)
+
+// Adds an error to the spec for each modifier already on the pointer that modifierName must be added before
+func (ptr *PointerDef) checkConstraints(spec wiring.WiringSpec, modifierName string, opts ModifierOpts, callstack *logging.Callstack) {
+	if len(opts.Before) == 0 {
+		return
+	}
+	for _, existing := range ptr.modifiersInOrder() {
+		category, conflicts := sharedCategory(opts.Before, ptr.options[existing].Categories)
+		if !conflicts {
+			continue
+		}
+		existingCallstack := ptr.callsites[existing]
+		spec.AddError(blueprint.Errorf("%v at %v adds %v to %v, but %v must be added before any %v modifier; %v at %v already added %v modifier %v to %v",
+			pluginFunc(callstack), callsiteOf(callstack), modifierName, ptr.name, modifierName, category,
+			pluginFunc(existingCallstack), callsiteOf(existingCallstack), category, existing, ptr.name))
+		return
+	}
+}
+
+// The modifiers on both sides of the pointer; client side first, then server side, each in the order they were added
+func (ptr *PointerDef) modifiersInOrder() []string {
+	modifiers := append([]string{}, ptr.srcModifiers...)
+	for i := len(ptr.dstModifiers) - 2; i >= 0; i-- {
+		modifiers = append(modifiers, ptr.dstModifiers[i])
+	}
+	return modifiers
+}
+
+func sharedCategory(before []string, categories []string) (string, bool) {
+	for _, b := range before {
+		for _, c := range categories {
+			if b == c {
+				return b, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Returns the plugin function that added a modifier, which is the first function on the callstack
+// outside of this package
+func pluginFunc(callstack *logging.Callstack) string {
+	if callstack == nil {
+		return "unknown plugin"
+	}
+	for _, callsite := range callstack.Stack {
+		if !strings.HasPrefix(callsite.FuncName, "github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/pointer.") {
+			return callsite.Func
+		}
+	}
+	return "unknown plugin"
+}
+
+// Returns the file and line of the wiring spec that the callstack was captured from
+func callsiteOf(callstack *logging.Callstack) string {
+	callsite, ok := callstack.WiringCallsite()
+	if !ok || callsite.Source == nil {
+		return "unknown location"
+	}
+	return fmt.Sprintf("%s:%v", callsite.Source.WorkspaceFilename, callsite.LineNumber)
+}
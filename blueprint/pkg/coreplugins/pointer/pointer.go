@@ -30,8 +30,10 @@
 package pointer
 
 import (
+	"sort"
 	"strings"
 
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint/logging"
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/address"
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
@@ -53,6 +55,12 @@ type PointerDef struct {
 
 	dstHead      string
 	dstModifiers []string
+
+	// Where each modifier was added; used when reporting errors
+	callsites map[string]*logging.Callstack
+
+	// The options that each modifier was added with
+	options map[string]ModifierOpts
 }
 
 func (ptr PointerDef) String() string {
@@ -77,8 +85,15 @@ type PointerOpts struct {
 // Additional options that can be specified when adding a modifier to a pointer.
 // If not specified, defaults are used.
 type ModifierOpts struct {
-	// Defaults to true.  When true, the pointer's interface node is updated
+	// Defaults to true.  When true, the pointer's interface node is updated.  Only used by [AddDstModifier]
 	IsInterfaceNode bool
+
+	// The categories that the modifier belongs to, such as [RPC].  Used by other modifiers' ordering constraints
+	Categories []string
+
+	// Categories of modifier that this modifier must be added before.  If a modifier of one of these
+	// categories has already been added to the pointer, on either side, then adding this modifier is an error.
+	Before []string
 }
 
 var defaultPointerOpts = PointerOpts{
@@ -119,6 +134,8 @@ func CreatePointer[SrcNodeType any](spec wiring.WiringSpec, name string, dst str
 	ptr.interfaceNode = dst
 	ptr.dstHead = dst
 	ptr.dstModifiers = []string{dst}
+	ptr.callsites = make(map[string]*logging.Callstack)
+	ptr.options = make(map[string]ModifierOpts)
 
 	spec.Alias(ptr.srcTail, ptr.interfaceNode)
 
@@ -147,6 +164,40 @@ func GetPointer(spec wiring.WiringSpec, name string) *PointerDef {
 	return ptr
 }
 
+// Returns the names of every pointer defined in the wiring spec, sorted
+func GetAllPointers(spec wiring.WiringSpec) []string {
+	var names []string
+	for _, name := range spec.Defs() {
+		if GetPointer(spec, name) != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// The name of the pointer
+func (ptr *PointerDef) Name() string {
+	return ptr.name
+}
+
+// Returns the client side modifiers of the pointer, in the order that they were added.
+func (ptr *PointerDef) SrcModifiers() []string {
+	return append([]string{}, ptr.srcModifiers...)
+}
+
+// Returns the server side modifiers of the pointer, outermost first.  The last
+// modifier is the pointer's original destination.
+func (ptr *PointerDef) DstModifiers() []string {
+	return append([]string{}, ptr.dstModifiers...)
+}
+
+// Returns the callstack that was captured when modifierName was added to the pointer,
+// or nil if modifierName isn't a modifier of the pointer.
+func (ptr *PointerDef) ModifierCallstack(modifierName string) *logging.Callstack {
+	return ptr.callsites[modifierName]
+}
+
 // Appends a modifier node called modifierName to the client side modifiers of a pointer.
 //
 // Plugins use this method if they want to wrap the client side of a service, for example
@@ -155,13 +206,25 @@ func GetPointer(spec wiring.WiringSpec, name string) *PointerDef {
 // A pointer can have multiple modifiers applied to it.  They will be applied in the order
 // that AddSrcModifier was called.
 //
+// Ordering constraints on the modifier can be specified with optional [ModifierOpts]; if they are
+// violated, an error is added to the wiring spec.
+//
 // The return value of AddSrcModifier is the name of the _next_ client side modifier.  This
 // can be used within the BuildFunc of modifierName.
-func (ptr *PointerDef) AddSrcModifier(spec wiring.WiringSpec, modifierName string) string {
+func (ptr *PointerDef) AddSrcModifier(spec wiring.WiringSpec, modifierName string, options ...ModifierOpts) string {
+	opts := defaultModifierOpts
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	callstack := logging.GetCallstack()
+	ptr.checkConstraints(spec, modifierName, opts, callstack)
+
 	spec.Alias(ptr.srcTail, modifierName)
 	ptr.srcTail = modifierName + ".ptr.src.next"
 	spec.Alias(ptr.srcTail, ptr.interfaceNode)
 	ptr.srcModifiers = append(ptr.srcModifiers, modifierName)
+	ptr.callsites[modifierName] = callstack
+	ptr.options[modifierName] = opts
 
 	return ptr.srcTail
 }
@@ -174,6 +237,9 @@ func (ptr *PointerDef) AddSrcModifier(spec wiring.WiringSpec, modifierName strin
 // A pointer can have multiple modifiers applied to it.  They will be applied in the order
 // that AddDstModifier was caleld.
 //
+// Ordering constraints on the modifier can be specified with optional [ModifierOpts]; if they are
+// violated, an error is added to the wiring spec.
+//
 // The return value of AddDstModifier is the name of the _previous_ server side modifier.  This
 // can be used within the BuildFunc of modifierName.
 func (ptr *PointerDef) AddDstModifier(spec wiring.WiringSpec, modifierName string, options ...ModifierOpts) string {
@@ -181,6 +247,9 @@ func (ptr *PointerDef) AddDstModifier(spec wiring.WiringSpec, modifierName strin
 	if len(options) > 0 {
 		opts = options[0]
 	}
+	callstack := logging.GetCallstack()
+	ptr.checkConstraints(spec, modifierName, opts, callstack)
+
 	nextDst := ptr.dstHead
 	ptr.dstHead = modifierName
 	if opts.IsInterfaceNode {
@@ -188,6 +257,8 @@ func (ptr *PointerDef) AddDstModifier(spec wiring.WiringSpec, modifierName strin
 		spec.Alias(ptr.srcTail, ptr.interfaceNode)
 	}
 	ptr.dstModifiers = append([]string{ptr.dstHead}, ptr.dstModifiers...)
+	ptr.callsites[modifierName] = callstack
+	ptr.options[modifierName] = opts
 	return nextDst
 }
 
@@ -205,8 +276,8 @@ func (ptr *PointerDef) AddAddrModifier(spec wiring.WiringSpec, addrName string)
 		return ""
 	}
 
-	// Add a modifier to instantiate address PointsTo
-	nextDst := ptr.AddDstModifier(spec, def.PointsTo)
+	// Add a modifier to instantiate address PointsTo.  Beyond the address, the pointer is outside the process boundary
+	nextDst := ptr.AddDstModifier(spec, def.PointsTo, RPCModifier)
 
 	// Set the pointer interface to be the address, rather than the node
 	ptr.interfaceNode = addrName
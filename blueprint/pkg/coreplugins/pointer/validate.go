@@ -0,0 +1,56 @@
+package pointer
+
+import (
+	"sort"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint/logging"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+)
+
+func init() {
+	wiring.RegisterValidator("pointer", validatePointers)
+}
+
+// Checks every pointer in the wiring spec for a destination that doesn't exist, or for
+// modifiers that were added to the pointer but never defined.
+func validatePointers(spec wiring.WiringSpec, report *wiring.ValidationReport) {
+	names := spec.Defs()
+	sort.Strings(names)
+	for _, name := range names {
+		ptr := GetPointer(spec, name)
+		if ptr == nil {
+			continue
+		}
+		var callstack *logging.Callstack
+		if def := spec.GetDef(name); def != nil {
+			callstack = def.Callstack()
+		}
+
+		dst := resolveAliases(spec, ptr.dstModifiers[len(ptr.dstModifiers)-1])
+		if spec.GetDef(dst) == nil && !report.Has(dst) {
+			report.Errorf(dst, callstack, "pointer %s has no destination because %s is not defined", name, dst)
+		}
+
+		for _, modifier := range append(append([]string{}, ptr.srcModifiers...), ptr.dstModifiers[:len(ptr.dstModifiers)-1]...) {
+			if spec.GetDef(modifier) == nil && !report.Has(modifier) {
+				report.Errorf(modifier, ptr.callsites[modifier], "%s was added as a modifier to pointer %s but is not defined", modifier, name)
+			}
+		}
+	}
+}
+
+// Follows aliases to the name they ultimately point to.  Loops are reported by the wiring spec itself.
+func resolveAliases(spec wiring.WiringSpec, name string) string {
+	seen := make(map[string]struct{})
+	for {
+		next, isAlias := spec.GetAlias(name)
+		if !isAlias {
+			return name
+		}
+		if _, loop := seen[name]; loop {
+			return name
+		}
+		seen[name] = struct{}{}
+		name = next
+	}
+}
@@ -6,21 +6,25 @@ import (
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
 )
 
+func init() {
+	ir.RegisterJSONType[*visibilityMetadata]()
+}
+
 // Metadata used to enforce reachability constraints for nodes (primarily services)
 type visibilityMetadata struct {
 	ir.IRMetadata
 
-	name      string
-	node      ir.IRNode
-	namespace wiring.Namespace
+	InstanceName string
+	node         ir.IRNode        `json:"-"` // Only used while building
+	namespace    wiring.Namespace `json:"-"` // Only used while building
 }
 
 func (md *visibilityMetadata) Name() string {
-	return md.name
+	return md.InstanceName
 }
 
 func (md *visibilityMetadata) String() string {
-	return md.name
+	return md.InstanceName
 }
 
 /*
@@ -48,7 +52,7 @@ func RequireUniqueness(spec wiring.WiringSpec, alias string, visibility any) {
 	mdName := name + ".visibility"
 	spec.Define(mdName, visibility, func(namespace wiring.Namespace) (ir.IRNode, error) {
 		md := &visibilityMetadata{}
-		md.name = mdName
+		md.InstanceName = mdName
 		md.node = nil
 		md.namespace = nil
 		return md, nil
@@ -1,5 +1,7 @@
 package ir
 
+import "sync"
+
 type (
 	// A Blueprint application can potentially have multiple IR node instances spread across the application
 	// that generate the same code.
@@ -20,12 +22,19 @@ type (
 	}
 )
 
-// Basic implementation of the [VisitTracker] interface
+// Basic implementation of the [VisitTracker] interface.  It is safe for concurrent use, because
+// the children of a namespace can be generated concurrently; see [RunConcurrently].
 type VisitTrackerImpl struct {
 	visited map[string]any
 }
 
+// Guards the visited maps of all VisitTrackerImpls.  Visited is cheap, so one lock suffices, and
+// VisitTrackerImpl can still be embedded and copied by value.
+var visitedLock sync.Mutex
+
 func (tracker *VisitTrackerImpl) Visited(name string) bool {
+	visitedLock.Lock()
+	defer visitedLock.Unlock()
 	if tracker.visited == nil {
 		tracker.visited = make(map[string]any)
 	}
@@ -2,9 +2,12 @@ package ir
 
 import (
 	"fmt"
-	"os"
 	"reflect"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint/ioutil"
@@ -47,6 +50,18 @@ func RegisterDefaultNamespace[T IRNode](name string, buildFunc func(outputDir st
 	slog.Info(fmt.Sprintf("%v registered as the default namespace builder for %v nodes", name, nodeType))
 }
 
+// Returns a func that restores the default namespace builders to those that are registered now.  This
+// allows tests that register default namespace builders to undo the registration afterwards.
+func SaveDefaultNamespaces() (restore func()) {
+	saved := make(map[reflect.Type]*namespaceBuilder, len(defaultBuilders.namespace))
+	for nodeType, builder := range defaultBuilders.namespace {
+		saved[nodeType] = builder
+	}
+	return func() {
+		defaultBuilders.namespace = saved
+	}
+}
+
 func (r *registry) addNamespaceBuilder(name string, nodeType reflect.Type, buildFunc func(outputDir string, nodes []IRNode) error) {
 	r.namespace[nodeType] = &namespaceBuilder{
 		builder: builder{
@@ -62,10 +77,8 @@ func (b *builder) builds(node IRNode) bool {
 	return reflect.TypeOf(node).AssignableTo(b.nodeType)
 }
 
-func (b *namespaceBuilder) buildCompatibleNodes(outputDir string, nodes []IRNode) ([]IRNode, error) {
-	// Find compatible nodes
-	toBuild := make([]IRNode, 0, len(nodes))
-	remaining := make([]IRNode, 0, len(nodes))
+// Splits nodes into those that this builder can build and those that it can't
+func (b *namespaceBuilder) compatibleNodes(nodes []IRNode) (toBuild []IRNode, remaining []IRNode) {
 	for _, node := range nodes {
 		if b.builds(node) {
 			toBuild = append(toBuild, node)
@@ -73,69 +86,147 @@ func (b *namespaceBuilder) buildCompatibleNodes(outputDir string, nodes []IRNode
 			remaining = append(remaining, node)
 		}
 	}
+	return toBuild, remaining
+}
 
-	// Build them
-	if len(toBuild) > 0 {
-		if err := b.build(outputDir, toBuild); err != nil {
-			return nil, err
-		}
+// The maximum number of namespace builders and artifact generators that run concurrently
+// when building an application.
+var buildParallelism = runtime.GOMAXPROCS(0)
+
+// Sets the maximum number of namespace builders and artifact generators that can run concurrently
+// when building an application.  Each runs in its own output subdirectory, so the generated
+// artifacts are the same regardless of parallelism.  A value less than 1 builds sequentially.
+//
+// Defaults to GOMAXPROCS.
+func SetBuildParallelism(n int) {
+	if n < 1 {
+		n = 1
 	}
-	return remaining, nil
+	buildParallelism = n
 }
 
-func buildArtifactGeneratorNodes(outputdir string, nodes []IRNode) ([]IRNode, error) {
-	remaining := make([]IRNode, 0, len(nodes))
-	for _, node := range nodes {
-		if gen, isGen := node.(ArtifactGenerator); isGen {
-			subdir, err := ioutil.CreateNodeDir(outputdir, node.Name())
-			if err != nil {
-				return nil, err
-			}
+// Returns the maximum number of namespace builders and artifact generators that can run concurrently;
+// see [SetBuildParallelism].
+func BuildParallelism() int {
+	return buildParallelism
+}
 
-			if err := gen.GenerateArtifacts(subdir); err != nil {
-				return nil, err
-			}
-		} else {
-			remaining = append(remaining, node)
-		}
+// Generates artifacts for all nodes to outputDir.  Artifacts are generated incrementally: only files
+// whose contents changed since the previous generation are rewritten, and files from nodes that no
+// longer exist are removed.  See [ioutil.GenerateIncrementally].
+func (r *registry) buildAll(outputDir string, nodes []IRNode) error {
+	stats, err := ioutil.GenerateIncrementally(outputDir, func(stagingDir string) error {
+		return r.generate(stagingDir, nodes)
+	})
+	if err != nil {
+		return err
 	}
-	return remaining, nil
+	slog.Info(fmt.Sprintf("Wrote %v artifacts to %v (%v unchanged, %v removed)", len(stats.Written), outputDir, len(stats.Unchanged), len(stats.Removed)))
+	return nil
 }
 
-func (r *registry) buildAll(outputDir string, nodes []IRNode) (err error) {
-	// Create output directory
-	if info, err := os.Stat(outputDir); err == nil && info.IsDir() {
-		return blueprint.Errorf("output directory %v already exists", outputDir)
-	}
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return blueprint.Errorf("unable to create output directory %v due to %v", outputDir, err.Error())
-	}
+func (r *registry) generate(outputDir string, nodes []IRNode) error {
+	var tasks []func() error
 
-	// Try to group like-nodes into namespaces first
+	// Try to group like-nodes into namespaces first.  Builders are visited in a fixed order so
+	// that a node compatible with more than one builder is always built by the same one.
+	builderNames := make([]string, 0, len(r.namespace))
+	buildersByName := make(map[string]*namespaceBuilder)
 	for _, builder := range r.namespace {
-		nodes, err = builder.buildCompatibleNodes(outputDir, nodes)
-		if err != nil {
-			return err
+		builderNames = append(builderNames, builder.name)
+		buildersByName[builder.name] = builder
+	}
+	sort.Strings(builderNames)
+	for _, name := range builderNames {
+		builder := buildersByName[name]
+		var toBuild []IRNode
+		toBuild, nodes = builder.compatibleNodes(nodes)
+		if len(toBuild) > 0 {
+			tasks = append(tasks, func() error { return builder.build(outputDir, toBuild) })
 		}
 	}
 
 	// Remaining nodes can be built individually
-	nodes, err = buildArtifactGeneratorNodes(outputDir, nodes)
-	if err != nil {
-		return err
+	remaining := make([]IRNode, 0, len(nodes))
+	for _, node := range nodes {
+		if gen, isGen := node.(ArtifactGenerator); isGen {
+			name := node.Name()
+			tasks = append(tasks, func() error {
+				subdir, err := ioutil.CreateNodeDir(outputDir, name)
+				if err != nil {
+					return err
+				}
+				return gen.GenerateArtifacts(subdir)
+			})
+		} else {
+			remaining = append(remaining, node)
+		}
 	}
 
-	if len(nodes) > 0 {
+	if len(remaining) > 0 {
 		unbuiltTypes := make(map[reflect.Type]struct{})
-		for _, node := range nodes {
+		for _, node := range remaining {
 			unbuiltTypes[reflect.TypeOf(node)] = struct{}{}
 		}
 		typeNames := []string{}
 		for t := range unbuiltTypes {
 			typeNames = append(typeNames, t.String())
 		}
+		sort.Strings(typeNames)
 		// This should probably be a warning in general
 		return blueprint.Errorf("No registered builders for node types %s", strings.Join(typeNames, ", "))
 	}
+
+	return runTasks(tasks, buildParallelism)
+}
+
+// Runs tasks concurrently, with at most the build parallelism (see [SetBuildParallelism]) running at once.
+//
+// Plugins whose artifacts contain independent children, such as a container of processes or a deployment of
+// containers, can use this to generate their children concurrently.  Tasks must only interact through
+// thread-safe state, e.g. by writing to distinct output directories.  If any tasks fail, the error of the
+// earliest failed task is returned.
+func RunConcurrently(tasks []func() error) error {
+	return runTasks(tasks, buildParallelism)
+}
+
+// Runs tasks with at most parallelism running at once.  Once a task fails, no further tasks are
+// started.  If any tasks fail, the error of the earliest failed task is returned, which is the same
+// error that running the tasks sequentially would return.
+func runTasks(tasks []func() error, parallelism int) error {
+	if parallelism <= 1 {
+		for _, task := range tasks {
+			if err := task(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	errs := make([]error, len(tasks))
+	sem := make(chan struct{}, parallelism)
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		sem <- struct{}{}
+		if failed.Load() {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(i int, task func() error) {
+			defer func() { <-sem; wg.Done() }()
+			if errs[i] = task(); errs[i] != nil {
+				failed.Store(true)
+			}
+		}(i, task)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
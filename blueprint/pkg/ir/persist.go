@@ -0,0 +1,744 @@
+package ir
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+)
+
+// The version of the JSON document written by [WriteJSON].  [ReadJSON] only reads documents of this version.
+const JSONVersion = 1
+
+/*
+The JSON document written by [WriteJSON].  Every IR node is written once to Nodes, and is referenced
+elsewhere by its ID, e.g.
+
+	{
+	  "version": 1,
+	  "application": "leaf_app",
+	  "root": 1,
+	  "nodes": [
+	    {"id": 1, "kind": "*github.com/blueprint-uservices/blueprint/blueprint/pkg/ir.ApplicationNode", "name": "leaf_app",
+	      "properties": {"ApplicationName": "leaf_app"},
+	      "children": [2],
+	      "edges": {"Children": [2]}
+	    },
+	    ...
+	  ]
+	}
+
+See [JSONNode] for how each node is written.
+*/
+type JSONDocument struct {
+	Version     int        `json:"version"`
+	Application string     `json:"application"`
+	Root        int        `json:"root"` // The ID of the [ApplicationNode]
+	Nodes       []JSONNode `json:"nodes"`
+}
+
+/*
+An IR node within a [JSONDocument].
+
+The kind of a node is its Go type, which must be registered with [RegisterJSONType] to be read.
+The state of a node is its exported fields, which are written as either properties or edges:
+
+  - Edges are the fields whose type is an IR node, or a slice, array, or map with string keys of IR nodes.
+    Each IR node in an edge is written as its ID.
+  - Properties are all other fields.  Basic values are written as JSON values; structs, slices, and maps
+    with string keys as JSON objects and arrays; pointers as the value they point to; and values of interface
+    type as {"$type": type, "$value": value}.  Types that should not be written field by field can register
+    a codec with [RegisterJSONCodec].
+
+Fields with zero values are omitted, as are fields tagged `json:"-"`, which IR nodes can use for state that
+is only needed while the IR is being built.  A node cannot be written if any of its other unexported fields
+are set; the fields of embedded structs are written as if they were fields of the node itself.
+
+Children are the IDs of the nodes returned by the node's GetNodes, if it implements [HasIRChildren].  Since
+the children are also in the node's edges, children are informational, for tools that consume the document,
+and are ignored by [ReadJSON], as is the node's name.
+*/
+type JSONNode struct {
+	ID         int            `json:"id"`
+	Kind       string         `json:"kind"`
+	Name       string         `json:"name"`
+	Properties map[string]any `json:"properties,omitempty"`
+	Children   []int          `json:"children,omitempty"`
+	Edges      map[string]any `json:"edges,omitempty"`
+}
+
+type jsonCodec struct {
+	encode func(reflect.Value) (any, error)
+	decode func(json.RawMessage) (reflect.Value, error)
+}
+
+var (
+	jsonTypes  = make(map[string]reflect.Type)
+	jsonCodecs = make(map[reflect.Type]jsonCodec)
+	irNodeType = reflect.TypeOf((*IRNode)(nil)).Elem()
+)
+
+func init() {
+	for _, v := range []any{false, "", int(0), int8(0), int16(0), int32(0), int64(0), uint(0), uint8(0), uint16(0), uint32(0), uint64(0), float32(0), float64(0)} {
+		jsonTypes[jsonTypeName(reflect.TypeOf(v))] = reflect.TypeOf(v)
+	}
+	RegisterJSONType[*ApplicationNode]()
+	RegisterJSONType[*IRValue]()
+}
+
+// Registers type T so that it can be read by [ReadJSON], either as the kind of an IR node or as the concrete type
+// of a property of interface type.  Plugins should register all of their IR node types, and any other types that
+// their IR nodes store in fields of interface type, in an init function, e.g.
+//
+//	func init() {
+//		ir.RegisterJSONType[*MyNode]()
+//	}
+//
+// Registering *T also registers T.
+func RegisterJSONType[T any]() {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	jsonTypes[jsonTypeName(t)] = t
+}
+
+// Registers custom JSON encode and decode functions for properties of type T.  This is for types that should not
+// be written field by field, typically because they reference external state such as parsed source code; instead,
+// encode can write a reference to the value, and decode can look the value up again.
+func RegisterJSONCodec[T any](encode func(T) (any, error), decode func(json.RawMessage) (T, error)) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	jsonCodecs[t] = jsonCodec{
+		encode: func(v reflect.Value) (any, error) {
+			return encode(v.Interface().(T))
+		},
+		decode: func(data json.RawMessage) (reflect.Value, error) {
+			v, err := decode(data)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(&v).Elem(), nil
+		},
+	}
+	RegisterJSONType[T]()
+}
+
+// Writes the IR of app to w as a [JSONDocument].
+func WriteJSON(w io.Writer, app *ApplicationNode) error {
+	doc, err := EncodeJSON(app)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// Writes the IR of app as a [JSONDocument] to the file filename.
+func SaveJSON(filename string, app *ApplicationNode) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return blueprint.Errorf("unable to save IR to %v due to %v", filename, err.Error())
+	}
+	defer f.Close()
+	if err := WriteJSON(f, app); err != nil {
+		return blueprint.Errorf("unable to save IR to %v due to %v", filename, err.Error())
+	}
+	return nil
+}
+
+// Reads an application's IR from a [JSONDocument] previously written by [WriteJSON].  The kinds of IR nodes, and
+// the concrete types of properties of interface type, must have been registered with [RegisterJSONType] or
+// [RegisterJSONCodec]; importing the plugins used by the application is sufficient to register their IR node types.
+func ReadJSON(r io.Reader) (*ApplicationNode, error) {
+	var doc struct {
+		Version     int    `json:"version"`
+		Application string `json:"application"`
+		Root        int    `json:"root"`
+		Nodes       []struct {
+			ID         int                        `json:"id"`
+			Kind       string                     `json:"kind"`
+			Properties map[string]json.RawMessage `json:"properties"`
+			Edges      map[string]json.RawMessage `json:"edges"`
+		} `json:"nodes"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, blueprint.Errorf("unable to read IR due to %v", err.Error())
+	}
+	if doc.Version != JSONVersion {
+		return nil, blueprint.Errorf("unable to read IR of %v with version %v; only version %v is supported", doc.Application, doc.Version, JSONVersion)
+	}
+
+	// Nodes can refer to each other in any order, so every node is created before any are decoded
+	d := &jsonDecoder{nodes: make(map[int]reflect.Value)}
+	for _, node := range doc.Nodes {
+		t, err := lookupJSONType(node.Kind)
+		if err != nil {
+			return nil, blueprint.Errorf("unable to read IR of %v due to node %v: %v", doc.Application, node.ID, err.Error())
+		}
+		if t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct || !t.Implements(irNodeType) {
+			return nil, blueprint.Errorf("unable to read IR of %v as node %v has kind %v, which is not an IR node", doc.Application, node.ID, node.Kind)
+		}
+		if _, exists := d.nodes[node.ID]; exists {
+			return nil, blueprint.Errorf("unable to read IR of %v as there is more than one node %v", doc.Application, node.ID)
+		}
+		d.nodes[node.ID] = reflect.New(t.Elem())
+	}
+	for _, node := range doc.Nodes {
+		v := d.nodes[node.ID]
+		if err := d.decodeNode(v.Elem(), node.Properties, node.Edges); err != nil {
+			return nil, blueprint.Errorf("unable to read IR of %v due to node %v: %v: %v", doc.Application, node.ID, node.Kind, err.Error())
+		}
+	}
+
+	root, exists := d.nodes[doc.Root]
+	if !exists {
+		return nil, blueprint.Errorf("unable to read IR of %v as it has no root", doc.Application)
+	}
+	app, isApp := root.Interface().(*ApplicationNode)
+	if !isApp {
+		return nil, blueprint.Errorf("unable to read IR of %v as its root is a %v, not an application", doc.Application, root.Type())
+	}
+	return app, nil
+}
+
+// Reads an application's IR from the file filename; see [ReadJSON].
+func LoadJSON(filename string) (*ApplicationNode, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, blueprint.Errorf("unable to load IR from %v due to %v", filename, err.Error())
+	}
+	defer f.Close()
+	return ReadJSON(f)
+}
+
+// Converts the IR of app into a [JSONDocument], without writing it.
+func EncodeJSON(app *ApplicationNode) (*JSONDocument, error) {
+	e := &jsonEncoder{ids: make(map[IRNode]int)}
+	root, err := e.encodeNode(app)
+	if err != nil {
+		return nil, blueprint.Errorf("unable to encode IR of %v due to %v", app.Name(), err.Error())
+	}
+	return &JSONDocument{
+		Version:     JSONVersion,
+		Application: app.Name(),
+		Root:        root,
+		Nodes:       e.nodes,
+	}, nil
+}
+
+type jsonEncoder struct {
+	ids   map[IRNode]int
+	nodes []JSONNode
+}
+
+// Returns the ID of node, writing the node if this is the first reference to it
+func (e *jsonEncoder) encodeNode(node IRNode) (int, error) {
+	v := reflect.ValueOf(node)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return 0, fmt.Errorf("IR node %v has type %v; only pointers to structs can be encoded", node.Name(), v.Type())
+	}
+	if id, exists := e.ids[node]; exists {
+		return id, nil
+	}
+
+	// Record the node before encoding its fields, as its fields might refer back to it
+	id := len(e.nodes) + 1
+	e.ids[node] = id
+	e.nodes = append(e.nodes, JSONNode{ID: id, Kind: jsonTypeName(v.Type()), Name: node.Name()})
+
+	properties := make(map[string]any)
+	edges := make(map[string]any)
+	err := forEachField(v.Elem(), func(name string, field reflect.Value) error {
+		if isEdge(field.Type()) {
+			edge, err := e.encodeEdge(field)
+			if err != nil {
+				return fmt.Errorf("%v: %v", name, err.Error())
+			}
+			edges[name] = edge
+			return nil
+		}
+		property, err := e.encodeProperty(field)
+		if err != nil {
+			return fmt.Errorf("%v: %v", name, err.Error())
+		}
+		properties[name] = property
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%v %v: %v", jsonTypeName(v.Type()), node.Name(), err.Error())
+	}
+
+	var children []int
+	if parent, hasChildren := node.(HasIRChildren); hasChildren {
+		for _, child := range parent.GetNodes() {
+			childID, err := e.encodeNode(child)
+			if err != nil {
+				return 0, err
+			}
+			children = append(children, childID)
+		}
+	}
+
+	e.nodes[id-1].Properties = properties
+	e.nodes[id-1].Edges = edges
+	e.nodes[id-1].Children = children
+	if len(properties) == 0 {
+		e.nodes[id-1].Properties = nil
+	}
+	if len(edges) == 0 {
+		e.nodes[id-1].Edges = nil
+	}
+	return id, nil
+}
+
+// Encodes an edge as the ID of the IR node, or as a list or map of IDs
+func (e *jsonEncoder) encodeEdge(v reflect.Value) (any, error) {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+		ids := make([]any, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			id, err := e.encodeEdge(v.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("[%v]: %v", i, err.Error())
+			}
+			ids[i] = id
+		}
+		return ids, nil
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		ids := make(map[string]any, v.Len())
+		for _, key := range v.MapKeys() {
+			id, err := e.encodeEdge(v.MapIndex(key))
+			if err != nil {
+				return nil, fmt.Errorf("[%v]: %v", key.String(), err.Error())
+			}
+			ids[key.String()] = id
+		}
+		return ids, nil
+
+	default:
+		if isNil(v) {
+			return nil, nil
+		}
+		return e.encodeNode(v.Interface().(IRNode))
+	}
+}
+
+func (e *jsonEncoder) encodeProperty(v reflect.Value) (any, error) {
+	if codec, hasCodec := jsonCodecs[v.Type()]; hasCodec {
+		if isNil(v) {
+			return nil, nil
+		}
+		return codec.encode(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return basicValue(v), nil
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return e.encodeProperty(v.Elem())
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Elem().Type().Implements(irNodeType) {
+			return nil, fmt.Errorf("IR node %v is stored as a %v; IR nodes can only be stored in fields of IR node type", v.Elem().Interface().(IRNode).Name(), v.Type())
+		}
+		value, err := e.encodeProperty(v.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"$type": jsonTypeName(v.Elem().Type()), "$value": value}, nil
+
+	case reflect.Struct:
+		fields := make(map[string]any)
+		err := forEachField(v, func(name string, field reflect.Value) error {
+			if isEdge(field.Type()) {
+				return fmt.Errorf("%v: IR nodes can only be stored in fields of IR nodes, not of %v", name, v.Type())
+			}
+			value, err := e.encodeProperty(field)
+			if err != nil {
+				return fmt.Errorf("%v: %v", name, err.Error())
+			}
+			fields[name] = value
+			return nil
+		})
+		return fields, err
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+		values := make([]any, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			value, err := e.encodeProperty(v.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("[%v]: %v", i, err.Error())
+			}
+			values[i] = value
+		}
+		return values, nil
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unable to encode map of type %v as its keys are not strings", v.Type())
+		}
+		values := make(map[string]any, v.Len())
+		for _, key := range v.MapKeys() {
+			value, err := e.encodeProperty(v.MapIndex(key))
+			if err != nil {
+				return nil, fmt.Errorf("[%v]: %v", key.String(), err.Error())
+			}
+			values[key.String()] = value
+		}
+		return values, nil
+
+	default:
+		if isNil(v) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to encode value of type %v", v.Type())
+	}
+}
+
+type jsonDecoder struct {
+	nodes map[int]reflect.Value
+}
+
+// Sets the fields of the IR node v from its properties and edges
+func (d *jsonDecoder) decodeNode(v reflect.Value, properties, edges map[string]json.RawMessage) error {
+	fields := fieldIndices(v.Type())
+	for name, data := range properties {
+		index, exists := fields[name]
+		if !exists || isEdge(v.Type().FieldByIndex(index).Type) {
+			return fmt.Errorf("%v has no property %v", v.Type(), name)
+		}
+		value, err := d.decodeProperty(data, v.Type().FieldByIndex(index).Type)
+		if err != nil {
+			return fmt.Errorf("%v: %v", name, err.Error())
+		}
+		v.FieldByIndex(index).Set(value)
+	}
+	for name, data := range edges {
+		index, exists := fields[name]
+		if !exists || !isEdge(v.Type().FieldByIndex(index).Type) {
+			return fmt.Errorf("%v has no edge %v", v.Type(), name)
+		}
+		value, err := d.decodeEdge(data, v.Type().FieldByIndex(index).Type)
+		if err != nil {
+			return fmt.Errorf("%v: %v", name, err.Error())
+		}
+		v.FieldByIndex(index).Set(value)
+	}
+	return nil
+}
+
+// Decodes the IDs of an edge into the IR nodes of type t that they refer to
+func (d *jsonDecoder) decodeEdge(data json.RawMessage, t reflect.Type) (reflect.Value, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return reflect.Zero(t), nil
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		var ids []json.RawMessage
+		if err := json.Unmarshal(data, &ids); err != nil {
+			return reflect.Value{}, err
+		}
+		var v reflect.Value
+		if t.Kind() == reflect.Slice {
+			v = reflect.MakeSlice(t, len(ids), len(ids))
+		} else if len(ids) != t.Len() {
+			return reflect.Value{}, fmt.Errorf("expected %v nodes for %v but got %v", t.Len(), t, len(ids))
+		} else {
+			v = reflect.New(t).Elem()
+		}
+		for i, id := range ids {
+			node, err := d.decodeEdge(id, t.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("[%v]: %v", i, err.Error())
+			}
+			v.Index(i).Set(node)
+		}
+		return v, nil
+
+	case reflect.Map:
+		var ids map[string]json.RawMessage
+		if err := json.Unmarshal(data, &ids); err != nil {
+			return reflect.Value{}, err
+		}
+		v := reflect.MakeMapWithSize(t, len(ids))
+		for key, id := range ids {
+			node, err := d.decodeEdge(id, t.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("[%v]: %v", key, err.Error())
+			}
+			v.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), node)
+		}
+		return v, nil
+
+	default:
+		var id int
+		if err := json.Unmarshal(data, &id); err != nil {
+			return reflect.Value{}, err
+		}
+		node, exists := d.nodes[id]
+		if !exists {
+			return reflect.Value{}, fmt.Errorf("reference to unknown node %v", id)
+		}
+		if !node.Type().AssignableTo(t) {
+			return reflect.Value{}, fmt.Errorf("node %v is a %v, which is not a %v", id, jsonTypeName(node.Type()), t)
+		}
+		v := reflect.New(t).Elem()
+		v.Set(node)
+		return v, nil
+	}
+}
+
+// Decodes a property into a new value of type t
+func (d *jsonDecoder) decodeProperty(data json.RawMessage, t reflect.Type) (reflect.Value, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return reflect.Zero(t), nil
+	}
+	if codec, hasCodec := jsonCodecs[t]; hasCodec {
+		return codec.decode(data)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		v := reflect.New(t)
+		if err := json.Unmarshal(data, v.Interface()); err != nil {
+			return reflect.Value{}, err
+		}
+		return v.Elem(), nil
+
+	case reflect.Pointer:
+		elem, err := d.decodeProperty(data, t.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v := reflect.New(t.Elem())
+		v.Elem().Set(elem)
+		return v, nil
+
+	case reflect.Interface:
+		var value struct {
+			Type  string          `json:"$type"`
+			Value json.RawMessage `json:"$value"`
+		}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return reflect.Value{}, err
+		}
+		concreteType, err := lookupJSONType(value.Type)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if !concreteType.AssignableTo(t) {
+			return reflect.Value{}, fmt.Errorf("%v does not implement %v", value.Type, t)
+		}
+		concrete, err := d.decodeProperty(value.Value, concreteType)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v := reflect.New(t).Elem()
+		v.Set(concrete)
+		return v, nil
+
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return reflect.Value{}, err
+		}
+		v := reflect.New(t).Elem()
+		indices := fieldIndices(t)
+		for name, data := range fields {
+			index, exists := indices[name]
+			if !exists {
+				return reflect.Value{}, fmt.Errorf("%v has no field %v", t, name)
+			}
+			value, err := d.decodeProperty(data, t.FieldByIndex(index).Type)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("%v: %v", name, err.Error())
+			}
+			v.FieldByIndex(index).Set(value)
+		}
+		return v, nil
+
+	case reflect.Slice, reflect.Array:
+		var values []json.RawMessage
+		if err := json.Unmarshal(data, &values); err != nil {
+			return reflect.Value{}, err
+		}
+		var v reflect.Value
+		if t.Kind() == reflect.Slice {
+			v = reflect.MakeSlice(t, len(values), len(values))
+		} else if len(values) != t.Len() {
+			return reflect.Value{}, fmt.Errorf("expected %v values for %v but got %v", t.Len(), t, len(values))
+		} else {
+			v = reflect.New(t).Elem()
+		}
+		for i, data := range values {
+			elem, err := d.decodeProperty(data, t.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("[%v]: %v", i, err.Error())
+			}
+			v.Index(i).Set(elem)
+		}
+		return v, nil
+
+	case reflect.Map:
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(data, &values); err != nil {
+			return reflect.Value{}, err
+		}
+		v := reflect.MakeMapWithSize(t, len(values))
+		for key, data := range values {
+			elem, err := d.decodeProperty(data, t.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("[%v]: %v", key, err.Error())
+			}
+			v.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), elem)
+		}
+		return v, nil
+
+	default:
+		return reflect.Value{}, fmt.Errorf("unable to decode value of type %v", t)
+	}
+}
+
+// Calls f with the name and value of each of the struct's fields that is written; see [JSONNode].  The fields of
+// embedded structs are visited as if they were fields of the struct itself.
+func forEachField(v reflect.Value, f func(name string, field reflect.Value) error) error {
+	for i := 0; i < v.NumField(); i++ {
+		structField := v.Type().Field(i)
+		if structField.Tag.Get("json") == "-" {
+			continue
+		}
+		field := v.Field(i)
+		if structField.Anonymous && structField.Type.Kind() == reflect.Struct {
+			if err := forEachField(field, f); err != nil {
+				return err
+			}
+			continue
+		}
+		if field.IsZero() {
+			continue
+		}
+		if !structField.IsExported() {
+			return fmt.Errorf("unable to encode unexported field %v of %v; it should be exported, or tagged `json:\"-\"` if it is only needed while building the IR", structField.Name, v.Type())
+		}
+		if err := f(structField.Name, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the index of each field of struct type t that [forEachField] visits, by name
+func fieldIndices(t reflect.Type) map[string][]int {
+	indices := make(map[string][]int)
+	var visit func(t reflect.Type, prefix []int)
+	visit = func(t reflect.Type, prefix []int) {
+		for i := 0; i < t.NumField(); i++ {
+			structField := t.Field(i)
+			index := append(append([]int{}, prefix...), i)
+			if structField.Tag.Get("json") == "-" {
+				continue
+			}
+			if structField.Anonymous && structField.Type.Kind() == reflect.Struct {
+				visit(structField.Type, index)
+			} else if structField.IsExported() {
+				indices[structField.Name] = index
+			}
+		}
+	}
+	visit(t, nil)
+	return indices
+}
+
+// Returns true if values of type t are written as edges; see [JSONNode]
+func isEdge(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		return t.Implements(irNodeType)
+	case reflect.Slice, reflect.Array:
+		return isEdge(t.Elem())
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && isEdge(t.Elem())
+	}
+	return false
+}
+
+// The name that a type is written with; the package path and name of named types, and a * prefix for pointers
+func jsonTypeName(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		return "*" + jsonTypeName(t.Elem())
+	}
+	if t.Name() != "" && t.PkgPath() != "" {
+		return t.PkgPath() + "." + t.Name()
+	}
+	return t.String()
+}
+
+func lookupJSONType(name string) (reflect.Type, error) {
+	if strings.HasPrefix(name, "*") {
+		t, err := lookupJSONType(name[1:])
+		if err != nil {
+			return nil, err
+		}
+		return reflect.PointerTo(t), nil
+	}
+	if t, registered := jsonTypes[name]; registered {
+		return t, nil
+	}
+	return nil, fmt.Errorf("type %v is not registered; the plugin that defines it should call ir.RegisterJSONType", name)
+}
+
+// Returns the value of a basic kind as a JSON value, without any named type, so that named types that implement
+// json.Marshaler or fmt.Stringer are still written as their underlying value
+func basicValue(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	default:
+		return v.Uint()
+	}
+}
+
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return v.IsNil()
+	}
+	return false
+}
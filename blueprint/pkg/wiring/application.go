@@ -16,6 +16,12 @@ import (
 // but this might not result in an application with the desired topology.  Hence
 // the recommended approach is to explicitly specify which nodes to instantiate.
 func BuildApplicationIR(spec WiringSpec, name string, nodesToInstantiate ...string) (*ir.ApplicationNode, error) {
+	return buildApplicationIR(spec, name, nil, nodesToInstantiate...)
+}
+
+// Builds the IR of an application.  If tracker is nil, building stops at the first error.  If
+// tracker is non-nil, building continues after errors so that the tracker can record every problem.
+func buildApplicationIR(spec WiringSpec, name string, tracker *buildTracker, nodesToInstantiate ...string) (*ir.ApplicationNode, error) {
 	// Create the root application namespace
 	app := &ir.ApplicationNode{ApplicationName: name}
 
@@ -28,6 +34,7 @@ func BuildApplicationIR(spec WiringSpec, name string, nodesToInstantiate ...stri
 		Seen:            make(map[string]ir.IRNode),
 		Added:           make(map[string]any),
 		ChildNamespaces: make(map[string]Namespace),
+		tracker:         tracker,
 	}
 
 	// If no nodes were specified, then instead we will instantiate all defined nodes
@@ -46,14 +53,22 @@ func BuildApplicationIR(spec WiringSpec, name string, nodesToInstantiate ...stri
 	}
 
 	// Execute deferred functions until empty
+	var firstErr error
 	for len(namespace.Deferred) > 0 {
 		next := namespace.Deferred[0]
 		namespace.Deferred = namespace.Deferred[1:]
-		if err := next(); err != nil {
-			return app, err
+		tracker.reset()
+		if err := tracker.run(next); err != nil {
+			if tracker == nil {
+				return app, err
+			}
+			tracker.deferredFailed(err)
+			if firstErr == nil {
+				firstErr = err
+			}
 		}
 	}
-	return app, nil
+	return app, firstErr
 }
 
 type applicationNamespaceHandler struct {
@@ -106,7 +106,8 @@ type namespaceimpl struct {
 	Deferred        []func() error       // Deferred functions to execute
 	ChildNamespaces map[string]Namespace // Child namespaces
 
-	stack []*WiringDef // Used when building; the stack of wiring defs currently being built
+	stack   []*WiringDef  // Used when building; the stack of wiring defs currently being built
+	tracker *buildTracker // Used when validating; nil otherwise
 }
 
 // NamespaceHandler is an interface intended for use by any Blueprint plugin that wants to
@@ -146,8 +147,10 @@ func (namespace *namespaceimpl) Get(name string, dst any) error {
 func (namespace *namespaceimpl) lookupDef(name string) (*WiringDef, error) {
 	def := namespace.Wiring.GetDef(name)
 	if def == nil {
+		namespace.tracker.missing(namespace, name)
 		return nil, blueprint.Errorf("%s does not exist in the wiring spec of namespace %s", name, namespace.NamespaceName)
 	}
+	namespace.tracker.use(name, def.Name)
 	return def, nil
 }
 
@@ -174,10 +177,10 @@ func (namespace *namespaceimpl) get(name string, addEdge bool, dst any) error {
 		namespace.Info("Resolved %s to %s", name, def.Name)
 		var node ir.IRNode
 		err := namespace.get(def.Name, addEdge, &node)
-		namespace.Seen[name] = node
 		if err != nil {
 			return err
 		}
+		namespace.Seen[name] = node
 		return copyResult(node, dst)
 	}
 
@@ -213,9 +216,16 @@ func (namespace *namespaceimpl) get(name string, addEdge bool, dst any) error {
 		namespace.Info("Building %s (alias %s) of type %s", def.Name, name, reflect.TypeOf(def.NodeType).String())
 	}
 
+	// Nodes that only have properties but were never defined can't be built
+	if def.Build == nil {
+		namespace.tracker.fail()
+		return namespace.Error("%s has properties in the wiring spec but was never defined", name)
+	}
+
 	// Build the node
 	node, err := def.Build(namespace)
 	if err != nil {
+		namespace.tracker.buildFailed(def, err)
 		namespace.Error("Unable to build %v: %s", name, err.Error())
 		return err
 	}
@@ -304,6 +314,7 @@ func (namespace *namespaceimpl) DeriveNamespace(name string, handler NamespaceHa
 		Seen:            make(map[string]ir.IRNode),
 		Added:           make(map[string]any),
 		ChildNamespaces: make(map[string]Namespace),
+		tracker:         namespace.tracker,
 	}
 	namespace.ChildNamespaces[name] = child
 	namespace.Info("Created child namespace %v", name)
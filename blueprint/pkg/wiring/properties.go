@@ -0,0 +1,198 @@
+package wiring
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint/logging"
+	"golang.org/x/exp/slog"
+)
+
+// A typed property key that plugins declare with [DeclareProperty], for storing static values on a
+// [WiringDef].  Compared to [WiringSpec.SetProperty] and [WiringSpec.GetProperty], values are checked
+// when they are set, so that a misconfigured property is reported at the line of the wiring spec that
+// set it, rather than failing or being silently ignored when the node is built.
+//
+// For example, the timeouts plugin declares
+//
+//	var TimeoutProperty = wiring.DeclareProperty(wiring.Property[string]{
+//		Key:      "timeouts.Timeout",
+//		Validate: func(timeout string) error { _, err := time.ParseDuration(timeout); return err },
+//	})
+//
+// Values are stored in [WiringDef.Properties] under Key, so typed and untyped access can be mixed.
+// Since different plugins can set properties on the same node, Key is qualified by the name of the
+// declaring plugin's package.
+type Property[T any] struct {
+	Key      string        // The plugin's package name and the property's name, e.g. timeouts.Timeout
+	Multi    bool          // If true, the property can have more than one value; otherwise it has at most one
+	Default  T             // Returned by [Property.Get] if the property has not been set
+	Validate func(T) error // Optional; checks each value when it is set
+}
+
+// Anything that properties can be read from, such as a [WiringSpec] or a [Namespace]
+type PropertySource interface {
+	GetProperties(name string, key string, dst any) error
+}
+
+// Checks the untyped values of a declared property; used when validating a wiring spec
+type propertyChecker interface {
+	check(name string, values []any) error
+}
+
+var declaredProperties = make(map[string]propertyChecker)
+
+// Declares a typed property and returns it.  The property's key must be qualified by the name of the
+// declaring package, e.g. timeouts.Timeout, so that the keys of different plugins cannot collide.
+//
+// Declared properties are also checked by [WiringSpec.Validate], which reports values that were set
+// with [WiringSpec.SetProperty] or [WiringSpec.AddProperty] and are of the wrong type or invalid.
+func DeclareProperty[T any](property Property[T]) *Property[T] {
+	if pkg := callerPackage(); pkg != "" && !strings.HasPrefix(property.Key, pkg+".") {
+		slog.Warn(fmt.Sprintf("Property %v is declared by package %v, so its key should be qualified, e.g. %v.%v", property.Key, pkg, pkg, property.Key))
+	}
+	if _, exists := declaredProperties[property.Key]; exists {
+		slog.Warn(fmt.Sprintf("Property %v was declared more than once; only the last declaration is validated", property.Key))
+	}
+	declaredProperties[property.Key] = &property
+	return &property
+}
+
+// Returns the name of the package that called the caller of this function, or the empty string if it
+// can't be determined
+func callerPackage() string {
+	pc, _, _, ok := runtime.Caller(2)
+	if !ok {
+		return ""
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+	// Function names are the package path then the function, e.g. github.com/org/plugins/timeouts.init
+	name := fn.Name()
+	path := name[:strings.LastIndex(name, "/")+1]
+	pkg, _, _ := strings.Cut(name[len(path):], ".")
+	return pkg
+}
+
+// Sets the value of the property on name, replacing any existing values.  If the value is invalid,
+// an error is added to the spec and the property is left unchanged.
+func (p *Property[T]) Set(spec WiringSpec, name string, value T) {
+	if err := p.validate(name, value); err != nil {
+		spec.AddError(err)
+		return
+	}
+	spec.SetProperty(name, p.Key, value)
+}
+
+// Adds a value to the property on name.  If the value is invalid, or if the property is single-valued
+// and already has a value, an error is added to the spec and the property is left unchanged.
+func (p *Property[T]) Add(spec WiringSpec, name string, value T) {
+	if err := p.validate(name, value); err != nil {
+		spec.AddError(err)
+		return
+	}
+	if !p.Multi {
+		var existing []any
+		if err := spec.GetProperties(name, p.Key, &existing); err == nil && len(existing) > 0 {
+			spec.AddError(p.errorf("%v property of %v is single-valued, but already has value %v", p.Key, name, existing[0]))
+			return
+		}
+	}
+	spec.AddProperty(name, p.Key, value)
+}
+
+// Gets the value of the property on name, or the property's default if it has not been set.
+// Returns an error if the property has more than one value or a value of the wrong type.
+func (p *Property[T]) Get(src PropertySource, name string) (T, error) {
+	values, err := p.GetAll(src, name)
+	if err != nil || len(values) == 0 {
+		return p.Default, err
+	}
+	if len(values) > 1 && !p.Multi {
+		return p.Default, blueprint.Errorf("%v property of %v is single-valued, but has %v values", p.Key, name, len(values))
+	}
+	return values[0], nil
+}
+
+// Gets all values of the property on name.  Returns an error if any value is of the wrong type.
+func (p *Property[T]) GetAll(src PropertySource, name string) ([]T, error) {
+	var values []any
+	if err := src.GetProperties(name, p.Key, &values); err != nil {
+		return nil, err
+	}
+	typed := make([]T, 0, len(values))
+	for _, value := range values {
+		v, isT := value.(T)
+		if !isT {
+			return nil, blueprint.Errorf("%v property of %v has value %v of type %T, but expected %v", p.Key, name, value, value, p.typeName())
+		}
+		typed = append(typed, v)
+	}
+	return typed, nil
+}
+
+func (p *Property[T]) check(name string, values []any) error {
+	if len(values) > 1 && !p.Multi {
+		return blueprint.Errorf("%v property of %v is single-valued, but has %v values", p.Key, name, len(values))
+	}
+	for _, value := range values {
+		v, isT := value.(T)
+		if !isT {
+			return blueprint.Errorf("%v property of %v has value %v of type %T, but expected %v", p.Key, name, value, value, p.typeName())
+		}
+		if p.Validate != nil {
+			if err := p.Validate(v); err != nil {
+				return blueprint.Errorf("invalid %v property of %v: %v", p.Key, name, err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Property[T]) validate(name string, value T) error {
+	if p.Validate == nil {
+		return nil
+	}
+	if err := p.Validate(value); err != nil {
+		return p.errorf("invalid %v property %v of %v: %v", p.Key, value, name, err.Error())
+	}
+	return nil
+}
+
+// Prefixes the error with the line of the wiring spec that set the property, if known
+func (p *Property[T]) errorf(format string, args ...any) error {
+	if location := (ValidationIssue{Callstack: logging.GetCallstack()}).Location(); location != "" {
+		return blueprint.Errorf("%s: %s", location, fmt.Sprintf(format, args...))
+	}
+	return blueprint.Errorf(format, args...)
+}
+
+func (p *Property[T]) typeName() string {
+	return reflect.TypeOf((*T)(nil)).Elem().String()
+}
+
+// Checks the values of declared properties, which might have been set without type checks
+func (spec *wiringSpecImpl) validateProperties(report *ValidationReport) {
+	names := make([]string, 0, len(spec.defs))
+	for name := range spec.defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		def := spec.defs[name]
+		for key, values := range def.Properties {
+			if checker, declared := declaredProperties[key]; declared {
+				if err := checker.check(name, values); err != nil {
+					report.Errorf(name, def.Callstack(), "%s", err.Error())
+				}
+			}
+		}
+	}
+}
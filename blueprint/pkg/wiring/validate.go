@@ -0,0 +1,397 @@
+package wiring
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint/logging"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+	"golang.org/x/exp/slog"
+)
+
+// The severity of a [ValidationIssue]
+type IssueSeverity int
+
+const (
+	// The wiring spec will fail to build
+	IssueError IssueSeverity = iota
+
+	// The wiring spec can build, but probably doesn't do what was intended
+	IssueWarning
+)
+
+func (s IssueSeverity) String() string {
+	if s == IssueWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// A single problem found when validating a wiring spec
+type ValidationIssue struct {
+	Severity  IssueSeverity
+	Name      string             // The name in the wiring spec that the issue relates to
+	Message   string             // Description of the problem
+	Callstack *logging.Callstack // Where the problem was introduced; can be nil
+}
+
+// Returns the file and line of the wiring spec that introduced the issue, or the empty
+// string if it is unknown.
+func (issue ValidationIssue) Location() string {
+	callsite, ok := issue.Callstack.WiringCallsite()
+	if !ok || callsite.Source == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s:%v", callsite.Source.WorkspaceFilename, callsite.LineNumber)
+}
+
+func (issue ValidationIssue) String() string {
+	if location := issue.Location(); location != "" {
+		return fmt.Sprintf("%s: %s: %s", location, issue.Severity, issue.Message)
+	}
+	return fmt.Sprintf("%s: %s", issue.Severity, issue.Message)
+}
+
+// The result of validating a wiring spec with [WiringSpec.Validate] or [WiringSpec.BuildIRWithReport].
+//
+// Validation reports:
+//   - errors accumulated in the wiring spec with [WiringSpec.AddError]
+//   - aliases that point to names that were never defined
+//   - aliases that loop
+//   - names that were given properties but were never defined
+//   - values of properties declared with [DeclareProperty] that are of the wrong type, invalid, or
+//     that have more than one value when the property is single-valued
+//   - names that do not exist but are requested by another node when building
+//   - nodes that fail to build
+//   - any problems reported by validators that plugins registered with [RegisterValidator],
+//     for example pointers with no destination
+//   - (as warnings) definitions that are never instantiated
+//
+// Each issue is tied back to the line of the wiring spec that caused it.
+type ValidationReport struct {
+	Issues []ValidationIssue
+}
+
+// Adds an error to the report.  callstack should be the callstack of the def or alias
+// that is at fault, and can be nil if unknown.
+func (r *ValidationReport) Errorf(name string, callstack *logging.Callstack, format string, args ...any) {
+	r.add(IssueError, name, callstack, fmt.Sprintf(format, args...))
+}
+
+// Adds a warning to the report.  callstack should be the callstack of the def or alias
+// that is at fault, and can be nil if unknown.
+func (r *ValidationReport) Warnf(name string, callstack *logging.Callstack, format string, args ...any) {
+	r.add(IssueWarning, name, callstack, fmt.Sprintf(format, args...))
+}
+
+func (r *ValidationReport) add(severity IssueSeverity, name string, callstack *logging.Callstack, message string) {
+	r.Issues = append(r.Issues, ValidationIssue{
+		Severity:  severity,
+		Name:      name,
+		Message:   message,
+		Callstack: callstack,
+	})
+}
+
+// Reports whether an issue has already been reported for name
+func (r *ValidationReport) Has(name string) bool {
+	for _, issue := range r.Issues {
+		if issue.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns only the issues with the specified severity
+func (r *ValidationReport) Filter(severity IssueSeverity) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// Returns a [WiringError] containing every error in the report, or nil if there are no errors.
+// Warnings are not included.
+func (r *ValidationReport) Err() error {
+	var errs []error
+	for _, issue := range r.Filter(IssueError) {
+		errs = append(errs, fmt.Errorf("%s", issue.String()))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &WiringError{errs}
+}
+
+func (r *ValidationReport) String() string {
+	if len(r.Issues) == 0 {
+		return "no issues"
+	}
+	var lines []string
+	for _, issue := range r.Issues {
+		lines = append(lines, issue.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Sorts issues by location so that reports are deterministic
+func (r *ValidationReport) sort() {
+	sort.SliceStable(r.Issues, func(i, j int) bool {
+		a, b := r.Issues[i], r.Issues[j]
+		if a.Severity != b.Severity {
+			return a.Severity < b.Severity
+		}
+		if la, lb := a.Location(), b.Location(); la != lb {
+			return la < lb
+		}
+		return a.Message < b.Message
+	})
+}
+
+// A Validator checks plugin-specific metadata of a wiring spec and adds any problems
+// to the report.  Plugins register validators with [RegisterValidator].
+type Validator func(spec WiringSpec, report *ValidationReport)
+
+type namedValidator struct {
+	name     string
+	validate Validator
+}
+
+var validators []namedValidator
+
+// Registers a validator that will be run whenever a wiring spec is validated.
+//
+// Plugins that store metadata in the wiring spec (e.g. the pointer plugin) use this to
+// check that metadata for problems that would otherwise only be discovered when building.
+func RegisterValidator(name string, validator Validator) {
+	validators = append(validators, namedValidator{name, validator})
+	slog.Info(fmt.Sprintf("%v registered as a wiring spec validator", name))
+}
+
+// Implements [WiringSpec]
+func (spec *wiringSpecImpl) Validate(nodesToInstantiate ...string) *ValidationReport {
+	_, report := spec.BuildIRWithReport(nodesToInstantiate...)
+	return report
+}
+
+// Implements [WiringSpec]
+func (spec *wiringSpecImpl) BuildIRWithReport(nodesToInstantiate ...string) (*ir.ApplicationNode, *ValidationReport) {
+	report := &ValidationReport{}
+
+	// Errors that plugins already reported while the spec was being defined
+	for _, err := range spec.errors {
+		report.Errorf("", nil, "%s", firstLine(err))
+	}
+
+	for _, v := range validators {
+		v.validate(spec, report)
+	}
+	spec.validateAliases(report)
+	spec.validateDefs(report)
+	spec.validateProperties(report)
+
+	// Build the IR, recording missing names, nodes that fail to build, and unused defs
+	tracker := &buildTracker{report: report, used: make(map[string]struct{})}
+	app, _ := buildApplicationIR(spec, spec.name, tracker, nodesToInstantiate...)
+	if len(nodesToInstantiate) > 0 {
+		spec.validateUnused(tracker, report)
+	}
+
+	report.sort()
+	return app, report
+}
+
+// Checks for aliases that point nowhere and aliases that loop
+func (spec *wiringSpecImpl) validateAliases(report *ValidationReport) {
+	aliases := make([]string, 0, len(spec.aliases))
+	for alias := range spec.aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	reportedCycles := make(map[string]struct{})
+	for _, alias := range aliases {
+		// Only report dangling aliases at the final link of a chain, so that a single
+		// undefined name isn't reported once for every alias that leads to it
+		pointsto := spec.aliases[alias]
+		_, isAlias := spec.aliases[pointsto]
+		_, isDef := spec.defs[pointsto]
+		if !isAlias && !isDef {
+			if !report.Has(pointsto) {
+				report.Errorf(pointsto, spec.aliasCallsites[alias], "%s is an alias for %s, but %s is not defined", alias, pointsto, pointsto)
+			}
+			continue
+		}
+
+		if cycle := spec.aliasCycle(alias); cycle != nil {
+			sorted := append([]string{}, cycle...)
+			sort.Strings(sorted)
+			key := strings.Join(sorted, ",")
+			if _, reported := reportedCycles[key]; !reported {
+				reportedCycles[key] = struct{}{}
+				report.Errorf(alias, spec.aliasCallsites[alias], "aliases form a loop: %s -> %s", strings.Join(cycle, " -> "), cycle[0])
+			}
+		}
+	}
+}
+
+// If following alias leads back to alias, returns the aliases in the loop; otherwise nil
+func (spec *wiringSpecImpl) aliasCycle(alias string) []string {
+	path := []string{alias}
+	seen := map[string]struct{}{alias: {}}
+	for name := alias; ; {
+		next, isAlias := spec.aliases[name]
+		if !isAlias {
+			return nil
+		}
+		if next == alias {
+			return path
+		}
+		if _, visited := seen[next]; visited {
+			// Leads into a loop that doesn't include alias; the loop gets reported separately
+			return nil
+		}
+		seen[next] = struct{}{}
+		path = append(path, next)
+		name = next
+	}
+}
+
+// Checks for names that have properties but were never defined.  These are typically
+// caused by typos when calling plugins.
+func (spec *wiringSpecImpl) validateDefs(report *ValidationReport) {
+	for name, def := range spec.defs {
+		if def.Build == nil && !report.Has(name) {
+			var keys []string
+			for key := range def.Properties {
+				if key != "callsite" {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			report.Errorf(name, def.Callstack(), "%s was configured (%s) but never defined", name, strings.Join(keys, ", "))
+		}
+	}
+}
+
+// Warns about definitions that weren't instantiated when building.  Definitions are grouped
+// by the wiring spec line that defined them, since a single plugin call typically defines
+// several nodes.
+func (spec *wiringSpecImpl) validateUnused(tracker *buildTracker, report *ValidationReport) {
+	unused := make(map[string][]*WiringDef)
+	var locations []string
+	for name, def := range spec.defs {
+		if _, used := tracker.used[name]; used || def.Build == nil {
+			continue
+		}
+		location := ValidationIssue{Callstack: def.Callstack()}.Location()
+		if _, exists := unused[location]; !exists {
+			locations = append(locations, location)
+		}
+		unused[location] = append(unused[location], def)
+	}
+	sort.Strings(locations)
+
+	for _, location := range locations {
+		defs := unused[location]
+		sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+		var names []string
+		for _, def := range defs {
+			names = append(names, def.Name)
+		}
+		report.Warnf(defs[0].Name, defs[0].Callstack(), "%s defined but never instantiated", strings.Join(names, ", "))
+	}
+}
+
+// Records which names get used while building with a report, and the problems encountered.
+// A nil tracker does nothing, so that the namespace can call it unconditionally.
+type buildTracker struct {
+	report *ValidationReport
+	used   map[string]struct{}
+
+	// Set once a problem has been recorded for the deferred function currently executing.  When
+	// a node fails to build, the error propagates back through every node that depended on it;
+	// only the first (innermost) failure is recorded.
+	failed bool
+}
+
+func (t *buildTracker) reset() {
+	if t != nil {
+		t.failed = false
+	}
+}
+
+func (t *buildTracker) fail() {
+	if t != nil {
+		t.failed = true
+	}
+}
+
+func (t *buildTracker) use(names ...string) {
+	if t == nil {
+		return
+	}
+	for _, name := range names {
+		t.used[name] = struct{}{}
+	}
+}
+
+// Runs a deferred function.  When validating, a misconfigured spec can cause plugins to
+// panic part way through building; the panic is recovered and returned as an error so
+// that validation can continue.
+func (t *buildTracker) run(f func() error) (err error) {
+	if t == nil {
+		return f()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic while building: %v", r)
+		}
+	}()
+	return f()
+}
+
+// Called when name is requested from namespace but doesn't exist
+func (t *buildTracker) missing(namespace *namespaceimpl, name string) {
+	if t == nil || t.failed {
+		return
+	}
+	t.failed = true
+	if t.report.Has(name) {
+		return
+	}
+	if len(namespace.stack) == 0 {
+		t.report.Errorf(name, nil, "%s was requested for instantiation but does not exist in the wiring spec", name)
+		return
+	}
+	requester := namespace.stack[len(namespace.stack)-1]
+	t.report.Errorf(name, requester.Callstack(), "%s requires %s but %s does not exist in the wiring spec", requester.Name, name, name)
+}
+
+// Called when the BuildFunc of def returns an error
+func (t *buildTracker) buildFailed(def *WiringDef, err error) {
+	if t == nil || t.failed {
+		return
+	}
+	t.failed = true
+	t.report.Errorf(def.Name, def.Callstack(), "unable to build %s: %s", def.Name, firstLine(err))
+}
+
+// Called when a deferred function returns an error
+func (t *buildTracker) deferredFailed(err error) {
+	if t == nil || t.failed {
+		return
+	}
+	t.failed = true
+	t.report.Errorf("", nil, "%s", firstLine(err))
+}
+
+// Blueprint errors include a stack trace after the first line
+func firstLine(err error) string {
+	return strings.SplitN(err.Error(), "\n", 2)[0]
+}
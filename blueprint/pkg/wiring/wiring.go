@@ -60,10 +60,19 @@ type WiringSpec interface {
 	String() string // Returns a string representation of everything that has been defined
 
 	// Errors while building a wiring spec are accumulated within the wiring spec, rather than as return values to calls
-	AddError(err error) // Used by plugins to signify an error; the error will be returned by a call to Err or GetBlueprint
+	AddError(err error) // Used by plugins to signify an error; the error will be returned by a call to Err or BuildIR
 	Err() error         // Gets an error if there is currently one
 
 	BuildIR(nodesToInstantiate ...string) (*ir.ApplicationNode, error) // After defining everything, this builds the IR for the specified named nodes (implicitly including dependencies of those nodes)
+
+	// Checks the wiring spec for problems, reporting every problem found rather than just the first.
+	// See [ValidationReport] for the kinds of problems that are reported.
+	Validate(nodesToInstantiate ...string) *ValidationReport
+
+	// Like BuildIR, but builds as much of the IR as possible and reports every problem found, as [Validate] does.
+	// The IR is only usable if the report has no errors.  Use this rather than calling Validate then BuildIR,
+	// which would run every BuildFunc twice.
+	BuildIRWithReport(nodesToInstantiate ...string) (*ir.ApplicationNode, *ValidationReport)
 }
 
 // Additional options that can be specified when defining a WiringSpec node.
@@ -89,10 +98,11 @@ type WiringDef struct {
 
 type wiringSpecImpl struct {
 	WiringSpec
-	name    string
-	defs    map[string]*WiringDef
-	aliases map[string]string
-	errors  []error
+	name           string
+	defs           map[string]*WiringDef
+	aliases        map[string]string
+	aliasCallsites map[string]*logging.Callstack
+	errors         []error
 }
 
 func NewWiringSpec(name string) WiringSpec {
@@ -100,6 +110,7 @@ func NewWiringSpec(name string) WiringSpec {
 	spec.name = name
 	spec.defs = make(map[string]*WiringDef)
 	spec.aliases = make(map[string]string)
+	spec.aliasCallsites = make(map[string]*logging.Callstack)
 	spec.errors = nil
 	return &spec
 }
@@ -121,6 +132,17 @@ func (def *WiringDef) GetProperties(key string, dst any) error {
 	return copyResult(def.Properties[key], dst)
 }
 
+// Returns the callstack that was captured when this def was most recently defined.  If the def
+// has only had properties set and was never defined, returns the callstack of the first property.
+func (def *WiringDef) Callstack() *logging.Callstack {
+	if vs := def.Properties["callsite"]; len(vs) > 0 {
+		if callstack, isCallstack := vs[0].(*logging.Callstack); isCallstack {
+			return callstack
+		}
+	}
+	return nil
+}
+
 func (def *WiringDef) String() string {
 	defer func() {
 		if r := recover(); r != nil {
@@ -150,13 +172,18 @@ func (def *WiringDef) String() string {
 }
 
 func (spec *wiringSpecImpl) resolveAlias(alias string) string {
+	seen := make(map[string]struct{})
 	for {
 		name, is_alias := spec.aliases[alias]
-		if is_alias {
-			alias = name
-		} else {
+		if !is_alias {
 			return alias
 		}
+		if _, cycle := seen[alias]; cycle {
+			// Aliases that loop don't resolve to any def; Validate reports the loop
+			return ""
+		}
+		seen[alias] = struct{}{}
+		alias = name
 	}
 }
 
@@ -167,8 +194,10 @@ func (spec *wiringSpecImpl) getDef(name string, createIfAbsent bool) *WiringDef
 		def := WiringDef{}
 		def.Name = name
 		def.Properties = make(map[string][]any)
+		def.Properties["callsite"] = []any{logging.GetCallstack()}
 		spec.defs[name] = &def
 		delete(spec.aliases, name)
+		delete(spec.aliasCallsites, name)
 		return &def
 	} else {
 		return nil
@@ -211,6 +240,7 @@ func (spec *wiringSpecImpl) Alias(alias string, pointsto string) {
 		delete(spec.defs, alias)
 	}
 	spec.aliases[alias] = pointsto
+	spec.aliasCallsites[alias] = logging.GetCallstack()
 }
 
 // If the provided name is an alias, returns what it points to.
@@ -288,5 +318,10 @@ func (spec *wiringSpecImpl) Err() error {
 }
 
 func (spec *wiringSpecImpl) BuildIR(nodesToInstantiate ...string) (*ir.ApplicationNode, error) {
+	// Errors added by plugins while the spec was being written, such as misordered modifiers, would
+	// otherwise surface later as confusing build errors
+	if err := spec.Err(); err != nil {
+		return &ir.ApplicationNode{ApplicationName: spec.name}, err
+	}
 	return BuildApplicationIR(spec, spec.name, nodesToInstantiate...)
 }
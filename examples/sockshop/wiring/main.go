@@ -1,6 +1,8 @@
 // An application for compiling the SockShop application.
 // Provides a number of different wiring specs for compiling
-// the application in different configurations.
+// the application in different configurations.  Variants of
+// the basic and mongo specs can also be compiled by applying
+// overlays with the -overlay flag.
 //
 // To display options and usage, invoke:
 //
@@ -20,9 +22,12 @@ func main() {
 
 	// Build a supported wiring spec
 	name := "SockShop"
-	cmdbuilder.MakeAndExecute(
+	cmdbuilder.MakeAndExecuteWithOverlays(
 		name,
+		specs.Overlays,
+		nil,
 		specs.Basic,
+		specs.Mongo,
 		specs.GRPC,
 		specs.Docker,
 		specs.DockerRabbit,
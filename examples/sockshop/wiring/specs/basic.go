@@ -27,24 +27,30 @@ var Basic = cmdbuilder.SpecOption{
 }
 
 func makeBasicSpec(spec wiring.WiringSpec) ([]string, error) {
-	user_db := simple.NoSQLDB(spec, "user_db")
+	return makeServices(spec, simple.NoSQLDB, simple.RelationalDB)
+}
+
+// Defines the services of the application, using nosqlDB and relationalDB to define their database backends.
+// Returns the services, which are the nodes that overlays apply to.
+func makeServices(spec wiring.WiringSpec, nosqlDB, relationalDB func(spec wiring.WiringSpec, name string) string) ([]string, error) {
+	user_db := nosqlDB(spec, "user_db")
 	user_service := workflow.Service[user.UserService](spec, "user_service", user_db)
 
 	payment_service := workflow.Service[payment.PaymentService](spec, "payment_service", "500")
 
-	cart_db := simple.NoSQLDB(spec, "cart_db")
+	cart_db := nosqlDB(spec, "cart_db")
 	cart_service := workflow.Service[cart.CartService](spec, "cart_service", cart_db)
 
 	shipqueue := simple.Queue(spec, "shipping_queue")
-	shipdb := simple.NoSQLDB(spec, "shipping_db")
+	shipdb := nosqlDB(spec, "shipping_db")
 	shipping_service := workflow.Service[shipping.ShippingService](spec, "shipping_service", shipqueue, shipdb)
 
-	queue_master := workflow.Service[queuemaster.QueueMaster](spec, "queue_master", shipqueue, shipping_service)
+	queue_master := workflow.Service[queuemaster.QueueMaster](spec, queueMaster, shipqueue, shipping_service)
 
-	order_db := simple.NoSQLDB(spec, "order_db")
+	order_db := nosqlDB(spec, "order_db")
 	order_service := workflow.Service[order.OrderService](spec, "order_service", user_service, cart_service, payment_service, shipping_service, order_db)
 
-	catalogue_db := simple.RelationalDB(spec, "catalogue_db")
+	catalogue_db := relationalDB(spec, "catalogue_db")
 	catalogue_service := workflow.Service[catalogue.CatalogueService](spec, "catalogue_service", catalogue_db)
 
 	frontend_service := workflow.Service[frontend.Frontend](spec, "frontend", user_service, catalogue_service, cart_service, order_service)
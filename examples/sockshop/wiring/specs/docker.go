@@ -2,32 +2,23 @@ package specs
 
 import (
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
-	"github.com/blueprint-uservices/blueprint/examples/sockshop/workflow/cart"
-	"github.com/blueprint-uservices/blueprint/examples/sockshop/workflow/catalogue"
-	"github.com/blueprint-uservices/blueprint/examples/sockshop/workflow/frontend"
-	"github.com/blueprint-uservices/blueprint/examples/sockshop/workflow/order"
-	"github.com/blueprint-uservices/blueprint/examples/sockshop/workflow/payment"
-	"github.com/blueprint-uservices/blueprint/examples/sockshop/workflow/queuemaster"
-	"github.com/blueprint-uservices/blueprint/examples/sockshop/workflow/shipping"
-	"github.com/blueprint-uservices/blueprint/examples/sockshop/workflow/user"
-	"github.com/blueprint-uservices/blueprint/examples/sockshop/workload/workloadgen"
-	"github.com/blueprint-uservices/blueprint/plugins/clientpool"
 	"github.com/blueprint-uservices/blueprint/plugins/cmdbuilder"
-	"github.com/blueprint-uservices/blueprint/plugins/goproc"
-	"github.com/blueprint-uservices/blueprint/plugins/gotests"
-	"github.com/blueprint-uservices/blueprint/plugins/grpc"
-	"github.com/blueprint-uservices/blueprint/plugins/http"
-	"github.com/blueprint-uservices/blueprint/plugins/linuxcontainer"
 	"github.com/blueprint-uservices/blueprint/plugins/mongodb"
 	"github.com/blueprint-uservices/blueprint/plugins/mysql"
-	"github.com/blueprint-uservices/blueprint/plugins/opentelemetry"
-	"github.com/blueprint-uservices/blueprint/plugins/retries"
-	"github.com/blueprint-uservices/blueprint/plugins/simple"
-	"github.com/blueprint-uservices/blueprint/plugins/workflow"
-	"github.com/blueprint-uservices/blueprint/plugins/workload"
-	"github.com/blueprint-uservices/blueprint/plugins/zipkin"
 )
 
+// Like [Basic], but the user, cart, shipping, and orders services use separate MongoDB instances to store
+// their data, and the catalogue service uses MySQL to store catalogue data.
+var Mongo = cmdbuilder.SpecOption{
+	Name:        "mongo",
+	Description: "A basic single-process wiring spec that uses mongodb and mysql as database backends",
+	Build:       makeMongoSpec,
+}
+
+func makeMongoSpec(spec wiring.WiringSpec) ([]string, error) {
+	return makeServices(spec, mongodb.Container, mysql.Container)
+}
+
 // A wiring spec that deploys each service into its own Docker container and using gRPC to communicate between services.
 //
 // All RPC calls are retried up to 3 times.
@@ -37,71 +28,11 @@ import (
 // The user, cart, shipping, and orders services using separate MongoDB instances to store their data.
 // The catalogue service uses MySQL to store catalogue data.
 // The shipping service and queue master service run within the same process.
-var Docker = cmdbuilder.SpecOption{
-	Name:        "docker",
-	Description: "Deploys each service in a separate container with gRPC, and uses mongodb as NoSQL database backends.",
-	Build:       makeDockerSpec,
-}
-
-func makeDockerSpec(spec wiring.WiringSpec) ([]string, error) {
-	// Define the trace collector, which will be used by all services
-	trace_collector := zipkin.Collector(spec, "zipkin")
-
-	// Modifiers that will be applied to all services
-	applyDockerDefaults := func(serviceName string, useHTTP ...bool) {
-		// Golang-level modifiers that add functionality
-		retries.AddRetries(spec, serviceName, 3)
-		clientpool.Create(spec, serviceName, 10)
-		opentelemetry.Instrument(spec, serviceName, trace_collector)
-		if len(useHTTP) > 0 && useHTTP[0] {
-			http.Deploy(spec, serviceName)
-		} else {
-			grpc.Deploy(spec, serviceName)
-		}
-
-		// Deploying to namespaces
-		goproc.Deploy(spec, serviceName)
-		linuxcontainer.Deploy(spec, serviceName)
-
-		// Also add to tests
-		gotests.Test(spec, serviceName)
-	}
-
-	user_db := mongodb.Container(spec, "user_db")
-	user_service := workflow.Service[user.UserService](spec, "user_service", user_db)
-	applyDockerDefaults(user_service)
-
-	payment_service := workflow.Service[payment.PaymentService](spec, "payment_service", "500")
-	applyDockerDefaults(payment_service)
-
-	cart_db := mongodb.Container(spec, "cart_db")
-	cart_service := workflow.Service[cart.CartService](spec, "cart_service", cart_db)
-	applyDockerDefaults(cart_service)
-
-	shipqueue := simple.Queue(spec, "shipping_queue")
-	shipdb := mongodb.Container(spec, "shipping_db")
-	shipping_service := workflow.Service[shipping.ShippingService](spec, "shipping_service", shipqueue, shipdb)
-	applyDockerDefaults(shipping_service)
-
-	// Deploy queue master to the same process as the shipping proc
-	// TODO: after distributed queue is supported, move to separate containers
-	queue_master := workflow.Service[queuemaster.QueueMaster](spec, "queue_master", shipqueue, shipping_service)
-	goproc.AddToProcess(spec, "shipping_proc", queue_master)
-
-	order_db := mongodb.Container(spec, "order_db")
-	order_service := workflow.Service[order.OrderService](spec, "order_service", user_service, cart_service, payment_service, shipping_service, order_db)
-	applyDockerDefaults(order_service)
-
-	catalogue_db := mysql.Container(spec, "catalogue_db")
-	catalogue_service := workflow.Service[catalogue.CatalogueService](spec, "catalogue_service", catalogue_db)
-	applyDockerDefaults(catalogue_service)
-
-	frontend_service := workflow.Service[frontend.Frontend](spec, "frontend", user_service, catalogue_service, cart_service, order_service)
-	applyDockerDefaults(frontend_service, true) // Only the frontend gets deployed with HTTP
-
-	wlgen := workload.Generator[workloadgen.SimpleWorkload](spec, "wlgen", frontend_service)
-
-	// Instantiate starting with the frontend which will trigger all other services to be instantiated
-	// Also include the tests and wlgen
-	return []string{"frontend_ctr", wlgen, "gotests"}, nil
-}
+//
+// Equivalent to -w mongo -overlay retries,tracing,rpc,processes,containers,tests,workload
+var Docker = func() cmdbuilder.SpecOption {
+	spec := cmdbuilder.WithOverlays(Mongo, Retries, Tracing, RPC, Processes, Containers, Tests, Workload)
+	spec.Name = "docker"
+	spec.Description = "Deploys each service in a separate container with gRPC, and uses mongodb as NoSQL database backends."
+	return spec
+}()
@@ -0,0 +1,131 @@
+package specs
+
+import (
+	"strings"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/examples/sockshop/workload/workloadgen"
+	"github.com/blueprint-uservices/blueprint/plugins/clientpool"
+	"github.com/blueprint-uservices/blueprint/plugins/cmdbuilder"
+	"github.com/blueprint-uservices/blueprint/plugins/goproc"
+	"github.com/blueprint-uservices/blueprint/plugins/gotests"
+	"github.com/blueprint-uservices/blueprint/plugins/grpc"
+	"github.com/blueprint-uservices/blueprint/plugins/http"
+	"github.com/blueprint-uservices/blueprint/plugins/linuxcontainer"
+	"github.com/blueprint-uservices/blueprint/plugins/opentelemetry"
+	"github.com/blueprint-uservices/blueprint/plugins/retries"
+	"github.com/blueprint-uservices/blueprint/plugins/workload"
+	"github.com/blueprint-uservices/blueprint/plugins/zipkin"
+)
+
+// Overlays that can be applied on top of the basic and mongo specs with the -overlay flag, e.g.
+//
+//	go run main.go -o build -w basic -overlay retries,rpc,processes,tests
+//
+// Overlays must be applied in the order that they are listed here; each is optional.
+var Overlays = []cmdbuilder.Overlay{Retries, Tracing, RPC, Processes, Containers, Tests, Workload}
+
+// The queue master has no callers, and uses an in-memory queue shared with the shipping service, so
+// overlays leave it alone apart from deploying it to the shipping service's process.
+const queueMaster = "queue_master"
+
+// The services that the tests overlay tests
+var testedServices = []string{"user_service", "payment_service", "cart_service", "shipping_service", "order_service", "catalogue_service", "frontend"}
+
+// Retries all RPC calls up to 3 times, and gives RPC clients a client pool with 10 clients.
+var Retries = cmdbuilder.Overlay{
+	Name:        "retries",
+	Description: "Retries calls to each service up to 3 times, using a pool of 10 clients.",
+	Apply: func(spec wiring.WiringSpec, nodes []string) ([]string, error) {
+		for _, node := range nodes {
+			if node != queueMaster {
+				retries.AddRetries(spec, node, 3)
+				clientpool.Create(spec, node, 10)
+			}
+		}
+		return nodes, nil
+	},
+}
+
+// Instruments all services with OpenTelemetry, exporting traces to a Zipkin collector.
+var Tracing = cmdbuilder.Overlay{
+	Name:        "tracing",
+	Description: "Instruments each service with OpenTelemetry and exports traces to Zipkin.",
+	Apply: func(spec wiring.WiringSpec, nodes []string) ([]string, error) {
+		trace_collector := zipkin.Collector(spec, "zipkin")
+		for _, node := range nodes {
+			if node != queueMaster {
+				opentelemetry.Instrument(spec, node, trace_collector)
+			}
+		}
+		return nodes, nil
+	},
+}
+
+// Deploys the frontend with HTTP, and all other services with gRPC.
+var RPC = cmdbuilder.Overlay{
+	Name:        "rpc",
+	Description: "Deploys the frontend with HTTP and each other service with gRPC.",
+	Apply: func(spec wiring.WiringSpec, nodes []string) ([]string, error) {
+		for _, node := range nodes {
+			switch node {
+			case queueMaster:
+			case "frontend":
+				http.Deploy(spec, node)
+			default:
+				grpc.Deploy(spec, node)
+			}
+		}
+		return nodes, nil
+	},
+}
+
+// Deploys each service to a separate process.  The queue master is deployed to the same process as
+// the shipping service.
+var Processes = cmdbuilder.Overlay{
+	Name:        "processes",
+	Description: "Deploys each service in a separate process.",
+	Apply: func(spec wiring.WiringSpec, nodes []string) ([]string, error) {
+		var procs []string
+		for _, node := range nodes {
+			if node != queueMaster {
+				procs = append(procs, goproc.Deploy(spec, node))
+			}
+		}
+		// TODO: after distributed queue is supported, move to separate processes
+		goproc.AddToProcess(spec, "shipping_proc", queueMaster)
+		return procs, nil
+	},
+}
+
+// Deploys each process to a separate Docker container.  Must be applied after [Processes].
+var Containers = cmdbuilder.Overlay{
+	Name:        "containers",
+	Description: "Deploys each process in a separate container.",
+	Apply: func(spec wiring.WiringSpec, nodes []string) ([]string, error) {
+		var ctrs []string
+		for _, node := range nodes {
+			procPrefix, _ := strings.CutSuffix(node, "_proc")
+			ctrs = append(ctrs, linuxcontainer.CreateContainer(spec, procPrefix+"_ctr", node))
+		}
+		return ctrs, nil
+	},
+}
+
+// Adds the services to the application's tests.
+var Tests = cmdbuilder.Overlay{
+	Name:        "tests",
+	Description: "Adds the services to the tests.",
+	Apply: func(spec wiring.WiringSpec, nodes []string) ([]string, error) {
+		return append(nodes, gotests.Test(spec, testedServices...)), nil
+	},
+}
+
+// Adds a workload generator that calls the frontend.
+var Workload = cmdbuilder.Overlay{
+	Name:        "workload",
+	Description: "Adds a workload generator that calls the frontend.",
+	Apply: func(spec wiring.WiringSpec, nodes []string) ([]string, error) {
+		return append(nodes, workload.Generator[workloadgen.SimpleWorkload](spec, "wlgen", "frontend")), nil
+	},
+}
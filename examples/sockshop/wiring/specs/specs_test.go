@@ -0,0 +1,28 @@
+package specs
+
+import (
+	"testing"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	_ "github.com/blueprint-uservices/blueprint/examples/sockshop/tests"
+	"github.com/blueprint-uservices/blueprint/plugins/cmdbuilder"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow/workflowspec"
+)
+
+// Builds the IR of each wiring spec, which locates and validates every workflow service
+func TestBuildSpecs(t *testing.T) {
+	workflowspec.AddModule("github.com/blueprint-uservices/blueprint/examples/sockshop/tests")
+
+	for _, option := range []cmdbuilder.SpecOption{Basic, Mongo, GRPC, Docker, DockerRabbit} {
+		t.Run(option.Name, func(t *testing.T) {
+			spec := wiring.NewWiringSpec("SockShop")
+			nodes, err := option.Build(spec)
+			if err != nil {
+				t.Fatalf("unable to build wiring spec %v: %v", option.Name, err)
+			}
+			if _, err := spec.BuildIR(nodes...); err != nil {
+				t.Fatalf("unable to build IR of wiring spec %v: %v", option.Name, err)
+			}
+		})
+	}
+}
@@ -30,7 +30,7 @@ func generateClient(builder golang.ModuleBuilder, wrapped *gocode.ServiceInterfa
 
 	slog.Info(fmt.Sprintf("Generating %v/%v", client.Package.PackageName, wrapped.BaseName+"CircuitBreakerClient"))
 	outputFile := filepath.Join(client.Package.Path, wrapped.BaseName+"_CircuitBreakerClient.go")
-	return gogen.ExecuteTemplateToFile("CircuitBreaker", clientTemplate, client, outputFile)
+	return clientTemplate.ExecuteToFile(builder, client, outputFile)
 }
 
 type clientArgs struct {
@@ -43,7 +43,7 @@ type clientArgs struct {
 	Imports     *gogen.Imports
 }
 
-var clientTemplate = `// Blueprint: Auto-generated by CircuitBreaker Plugin
+var clientTemplate = gogen.RegisterTemplate("circuitbreaker", "client", `// Blueprint: Auto-generated by CircuitBreaker Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -89,4 +89,4 @@ func (client *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.Co
 	return client.Client.{{$f.Name}}({{ArgVars $f "ctx"}})
 }
 {{end}}
-`
+`)
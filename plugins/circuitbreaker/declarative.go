@@ -0,0 +1,29 @@
+package circuitbreaker
+
+import (
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("circuitbreaker.AddCircuitBreaker", func(spec wiring.WiringSpec, step declarative.Step) ([]string, error) {
+		if err := step.CheckOptions("min_reqs", "failure_rate", "interval"); err != nil {
+			return nil, err
+		}
+		min_reqs, err := step.GetInt("min_reqs")
+		if err != nil {
+			return nil, err
+		}
+		failure_rate, err := step.GetFloat("failure_rate")
+		if err != nil {
+			return nil, err
+		}
+		interval, err := step.GetDuration("interval")
+		if err != nil {
+			return nil, err
+		}
+		return declarative.Apply(func(spec wiring.WiringSpec, serviceName string) {
+			AddCircuitBreaker(spec, serviceName, min_reqs, failure_rate, interval)
+		})(spec, step)
+	})
+}
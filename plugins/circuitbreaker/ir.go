@@ -11,6 +11,10 @@ import (
 	"github.com/blueprint-uservices/blueprint/plugins/golang/gocode"
 )
 
+func init() {
+	ir.RegisterJSONType[*CircuitBreakerClient]()
+}
+
 // Blueprint IR node representing a CircuitBreaker
 type CircuitBreakerClient struct {
 	golang.Service
@@ -20,7 +24,7 @@ type CircuitBreakerClient struct {
 	InstanceName string
 	Wrapped      golang.Service
 
-	outputPackage string
+	OutputPackage string
 	Min_Reqs      int64
 	FailureRate   float64
 	Interval      string
@@ -45,7 +49,7 @@ func newCircuitBreakerClient(name string, server ir.IRNode, min_reqs int64, fail
 	node := &CircuitBreakerClient{}
 	node.InstanceName = name
 	node.Wrapped = serverNode
-	node.outputPackage = "cb"
+	node.OutputPackage = "cb"
 	node.Min_Reqs = min_reqs
 	node.FailureRate = failure_rate
 	node.Interval = interval
@@ -71,7 +75,7 @@ func (node *CircuitBreakerClient) GenerateFuncs(builder golang.ModuleBuilder) er
 		return err
 	}
 
-	return generateClient(builder, iface, node.outputPackage, node.Min_Reqs, node.FailureRate, node.Interval)
+	return generateClient(builder, iface, node.OutputPackage, node.Min_Reqs, node.FailureRate, node.Interval)
 }
 
 func (node *CircuitBreakerClient) AddInstantiation(builder golang.NamespaceBuilder) error {
@@ -85,7 +89,7 @@ func (node *CircuitBreakerClient) AddInstantiation(builder golang.NamespaceBuild
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_CircuitBreakerClient", iface.BaseName),
 			Arguments: []gocode.Variable{
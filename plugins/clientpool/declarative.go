@@ -0,0 +1,21 @@
+package clientpool
+
+import (
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("clientpool.Create", func(spec wiring.WiringSpec, step declarative.Step) ([]string, error) {
+		if err := step.CheckOptions("num_clients"); err != nil {
+			return nil, err
+		}
+		num_clients, err := step.GetInt("num_clients")
+		if err != nil {
+			return nil, err
+		}
+		return declarative.Apply(func(spec wiring.WiringSpec, serviceName string) {
+			Create(spec, serviceName, int(num_clients))
+		})(spec, step)
+	})
+}
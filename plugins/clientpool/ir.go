@@ -14,6 +14,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*ClientPool]()
+}
+
 // Blueprint IR node representing a ClientPool that uses [N] instances of [Client]
 type ClientPool struct {
 	golang.Service
@@ -109,7 +113,7 @@ func (pool *ClientPool) GenerateFuncs(module golang.ModuleBuilder) error {
 
 	// Generate the client pool code
 	poolFileName := filepath.Join(module.Info().Path, args.PackageShortName, args.PoolFileName)
-	return gogen.ExecuteTemplateToFile("clientpool_client_constructor", poolTemplate, args, poolFileName)
+	return poolTemplate.ExecuteToFile(module, args, poolFileName)
 }
 
 // Implements golang.Service golang.Instantiable
@@ -126,7 +130,7 @@ func (pool *ClientPool) AddInstantiation(builder golang.NamespaceBuilder) error
 	builder.Import(args.PackageName)
 
 	slog.Info(fmt.Sprintf("Instantiating ClientPool %v in %v/%v", pool.PoolName, builder.Info().Package.PackageName, builder.Info().FileName))
-	code, err := gogen.ExecuteTemplate("clientpool", buildPoolTemplate, args)
+	code, err := buildPoolTemplate.Execute(builder.Module(), args)
 	if err != nil {
 		return err
 	}
@@ -177,11 +181,11 @@ type (
 	}
 )
 
-var buildPoolTemplate = `func(n *golang.Namespace) (any, error) {
+var buildPoolTemplate = gogen.RegisterTemplate("clientpool", "buildPool", `func(n *golang.Namespace) (any, error) {
 		return pool.{{.PoolConstructor}}(n), nil
-	}`
+	}`)
 
-var poolTemplate = `// This file is auto-generated by the Blueprint clientpool plugin
+var poolTemplate = gogen.RegisterTemplate("clientpool", "pool", `// This file is auto-generated by the Blueprint clientpool plugin
 package {{.PackageShortName}}
 
 {{.Imports}}
@@ -221,4 +225,4 @@ func (pool *{{$receiver}}) {{SignatureWithRetVars $f}} {
 {{end}}
 
 
-`
+`)
@@ -6,6 +6,22 @@
 // and takes care of argument parsing and spec building.
 //
 // Specify the name of a wiring spec with the -w argument, and the output directory with -o.
+// To compile several wiring specs at once, specify a comma-separated list of names, or all, with -w;
+// each spec is compiled to a subdirectory of the output directory.
+// The compiled IR can additionally be exported as a Graphviz DOT file with -dot, or as JSON with -json.
+// The IR can be saved with -save-ir, and artifacts regenerated from a saved IR with -load-ir.
+// Instead of a wiring spec written in Go, a declarative YAML or JSON wiring spec can be compiled by
+// specifying its file with -f; see the [declarative] plugin.
+// To compare two wiring specs, specify the second spec with -diff; the differences between their
+// IRs are printed and nothing is compiled.
+// Variants of a wiring spec can be selected by applying overlays on top of it with -overlay, e.g.
+// -overlay retries,containers; see [Overlay].
+// To compile every combination of a grid of parameter values of a spec, see [SweepSpec] and [CmdBuilder.BuildSweep].
+// To inspect a wiring spec without compiling it, follow the flags with one of the subcommands
+// list, describe <name>, or chain <service>; see [CmdBuilder.Inspect].
+// Generated Go code is formatted; specify -typecheck to also type check it.
+// Independent namespaces are generated concurrently; use -parallel to limit how many, or -parallel=1
+// to generate sequentially.
 //
 // # Usage
 //
@@ -50,6 +66,18 @@
 //
 //	go run main.go -o build -w myspec
 //
+// To compile every wiring spec, e.g. in CI, run
+//
+//	go run main.go -o build -w all
+//
+// To compile myspec with overlays applied on top of it, in order, run
+//
+//	go run main.go -o build -w myspec -overlay retries,containers
+//
+// To print the client and server modifiers that the spec applies to a service, run
+//
+//	go run main.go -w myspec chain myservice
+//
 // [wiring/main.go]: https://github.com/Blueprint-uServices/blueprint/blob/main/examples/sockshop/wiring/main.go
 package cmdbuilder
 
@@ -57,15 +85,20 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint/logging"
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/analysis"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/irgraph"
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
 	"github.com/blueprint-uservices/blueprint/plugins/dockercompose"
 	"github.com/blueprint-uservices/blueprint/plugins/environment"
+	"github.com/blueprint-uservices/blueprint/plugins/golang/gogen"
 	"github.com/blueprint-uservices/blueprint/plugins/goproc"
 	"github.com/blueprint-uservices/blueprint/plugins/linuxcontainer"
 	"golang.org/x/exp/slog"
@@ -86,18 +119,35 @@ type SpecOption struct {
 // wiring specs.  Makes it easy to choose which spec to compile.
 // See the Blueprint example applications for usage
 type CmdBuilder struct {
-	Name      string
-	OutputDir string
-	Quiet     bool
-	SpecName  string
-	Env       bool
-	Port      uint16
-	Spec      SpecOption
-	Wiring    wiring.WiringSpec
-	IR        *ir.ApplicationNode
-	Passes    []analysis.IRAnalysisPass
+	Name       string
+	OutputDir  string
+	Quiet      bool
+	Validate   bool
+	SpecName   string
+	Env        bool
+	Port       uint16
+	DOTFile    string
+	JSONFile   string
+	DiffSpec   string
+	SpecFile   string
+	Parallel   int
+	TypeCheck  bool
+	Command    []string
+	Spec       SpecOption
+	Specs      []SpecOption // When more than one spec is selected with -w; see [CmdBuilder.BuildEach]
+	Wiring     wiring.WiringSpec
+	IR         *ir.ApplicationNode
+	Passes     []analysis.IRAnalysisPass
+	Transforms []analysis.IRTransformPass
+
+	SaveIRFile string // If set, the built IR is saved to this file; see [ir.SaveJSON]
+	LoadIRFile string // If set, artifacts are generated from the IR saved in this file, instead of from a wiring spec
 
-	Registry map[string]SpecOption
+	OverlayNames string    // The comma-separated overlays specified with -overlay
+	Overlays     []Overlay // The selected overlays, applied in order to each selected spec
+
+	Registry        map[string]SpecOption
+	OverlayRegistry map[string]Overlay
 }
 
 // Parses command line flags, and if a valid spec is specified with the -w
@@ -105,32 +155,36 @@ type CmdBuilder struct {
 func MakeAndExecute(name string, specs ...SpecOption) {
 	builder := NewCmdBuilder(name)
 	builder.Add(specs...)
-
-	builder.ParseArgs()
-	if err := builder.ValidateArgs(); err != nil {
-		slog.Error(err.Error())
-		os.Exit(1)
-	}
-
-	if err := builder.Build(); err != nil {
-		slog.Error(err.Error())
-		os.Exit(2)
-	}
+	builder.execute()
 }
 
+// Like [MakeAndExecute], but additionally runs passes on the IR of the spec.
 func MakeAndExecuteWithPasses(name string, passes []analysis.IRAnalysisPass, specs ...SpecOption) {
 	builder := NewCmdBuilder(name)
 	builder.Add(specs...)
+	builder.RegisterPasses(passes...)
+	builder.execute()
+}
 
-	builder.ParseArgs()
-	if err := builder.ValidateArgs(); err != nil {
+// Like [MakeAndExecute], but additionally registers overlays that can be applied to the specs with the -overlay flag,
+// and runs passes on the IR of the spec with the selected overlays applied.  passes can be nil.
+func MakeAndExecuteWithOverlays(name string, overlays []Overlay, passes []analysis.IRAnalysisPass, specs ...SpecOption) {
+	builder := NewCmdBuilder(name)
+	builder.Add(specs...)
+	builder.AddOverlays(overlays...)
+	builder.RegisterPasses(passes...)
+	builder.execute()
+}
+
+// Parses and validates command line flags, then runs the builder, exiting on error
+func (b *CmdBuilder) execute() {
+	b.ParseArgs()
+	if err := b.ValidateArgs(); err != nil {
 		slog.Error(err.Error())
 		os.Exit(1)
 	}
 
-	builder.RegisterPasses(passes...)
-
-	if err := builder.Build(); err != nil {
+	if err := b.Run(); err != nil {
 		slog.Error(err.Error())
 		os.Exit(2)
 	}
@@ -140,6 +194,7 @@ func NewCmdBuilder(applicationName string) *CmdBuilder {
 	builder := CmdBuilder{}
 	builder.Name = applicationName
 	builder.Registry = make(map[string]SpecOption)
+	builder.OverlayRegistry = make(map[string]Overlay)
 	return &builder
 }
 
@@ -153,37 +208,115 @@ func (b *CmdBuilder) RegisterPasses(passes ...analysis.IRAnalysisPass) {
 	b.Passes = append(b.Passes, passes...)
 }
 
+// Registers transform passes that rewrite the IR after it is built.  Transform passes run before any
+// analysis passes, in the order determined by [analysis.OrderTransformPasses].
+func (b *CmdBuilder) RegisterTransformPasses(passes ...analysis.IRTransformPass) {
+	b.Transforms = append(b.Transforms, passes...)
+}
+
 func (b *CmdBuilder) ParseArgs() {
 	output_dir := flag.String("o", "", "Target output directory for compilation.")
-	spec_name := flag.String("w", "", "Wiring spec to compile.  One of:\n"+b.List())
+	spec_name := flag.String("w", "", "Wiring spec to compile; a comma-separated list of specs or \"all\" compiles each spec to a subdirectory of -o.  One of:\n"+b.List())
 	quiet := flag.Bool("quiet", false, "Suppress verbose compiler output.")
+	validate := flag.Bool("validate", true, "Validate the wiring spec while building the IR, reporting every problem found.")
 	env := flag.Bool("env", true, "Generate a .env file that sets service address and port environment variables")
 	port := flag.Uint("port", 12345, "Sets the port to start at when assigning service ports.  Only used when generating a .env file.")
+	dot_file := flag.String("dot", "", "If specified, writes the application's IR to this file in Graphviz DOT format.")
+	json_file := flag.String("json", "", "If specified, writes the application's IR to this file as JSON.")
+	spec_file := flag.String("f", "", "A declarative YAML or JSON wiring spec to compile, instead of a wiring spec specified with -w.")
+	parallel := flag.Int("parallel", runtime.GOMAXPROCS(0), "The maximum number of namespaces to generate concurrently.  1 generates sequentially.")
+	diff_spec := flag.String("diff", "", "If specified, instead of compiling, prints the differences between the -w wiring spec and this wiring spec.")
+	typecheck := flag.Bool("typecheck", false, "Type check generated Go code, reporting errors with the plugin and template that generated them.")
+	save_ir := flag.String("save-ir", "", "If specified, saves the application's IR to this file, so that its artifacts can later be regenerated with -load-ir.")
+	load_ir := flag.String("load-ir", "", "Generates artifacts from the IR saved in this file with -save-ir, instead of from a wiring spec.")
+	overlays := flag.String("overlay", "", "A comma-separated list of overlays to apply, in order, on top of the wiring spec.  One of:\n"+b.ListOverlays())
 
 	flag.Parse()
 
 	b.OutputDir = *output_dir
 	b.Quiet = *quiet
+	b.Validate = *validate
 	b.SpecName = *spec_name
 	b.Env = *env
 	b.Port = uint16(*port)
+	b.DOTFile = *dot_file
+	b.JSONFile = *json_file
+	b.DiffSpec = *diff_spec
+	b.SpecFile = *spec_file
+	b.Parallel = *parallel
+	b.TypeCheck = *typecheck
+	b.SaveIRFile = *save_ir
+	b.LoadIRFile = *load_ir
+	b.OverlayNames = *overlays
+	b.Command = flag.Args()
 }
 
 func (b *CmdBuilder) ValidateArgs() error {
-	if b.OutputDir == "" {
+	if len(b.Command) > 0 {
+		if err := checkInspectArgs(b.Command); err != nil {
+			return err
+		}
+	} else if b.OutputDir == "" && b.DiffSpec == "" {
 		return blueprint.Errorf("output directory not specified, specify with -o")
 	}
 
+	if b.LoadIRFile != "" {
+		if b.SpecName != "" || b.SpecFile != "" || b.DiffSpec != "" || b.OverlayNames != "" || len(b.Command) > 0 {
+			return blueprint.Errorf("-load-ir cannot be combined with a wiring spec, -diff, -overlay, or inspect commands")
+		}
+		return nil
+	}
+
+	if b.SpecFile != "" {
+		if b.SpecName != "" {
+			return blueprint.Errorf("only one of -w and -f can be specified")
+		}
+		b.SpecName = strings.TrimSuffix(filepath.Base(b.SpecFile), filepath.Ext(b.SpecFile))
+		b.Registry[b.SpecName] = SpecOption{
+			Name:        b.SpecName,
+			Description: "Declarative wiring spec " + b.SpecFile,
+			Build:       declarative.BuildFunc(b.SpecFile),
+		}
+	}
+
 	if b.SpecName == "" {
-		return blueprint.Errorf("wiring spec not specified, specify with -w")
+		return blueprint.Errorf("wiring spec not specified, specify with -w or -f")
 	}
 
-	if spec, specExists := b.Registry[b.SpecName]; specExists {
+	if b.SpecName == "all" || strings.Contains(b.SpecName, ",") {
+		if b.DiffSpec != "" || len(b.Command) > 0 {
+			return blueprint.Errorf("-diff and inspect commands require a single wiring spec")
+		}
+		specs, err := b.selectSpecs(b.SpecName)
+		if err != nil {
+			return err
+		}
+		b.Specs = specs
+	} else if spec, specExists := b.Registry[b.SpecName]; specExists {
 		b.Spec = spec
 	} else {
 		return blueprint.Errorf("unknown wiring spec \"%v\", expected one of:\n%v", b.SpecName, b.List())
 	}
 
+	if _, specExists := b.Registry[b.DiffSpec]; b.DiffSpec != "" && !specExists {
+		return blueprint.Errorf("unknown wiring spec \"%v\", expected one of:\n%v", b.DiffSpec, b.List())
+	}
+
+	if b.OverlayNames != "" {
+		overlays, err := b.selectOverlays(b.OverlayNames)
+		if err != nil {
+			return err
+		}
+		b.Overlays = overlays
+		for i := range b.Specs {
+			b.Specs[i] = WithOverlays(b.Specs[i], b.Overlays...)
+		}
+		if len(b.Specs) == 0 {
+			b.Spec = WithOverlays(b.Spec, b.Overlays...)
+			b.SpecName = b.Spec.Name
+		}
+	}
+
 	if b.Quiet {
 		slog.Info("Suppressing compiler logging")
 		logging.DisableCompilerLogging()
@@ -201,8 +334,33 @@ func (builder *CmdBuilder) List() string {
 	return b.String()
 }
 
-func (b *CmdBuilder) Build() error {
-	// Configure the default builders for Blueprint
+// Compiles the selected wiring spec, or each of the selected wiring specs.  If an inspect subcommand was given, runs it instead; if a spec
+// was specified with -diff, prints the differences between the two specs.  Neither compiles the spec.
+func (b *CmdBuilder) Run() error {
+	if b.LoadIRFile != "" {
+		return b.BuildFromIR(b.LoadIRFile)
+	}
+	if len(b.Command) > 0 {
+		return b.Inspect(os.Stdout, b.Command...)
+	}
+	if len(b.Specs) > 0 {
+		_, err := b.BuildEach(os.Stdout)
+		return err
+	}
+	if b.DiffSpec == "" {
+		return b.Build()
+	}
+	diff, err := b.Diff(b.DiffSpec)
+	if err != nil {
+		return err
+	}
+	fmt.Println(diff)
+	return nil
+}
+
+// Configures the default builders for Blueprint, and the port assignment, parallelism, and type checking
+// used when generating artifacts
+func (b *CmdBuilder) initCompiler() {
 	slog.Info("Initializing Blueprint compiler")
 	goproc.RegisterAsDefaultBuilder()
 	linuxcontainer.RegisterAsDefaultBuilder()
@@ -211,8 +369,56 @@ func (b *CmdBuilder) Build() error {
 		environment.AssignPorts(b.Port)
 	}
 
+	if b.Parallel > 0 {
+		ir.SetBuildParallelism(b.Parallel)
+	}
+	gogen.SetTypeCheck(b.TypeCheck)
+}
+
+func (b *CmdBuilder) Build() error {
+	b.initCompiler()
+
+	if err := b.BuildIR(); err != nil {
+		return err
+	}
+
+	// Generate artifacts
+	slog.Info(fmt.Sprintf("Generating %v-%v artifacts to %v", b.Name, b.SpecName, b.OutputDir))
+	err := b.IR.GenerateArtifacts(b.OutputDir)
+	if err != nil {
+		return blueprint.Errorf("unable to generate %v-%v artifacts due to %v", b.Name, b.SpecName, err.Error())
+	}
+
+	slog.Info(fmt.Sprintf("Successfully generated %v-%v to %v", b.Name, b.SpecName, b.OutputDir))
+	return nil
+}
+
+// Generates artifacts to b.OutputDir from an IR previously saved with -save-ir, without running any wiring spec.
+// Plugins whose IR nodes are in the saved IR must be imported by the program, so that their node types are registered.
+func (b *CmdBuilder) BuildFromIR(filename string) error {
+	b.initCompiler()
+
+	app, err := ir.LoadJSON(filename)
+	if err != nil {
+		return err
+	}
+	b.IR = app
+	slog.Info(fmt.Sprintf("Loaded %v IR from %v: \n%v", b.Name, filename, b.IR))
+
+	slog.Info(fmt.Sprintf("Generating %v artifacts from %v to %v", b.Name, filename, b.OutputDir))
+	if err := b.IR.GenerateArtifacts(b.OutputDir); err != nil {
+		return blueprint.Errorf("unable to generate %v artifacts from %v due to %v", b.Name, filename, err.Error())
+	}
+
+	slog.Info(fmt.Sprintf("Successfully generated %v from %v to %v", b.Name, filename, b.OutputDir))
+	return nil
+}
+
+// Runs the wiring spec, validates it, builds the application's IR, and runs any transform and analysis passes,
+// but does not generate any artifacts.  Afterwards, the wiring spec and IR are stored in b.Wiring and b.IR.
+func (b *CmdBuilder) BuildIR() error {
 	// Define the wiring spec
-	slog.Info(fmt.Sprintf("Building %v-%v to %v", b.Name, b.SpecName, b.OutputDir))
+	slog.Info(fmt.Sprintf("Building %v-%v", b.Name, b.SpecName))
 	b.Wiring = wiring.NewWiringSpec(b.Name)
 	nodesToBuild, err := b.Spec.Build(b.Wiring)
 	if err != nil {
@@ -220,14 +426,32 @@ func (b *CmdBuilder) Build() error {
 	}
 	slog.Info(fmt.Sprintf("Constructed %v WiringSpec %v: \n%v", b.Name, b.SpecName, b.Wiring))
 
-	// Construct the IR
-	b.IR, err = b.Wiring.BuildIR(nodesToBuild...)
-	slog.Info(fmt.Sprintf("%v %v IR: \n%v", b.Name, b.SpecName, b.IR))
-	if err != nil {
-		return blueprint.Errorf("unable to construct %v-%v IR due to %v", b.Name, b.SpecName, err.Error())
+	// Construct the IR; when validating, every problem with the wiring spec is reported rather than just the first
+	if b.Validate {
+		var report *wiring.ValidationReport
+		b.IR, report = b.Wiring.BuildIRWithReport(nodesToBuild...)
+		for _, warning := range report.Filter(wiring.IssueWarning) {
+			slog.Warn(warning.String())
+		}
+		if err := report.Err(); err != nil {
+			return blueprint.Errorf("%v-%v wiring spec is invalid:\n%v", b.Name, b.SpecName, err.Error())
+		}
+	} else {
+		b.IR, err = b.Wiring.BuildIR(nodesToBuild...)
+		if err != nil {
+			slog.Info(fmt.Sprintf("%v %v IR: \n%v", b.Name, b.SpecName, b.IR))
+			return blueprint.Errorf("unable to construct %v-%v IR due to %v", b.Name, b.SpecName, err.Error())
+		}
 	}
+	slog.Info(fmt.Sprintf("%v %v IR: \n%v", b.Name, b.SpecName, b.IR))
 
-	// Run any analysis passes
+	// Run any transform passes, then any analysis passes
+	if len(b.Transforms) > 0 {
+		if err := analysis.RunTransformPasses(b.Wiring, b.IR, b.Transforms); err != nil {
+			return err
+		}
+		slog.Info(fmt.Sprintf("%v %v IR after transform passes: \n%v", b.Name, b.SpecName, b.IR))
+	}
 	for _, pass := range b.Passes {
 		slog.Info(fmt.Sprintf("Running analysis pass %s on the IR", pass.Name()))
 		is_modified, err := pass.Analyze(b.Wiring, b.IR)
@@ -241,13 +465,30 @@ func (b *CmdBuilder) Build() error {
 		}
 	}
 
-	// Generate artifacts
-	slog.Info(fmt.Sprintf("Generating %v-%v artifacts to %v", b.Name, b.SpecName, b.OutputDir))
-	err = b.IR.GenerateArtifacts(b.OutputDir)
-	if err != nil {
-		return blueprint.Errorf("unable to generate %v-%v artifacts due to %v", b.Name, b.SpecName, err.Error())
+	// Export the IR graph
+	if b.DOTFile != "" || b.JSONFile != "" {
+		graph := irgraph.FromIR(b.IR)
+		if b.DOTFile != "" {
+			if err := irgraph.ExportToFile(graph, b.DOTFile, irgraph.WriteDOT); err != nil {
+				return err
+			}
+			slog.Info(fmt.Sprintf("Wrote %v-%v IR graph to %v", b.Name, b.SpecName, b.DOTFile))
+		}
+		if b.JSONFile != "" {
+			if err := irgraph.ExportToFile(graph, b.JSONFile, irgraph.WriteJSON); err != nil {
+				return err
+			}
+			slog.Info(fmt.Sprintf("Wrote %v-%v IR graph to %v", b.Name, b.SpecName, b.JSONFile))
+		}
+	}
+
+	// Save the IR so that its artifacts can be regenerated later
+	if b.SaveIRFile != "" {
+		if err := ir.SaveJSON(b.SaveIRFile, b.IR); err != nil {
+			return err
+		}
+		slog.Info(fmt.Sprintf("Saved %v-%v IR to %v", b.Name, b.SpecName, b.SaveIRFile))
 	}
 
-	slog.Info(fmt.Sprintf("Successfully generated %v-%v to %v", b.Name, b.SpecName, b.OutputDir))
 	return nil
 }
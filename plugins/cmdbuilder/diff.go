@@ -0,0 +1,146 @@
+package cmdbuilder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/irgraph"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/pointer"
+)
+
+// The semantic differences between two wiring specs of an application, as computed by [CmdBuilder.Diff].
+type SpecDiff struct {
+	From, To        string // Names of the two specs
+	IR              *irgraph.GraphDiff
+	AddedPointers   []PointerDiff // Pointers that are only in the second spec
+	RemovedPointers []PointerDiff // Pointers that are only in the first spec
+	Chains          []ChainDiff
+}
+
+// A pointer that is only defined in one of two wiring specs, with its modifiers in that spec
+type PointerDiff struct {
+	Pointer string
+	Client  []string
+	Server  []string
+}
+
+// A pointer whose client or server side modifiers differ between two wiring specs
+type ChainDiff struct {
+	Pointer  string
+	Side     string   // "client" or "server"
+	From, To []string // The modifiers of the chain in each spec
+	Inserted []string // Modifiers that are only in the second spec
+	Removed  []string // Modifiers that are only in the first spec
+}
+
+// Builds the IR of the spec selected with -w and of the spec named other, then computes the differences
+// between them: nodes added or removed, nodes placed in different namespaces, changed properties, pointers
+// added or removed, and modifiers inserted or removed on pointer chains.
+//
+// No artifacts are generated.
+func (b *CmdBuilder) Diff(other string) (*SpecDiff, error) {
+	otherSpec, exists := b.Registry[other]
+	if !exists {
+		return nil, blueprint.Errorf("unknown wiring spec \"%v\", expected one of:\n%v", other, b.List())
+	}
+	if err := b.BuildIR(); err != nil {
+		return nil, err
+	}
+
+	// Overlays selected with -overlay apply to both specs
+	o := *b
+	o.Spec = WithOverlays(otherSpec, b.Overlays...)
+	o.SpecName = o.Spec.Name
+	o.DOTFile, o.JSONFile = "", ""
+	if err := o.BuildIR(); err != nil {
+		return nil, err
+	}
+
+	diff := &SpecDiff{
+		From: b.SpecName,
+		To:   o.SpecName,
+		IR:   irgraph.Diff(irgraph.FromIR(b.IR), irgraph.FromIR(o.IR)),
+	}
+	for _, name := range pointer.GetAllPointers(b.Wiring) {
+		from := pointer.GetPointer(b.Wiring, name)
+		to := pointer.GetPointer(o.Wiring, name)
+		if to == nil {
+			diff.RemovedPointers = append(diff.RemovedPointers, PointerDiff{name, from.SrcModifiers(), from.DstModifiers()})
+			continue
+		}
+		diff.addChain(name, "client", from.SrcModifiers(), to.SrcModifiers())
+		diff.addChain(name, "server", from.DstModifiers(), to.DstModifiers())
+	}
+	for _, name := range pointer.GetAllPointers(o.Wiring) {
+		if pointer.GetPointer(b.Wiring, name) == nil {
+			to := pointer.GetPointer(o.Wiring, name)
+			diff.AddedPointers = append(diff.AddedPointers, PointerDiff{name, to.SrcModifiers(), to.DstModifiers()})
+		}
+	}
+	return diff, nil
+}
+
+func (d *SpecDiff) addChain(ptr, side string, from, to []string) {
+	if strings.Join(from, ",") == strings.Join(to, ",") {
+		return
+	}
+	d.Chains = append(d.Chains, ChainDiff{
+		Pointer:  ptr,
+		Side:     side,
+		From:     from,
+		To:       to,
+		Inserted: subtract(to, from),
+		Removed:  subtract(from, to),
+	})
+}
+
+// Returns the elements of a that aren't in b
+func subtract(a, b []string) []string {
+	inB := make(map[string]struct{})
+	for _, s := range b {
+		inB[s] = struct{}{}
+	}
+	var remaining []string
+	for _, s := range a {
+		if _, exists := inB[s]; !exists {
+			remaining = append(remaining, s)
+		}
+	}
+	return remaining
+}
+
+// Reports whether the two specs are the same
+func (d *SpecDiff) Empty() bool {
+	return d.IR.Empty() && len(d.AddedPointers) == 0 && len(d.RemovedPointers) == 0 && len(d.Chains) == 0
+}
+
+func (d *SpecDiff) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", d.From, d.To)
+	if d.Empty() {
+		b.WriteString("no differences")
+		return b.String()
+	}
+	if !d.IR.Empty() {
+		b.WriteString(d.IR.String())
+		b.WriteString("\n")
+	}
+	for _, ptr := range d.RemovedPointers {
+		fmt.Fprintf(&b, "- pointer %s with client modifiers [%s] and server modifiers [%s]\n", ptr.Pointer, strings.Join(ptr.Client, " -> "), strings.Join(ptr.Server, " -> "))
+	}
+	for _, ptr := range d.AddedPointers {
+		fmt.Fprintf(&b, "+ pointer %s with client modifiers [%s] and server modifiers [%s]\n", ptr.Pointer, strings.Join(ptr.Client, " -> "), strings.Join(ptr.Server, " -> "))
+	}
+	for _, chain := range d.Chains {
+		fmt.Fprintf(&b, "~ %s %s modifiers changed from [%s] to [%s]", chain.Pointer, chain.Side, strings.Join(chain.From, " -> "), strings.Join(chain.To, " -> "))
+		if len(chain.Inserted) > 0 {
+			fmt.Fprintf(&b, "; inserted %s", strings.Join(chain.Inserted, ", "))
+		}
+		if len(chain.Removed) > 0 {
+			fmt.Fprintf(&b, "; removed %s", strings.Join(chain.Removed, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,227 @@
+package cmdbuilder
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint/logging"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/irgraph"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/pointer"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+)
+
+// The inspect subcommands supported by [CmdBuilder.Inspect], and their usage
+var inspectCommands = map[string]string{
+	"list":     "list",
+	"describe": "describe <name>",
+	"chain":    "chain <service>",
+}
+
+// Checks that args is a valid inspect subcommand
+func checkInspectArgs(args []string) error {
+	usage, exists := inspectCommands[args[0]]
+	if !exists {
+		var commands []string
+		for _, usage := range inspectCommands {
+			commands = append(commands, "  "+usage)
+		}
+		sort.Strings(commands)
+		return blueprint.Errorf("unknown command \"%v\", expected one of:\n%v", args[0], strings.Join(commands, "\n"))
+	}
+	if len(args) != len(strings.Fields(usage)) {
+		return blueprint.Errorf("usage: %v", usage)
+	}
+	return nil
+}
+
+// Builds the IR of the selected wiring spec and runs a read-only inspect subcommand, writing its output to w.
+// No artifacts are generated.  The subcommands are:
+//
+//   - list prints every IR node with its type and namespace
+//   - describe <name> prints a node's properties, the wiring spec line that defined it, and its dependencies
+//   - chain <service> prints the client and server modifiers that have been applied to a service's pointer, in order
+func (b *CmdBuilder) Inspect(w io.Writer, args ...string) error {
+	if len(args) == 0 {
+		return blueprint.Errorf("no inspect command specified")
+	}
+	if err := checkInspectArgs(args); err != nil {
+		return err
+	}
+	if err := b.BuildIR(); err != nil {
+		return err
+	}
+	graph := irgraph.FromIR(b.IR)
+
+	switch args[0] {
+	case "list":
+		return listNodes(w, graph)
+	case "describe":
+		return describeNode(w, b.Wiring, graph, args[1])
+	default:
+		return showChain(w, b.Wiring, args[1])
+	}
+}
+
+func listNodes(w io.Writer, graph *irgraph.Graph) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tNAMESPACE")
+	for _, node := range graph.Nodes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", node.Name, node.Type, namespaceName(node.Namespace))
+	}
+	return tw.Flush()
+}
+
+func describeNode(w io.Writer, spec wiring.WiringSpec, graph *irgraph.Graph, name string) error {
+	// name can be the name of a node, or the ID of a node within a namespace
+	var nodes []*irgraph.Node
+	if node := graph.Node(name); node != nil {
+		nodes = append(nodes, node)
+	} else {
+		for _, node := range graph.Nodes {
+			if node.Name == name {
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	def := spec.GetDef(name)
+	if len(nodes) == 0 && def == nil {
+		return blueprint.Errorf("%v is neither an IR node nor defined in the wiring spec", name)
+	}
+
+	if def != nil {
+		fmt.Fprintf(w, "%s\n", def.Name)
+		fmt.Fprintf(w, "  defined as: %s\n", defType(def))
+		fmt.Fprintf(w, "  defined at: %s\n", location(def.Callstack()))
+		if def.Name != name {
+			fmt.Fprintf(w, "  alias of:   %s\n", def.Name)
+		}
+		if ptr := pointer.GetPointer(spec, def.Name); ptr != nil {
+			fmt.Fprintf(w, "  pointer:    %s\n", ptr)
+		}
+	} else {
+		fmt.Fprintf(w, "%s\n  not defined in the wiring spec\n", name)
+	}
+
+	if len(nodes) == 0 {
+		fmt.Fprintf(w, "  not instantiated in the IR\n")
+	}
+	for _, node := range nodes {
+		fmt.Fprintf(w, "\n%s\n", node.ID)
+		fmt.Fprintf(w, "  type:      %s\n", node.Type)
+		fmt.Fprintf(w, "  kind:      %s\n", node.Kind)
+		fmt.Fprintf(w, "  namespace: %s\n", namespaceName(node.Namespace))
+
+		var keys []string
+		for key := range node.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		if len(keys) > 0 {
+			fmt.Fprintf(w, "  properties:\n")
+			for _, key := range keys {
+				fmt.Fprintf(w, "    %s = %s\n", key, node.Properties[key])
+			}
+		}
+
+		var dependencies, dependents []string
+		for _, edge := range graph.Edges {
+			if edge.From == node.ID {
+				dependencies = append(dependencies, fmt.Sprintf("%s (%s %s)", edge.To, edge.Kind, edge.Field))
+			}
+			if edge.To == node.ID {
+				dependents = append(dependents, fmt.Sprintf("%s (%s %s)", edge.From, edge.Kind, edge.Field))
+			}
+		}
+		if len(dependencies) > 0 {
+			fmt.Fprintf(w, "  depends on:\n    %s\n", strings.Join(dependencies, "\n    "))
+		}
+		if len(dependents) > 0 {
+			fmt.Fprintf(w, "  used by:\n    %s\n", strings.Join(dependents, "\n    "))
+		}
+		if children := graph.Children(node.ID); len(children) > 0 {
+			var names []string
+			for _, child := range children {
+				names = append(names, child.Name)
+			}
+			fmt.Fprintf(w, "  contains:\n    %s\n", strings.Join(names, "\n    "))
+		}
+	}
+	return nil
+}
+
+func showChain(w io.Writer, spec wiring.WiringSpec, service string) error {
+	ptr := pointer.GetPointer(spec, service)
+	if ptr == nil {
+		return blueprint.Errorf("%v is not a service with a pointer, expected one of:\n  %v", service, strings.Join(pointer.GetAllPointers(spec), "\n  "))
+	}
+
+	fmt.Fprintf(w, "%s\n", ptr.Name())
+	if def := spec.GetDef(ptr.Name()); def != nil {
+		fmt.Fprintf(w, "  defined at: %s\n", location(def.Callstack()))
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "\nclient side, in the order callers invoke them:\n")
+	if src := ptr.SrcModifiers(); len(src) == 0 {
+		fmt.Fprintf(tw, "  (none)\n")
+	} else {
+		writeModifiers(tw, spec, ptr, src)
+	}
+	fmt.Fprintf(tw, "\nserver side, in the order requests reach them:\n")
+	writeModifiers(tw, spec, ptr, ptr.DstModifiers())
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\n%s\n", ptr)
+	return nil
+}
+
+func writeModifiers(w io.Writer, spec wiring.WiringSpec, ptr *pointer.PointerDef, modifiers []string) {
+	for i, modifier := range modifiers {
+		def := spec.GetDef(modifier)
+		callstack := ptr.ModifierCallstack(modifier)
+		if callstack == nil && def != nil {
+			callstack = def.Callstack()
+		}
+		name := modifier
+		if def != nil && def.Name != modifier {
+			name = fmt.Sprintf("%s (alias of %s)", modifier, def.Name)
+		}
+		fmt.Fprintf(w, "  %d.\t%s\t%s\t%s\n", i+1, name, defType(def), location(callstack))
+	}
+}
+
+// Returns the type of node that def builds
+func defType(def *wiring.WiringDef) string {
+	if def == nil {
+		return "undefined"
+	}
+	if t := reflect.TypeOf(def.NodeType); t != nil {
+		return t.String()
+	}
+	if t := reflect.TypeOf(def.Options.ReturnType); t != nil {
+		return t.String()
+	}
+	return "unknown type"
+}
+
+// Returns the file and line of the wiring spec that the callstack was captured from
+func location(callstack *logging.Callstack) string {
+	callsite, ok := callstack.WiringCallsite()
+	if !ok || callsite.Source == nil {
+		return "unknown location"
+	}
+	return fmt.Sprintf("%s:%v", callsite.Source.WorkspaceFilename, callsite.LineNumber)
+}
+
+func namespaceName(id string) string {
+	if id == "" {
+		return "(application)"
+	}
+	return id
+}
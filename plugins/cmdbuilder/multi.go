@@ -0,0 +1,124 @@
+package cmdbuilder
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"golang.org/x/exp/slog"
+)
+
+// The outcome of compiling one wiring spec with [CmdBuilder.BuildEach]
+type SpecResult struct {
+	Name      string
+	OutputDir string
+	Duration  time.Duration
+	Err       error // nil if the spec compiled successfully
+}
+
+// Parses the value of -w when it selects more than one spec: either a comma-separated
+// list of spec names, or "all" for every registered spec.
+func (b *CmdBuilder) selectSpecs(names string) ([]SpecOption, error) {
+	if names == "all" {
+		var specs []SpecOption
+		for _, spec := range b.Registry {
+			specs = append(specs, spec)
+		}
+		sort.Slice(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+		return specs, nil
+	}
+
+	var specs []SpecOption
+	seen := make(map[string]struct{})
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if _, duplicate := seen[name]; name == "" || duplicate {
+			continue
+		}
+		seen[name] = struct{}{}
+		spec, exists := b.Registry[name]
+		if !exists {
+			return nil, blueprint.Errorf("unknown wiring spec \"%v\", expected one of:\n%v", name, b.List())
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// Compiles each of b.Specs to a subdirectory of b.OutputDir named after the spec.  Each spec is built
+// from a fresh wiring spec.  A spec that fails to compile does not prevent the remaining specs from
+// being compiled; once all specs have been attempted, a summary table is written to w and an error is
+// returned if any spec failed.
+//
+// If -dot, -json, or -save-ir were specified, each spec's IR graph is written to a file with the spec's name
+// inserted before the extension, e.g. app-docker.dot.
+func (b *CmdBuilder) BuildEach(w io.Writer) ([]SpecResult, error) {
+	var results []SpecResult
+	failed := 0
+	for _, spec := range b.Specs {
+		o := *b
+		o.SpecName = spec.Name
+		o.Spec = spec
+		o.Specs = nil
+		o.OutputDir = filepath.Join(b.OutputDir, spec.Name)
+		o.DOTFile = specFile(b.DOTFile, spec.Name)
+		o.JSONFile = specFile(b.JSONFile, spec.Name)
+		o.SaveIRFile = specFile(b.SaveIRFile, spec.Name)
+
+		start := time.Now()
+		err := o.buildRecovered()
+		result := SpecResult{Name: spec.Name, OutputDir: o.OutputDir, Duration: time.Since(start), Err: err}
+		if err != nil {
+			failed++
+			slog.Error(fmt.Sprintf("Failed to compile %v-%v: %v", b.Name, spec.Name, err.Error()))
+		}
+		results = append(results, result)
+	}
+
+	if err := writeSummary(w, results); err != nil {
+		return results, err
+	}
+	if failed > 0 {
+		return results, blueprint.Errorf("%v of %v wiring specs failed to compile", failed, len(results))
+	}
+	return results, nil
+}
+
+// Builds the spec, converting a panic within the spec or a plugin into an error so that
+// the remaining specs can still be compiled.
+func (b *CmdBuilder) buildRecovered() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = blueprint.Errorf("panic while compiling %v-%v: %v", b.Name, b.SpecName, r)
+		}
+	}()
+	return b.Build()
+}
+
+// Inserts the spec name into filename before its extension
+func specFile(filename, specName string) string {
+	if filename == "" {
+		return ""
+	}
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "-" + specName + ext
+}
+
+func writeSummary(w io.Writer, results []SpecResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SPEC\tTIME\tOUTPUT\tRESULT")
+	for _, result := range results {
+		status := "ok"
+		if result.Err != nil {
+			// Only the first line; the full error has already been logged
+			status = "FAILED: " + strings.SplitN(result.Err.Error(), "\n", 2)[0]
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", result.Name, result.Duration.Round(time.Millisecond), result.OutputDir, status)
+	}
+	return tw.Flush()
+}
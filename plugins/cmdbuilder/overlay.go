@@ -0,0 +1,100 @@
+package cmdbuilder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+)
+
+// An overlay applies deployment choices, such as retries, tracing, or container placement, on top of
+// a base wiring spec.  This allows several variants of an application to share a single base spec that
+// defines the application's services, rather than duplicating the base spec for each variant.
+//
+// Apply is called after the base spec has been built.  nodes are the nodes that the base spec (and any
+// preceding overlays) would instantiate; Apply adds its modifiers to those nodes and returns the nodes
+// that should be instantiated instead, e.g. the names of containers that the nodes were placed in.
+//
+// When running the program, overlays can be selected by specifying their [Name] with the -overlay flag,
+// e.g.
+//
+//	-overlay retries,containers
+type Overlay struct {
+	Name        string
+	Description string
+	Apply       func(spec wiring.WiringSpec, nodes []string) ([]string, error)
+}
+
+// Returns a wiring spec option that builds base and then applies each of overlays in order.  The
+// returned spec is named after base and the overlays, e.g. basic+retries+containers; its name can be
+// replaced to register a fixed combination of overlays as a spec of its own:
+//
+//	var Docker = cmdbuilder.WithOverlays(Basic, Retries, Tracing, Containers)
+//	Docker.Name = "docker"
+func WithOverlays(base SpecOption, overlays ...Overlay) SpecOption {
+	if len(overlays) == 0 {
+		return base
+	}
+	spec := SpecOption{
+		Name:        base.Name,
+		Description: base.Description,
+	}
+	var descriptions []string
+	for _, overlay := range overlays {
+		spec.Name += "+" + overlay.Name
+		descriptions = append(descriptions, overlay.Name)
+	}
+	spec.Description += fmt.Sprintf(" With overlays %v.", strings.Join(descriptions, ", "))
+	spec.Build = func(wiringSpec wiring.WiringSpec) ([]string, error) {
+		nodes, err := base.Build(wiringSpec)
+		if err != nil {
+			return nil, err
+		}
+		for _, overlay := range overlays {
+			nodes, err = overlay.Apply(wiringSpec, nodes)
+			if err != nil {
+				return nil, blueprint.Errorf("unable to apply overlay %v to %v due to %v", overlay.Name, base.Name, err.Error())
+			}
+		}
+		return nodes, nil
+	}
+	return spec
+}
+
+// Registers overlays that can be selected with the -overlay flag
+func (b *CmdBuilder) AddOverlays(overlays ...Overlay) {
+	for _, overlay := range overlays {
+		b.OverlayRegistry[overlay.Name] = overlay
+	}
+}
+
+// Returns a list of registered overlays
+func (builder *CmdBuilder) ListOverlays() string {
+	var overlays []string
+	for _, overlay := range builder.OverlayRegistry {
+		overlays = append(overlays, fmt.Sprintf("  %v: %v\n", overlay.Name, overlay.Description))
+	}
+	sort.Strings(overlays)
+	return strings.Join(overlays, "")
+}
+
+// Parses the value of -overlay, a comma-separated list of overlay names, into the overlays to apply in order
+func (b *CmdBuilder) selectOverlays(names string) ([]Overlay, error) {
+	var overlays []Overlay
+	seen := make(map[string]struct{})
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if _, duplicate := seen[name]; name == "" || duplicate {
+			continue
+		}
+		seen[name] = struct{}{}
+		overlay, exists := b.OverlayRegistry[name]
+		if !exists {
+			return nil, blueprint.Errorf("unknown overlay \"%v\", expected one of:\n%v", name, b.ListOverlays())
+		}
+		overlays = append(overlays, overlay)
+	}
+	return overlays, nil
+}
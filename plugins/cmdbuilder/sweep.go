@@ -0,0 +1,227 @@
+package cmdbuilder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint/logging"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"golang.org/x/exp/slog"
+)
+
+// The name of the manifest file that [CmdBuilder.BuildSweep] writes to the output directory
+const SweepManifestFile = "sweep.json"
+
+// A wiring spec that is parameterized, e.g. by the number of retries or a timeout, for use with
+// [CmdBuilder.BuildSweep].  Build is called once for each combination of parameter values.
+type SweepSpec struct {
+	Name        string
+	Description string
+	Build       func(spec wiring.WiringSpec, params Params) ([]string, error)
+}
+
+// One parameter of a [SweepSpec] and the values to sweep it over, e.g.
+//
+//	cmdbuilder.Parameter{Name: "retries", Values: []any{0, 1, 2, 3, 4, 5}}
+type Parameter struct {
+	Name   string `json:"name"`
+	Values []any  `json:"values"`
+}
+
+// The parameter values of one variant of a [SweepSpec], keyed by parameter name.  Values have the
+// same type as in the [Parameter] they came from, e.g.
+//
+//	retries := params["retries"].(int)
+type Params map[string]any
+
+// One combination of parameter values of a [SweepSpec]
+type Variant struct {
+	Spec   SpecOption
+	Params Params
+}
+
+// The manifest written by [CmdBuilder.BuildSweep], describing the parameters of each variant
+type SweepManifest struct {
+	Application string            `json:"application"`
+	Spec        string            `json:"spec"`
+	Parameters  []Parameter       `json:"parameters"`
+	Variants    []VariantManifest `json:"variants"`
+}
+
+type VariantManifest struct {
+	Name      string `json:"name"`
+	OutputDir string `json:"output_dir"` // Relative to the directory of the manifest
+	Params    Params `json:"params"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Returns every combination of the values of grid, as variants of spec.  Variants are ordered with
+// the last parameter varying fastest, and are named after the spec and their parameter values, e.g.
+// myspec_retries-3_timeout-100ms.
+func Sweep(spec SweepSpec, grid ...Parameter) ([]Variant, error) {
+	seen := make(map[string]struct{})
+	for _, param := range grid {
+		if param.Name == "" {
+			return nil, blueprint.Errorf("sweep of %v has a parameter with no name", spec.Name)
+		}
+		if _, duplicate := seen[param.Name]; duplicate {
+			return nil, blueprint.Errorf("sweep of %v has parameter %v more than once", spec.Name, param.Name)
+		}
+		seen[param.Name] = struct{}{}
+		if len(param.Values) == 0 {
+			return nil, blueprint.Errorf("sweep of %v has no values for parameter %v", spec.Name, param.Name)
+		}
+	}
+
+	variants := []Variant{{Params: Params{}}}
+	for _, param := range grid {
+		var next []Variant
+		for _, variant := range variants {
+			for _, value := range param.Values {
+				params := make(Params, len(variant.Params)+1)
+				for k, v := range variant.Params {
+					params[k] = v
+				}
+				params[param.Name] = value
+				next = append(next, Variant{Params: params})
+			}
+		}
+		variants = next
+	}
+
+	names := make(map[string]struct{})
+	for i := range variants {
+		params := variants[i].Params
+		name := variantName(spec.Name, grid, params)
+		if _, duplicate := names[name]; duplicate {
+			return nil, blueprint.Errorf("sweep of %v has more than one variant named %v; parameter values must be distinct", spec.Name, name)
+		}
+		names[name] = struct{}{}
+		variants[i].Spec = SpecOption{
+			Name:        name,
+			Description: fmt.Sprintf("%v with %v", spec.Name, describeParams(grid, params)),
+			Build: func(wiringSpec wiring.WiringSpec) ([]string, error) {
+				return spec.Build(wiringSpec, params)
+			},
+		}
+	}
+	return variants, nil
+}
+
+// Compiles every variant of spec over the values of grid, each to a subdirectory of b.OutputDir named after
+// the variant; see [Sweep] and [CmdBuilder.BuildEach].  Afterwards, a manifest describing each variant's
+// parameters, output directory, and any error is written to [SweepManifestFile] in b.OutputDir.  The manifest
+// is written even if some variants failed to compile.
+func (b *CmdBuilder) BuildSweep(w io.Writer, spec SweepSpec, grid ...Parameter) ([]SpecResult, error) {
+	variants, err := Sweep(spec, grid...)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info(fmt.Sprintf("Compiling %v variants of %v-%v", len(variants), b.Name, spec.Name))
+
+	o := *b
+	o.Specs = nil
+	for _, variant := range variants {
+		o.Specs = append(o.Specs, variant.Spec)
+	}
+	results, buildErr := o.BuildEach(w)
+
+	manifest := SweepManifest{Application: b.Name, Spec: spec.Name}
+	for _, param := range grid {
+		p := Parameter{Name: param.Name}
+		for _, value := range param.Values {
+			p.Values = append(p.Values, manifestValue(value))
+		}
+		manifest.Parameters = append(manifest.Parameters, p)
+	}
+	for i, result := range results {
+		v := VariantManifest{
+			Name:      result.Name,
+			OutputDir: result.Name,
+			Params:    make(Params),
+		}
+		for k, value := range variants[i].Params {
+			v.Params[k] = manifestValue(value)
+		}
+		if result.Err != nil {
+			v.Error = result.Err.Error()
+		}
+		manifest.Variants = append(manifest.Variants, v)
+	}
+	if err := writeSweepManifest(filepath.Join(b.OutputDir, SweepManifestFile), &manifest); err != nil {
+		return results, err
+	}
+	return results, buildErr
+}
+
+// Parses command line flags and compiles every variant of spec over the values of grid to the output
+// directory specified with -o.  The -w and -f flags are not used.
+func MakeAndExecuteSweep(name string, spec SweepSpec, grid ...Parameter) {
+	builder := NewCmdBuilder(name)
+	builder.ParseArgs()
+	if builder.OutputDir == "" {
+		slog.Error("output directory not specified, specify with -o")
+		os.Exit(1)
+	}
+	if builder.Quiet {
+		slog.Info("Suppressing compiler logging")
+		logging.DisableCompilerLogging()
+	}
+
+	if _, err := builder.BuildSweep(os.Stdout, spec, grid...); err != nil {
+		slog.Error(err.Error())
+		os.Exit(2)
+	}
+}
+
+func writeSweepManifest(filename string, manifest *SweepManifest) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return blueprint.Errorf("unable to write sweep manifest %v due to %v", filename, err.Error())
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return blueprint.Errorf("unable to write sweep manifest %v due to %v", filename, err.Error())
+	}
+	if err := os.WriteFile(filename, append(data, '\n'), 0644); err != nil {
+		return blueprint.Errorf("unable to write sweep manifest %v due to %v", filename, err.Error())
+	}
+	slog.Info(fmt.Sprintf("Wrote sweep manifest to %v", filename))
+	return nil
+}
+
+// Values such as durations are written to the manifest as they are printed, e.g. 100ms rather than 100000000
+func manifestValue(value any) any {
+	if stringer, isStringer := value.(fmt.Stringer); isStringer {
+		return stringer.String()
+	}
+	return value
+}
+
+// Names a variant after the spec and its parameter values, using only characters that are safe in a directory name
+func variantName(specName string, grid []Parameter, params Params) string {
+	name := specName
+	for _, param := range grid {
+		name += "_" + param.Name + "-" + fmt.Sprint(params[param.Name])
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '-'
+		}
+	}, name)
+}
+
+func describeParams(grid []Parameter, params Params) string {
+	var values []string
+	for _, param := range grid {
+		values = append(values, fmt.Sprintf("%v=%v", param.Name, params[param.Name]))
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,352 @@
+// Package declarative loads wiring specs that are written in YAML or JSON rather than in Go.
+//
+// A declarative wiring spec is a list of steps.  Each step calls a wiring function that a plugin
+// has registered with [Register], e.g. "workflow.Service" or "grpc.Deploy".  Steps are applied
+// in order to a [wiring.WiringSpec], exactly as if the equivalent Go wiring spec had been run.
+// Deployment variants can thus be written and changed without recompiling the wiring binary.
+//
+// # Format
+//
+//	instantiate: [leaf_proc, nonleaf_proc]
+//	steps:
+//	  - call: workflow.Service
+//	    name: leaf
+//	    options: {type: github.com/blueprint-uservices/blueprint/examples/leaf/workflow/leaf.LeafServiceImpl}
+//	  - call: workflow.Service
+//	    name: nonleaf
+//	    args: [leaf]
+//	    options: {type: github.com/blueprint-uservices/blueprint/examples/leaf/workflow/leaf.NonLeafService}
+//	  - call: retries.AddRetries
+//	    apply: [leaf]
+//	    options: {max_retries: 3}
+//	  - call: grpc.Deploy
+//	    apply: [leaf, nonleaf]
+//	  - call: goproc.Deploy
+//	    apply: [leaf, nonleaf]
+//
+// Each step has the following fields:
+//   - call: the registered name of the wiring function
+//   - name: the name of the node that the step defines, for functions that define a node
+//   - args: names of other nodes, for functions that take a variable number of nodes
+//     (e.g. the constructor arguments of a service, or the children of a process)
+//   - apply: names of nodes that the function is applied to, one at a time
+//   - options: any other arguments of the wiring function
+//
+// instantiate lists the nodes to build.  If it is omitted, the outermost nodes defined by
+// the steps are built; see [Spec.Apply].
+//
+// JSON uses the same field names.
+//
+// # Plugins
+//
+// Plugins register their wiring functions in an init function, typically using one of the
+// helpers [Apply], [ApplyAndDefine], [Define], or [DefineWith]:
+//
+//	func init() {
+//		declarative.Register("grpc.Deploy", declarative.Apply(Deploy))
+//	}
+//
+// A plugin's wiring functions are only available if the plugin's package is imported by the
+// wiring binary.  A wiring binary that only loads declarative specs can import plugins anonymously,
+// e.g.
+//
+//	import _ "github.com/blueprint-uservices/blueprint/plugins/grpc"
+package declarative
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"golang.org/x/exp/slog"
+	"gopkg.in/yaml.v3"
+)
+
+// A declarative wiring spec
+type Spec struct {
+	Instantiate []string `json:"instantiate,omitempty" yaml:"instantiate,omitempty"`
+	Steps       []Step   `json:"steps" yaml:"steps"`
+}
+
+// A single call to a wiring function
+type Step struct {
+	Call    string         `json:"call" yaml:"call"`
+	Name    string         `json:"name,omitempty" yaml:"name,omitempty"`
+	Args    []string       `json:"args,omitempty" yaml:"args,omitempty"`
+	Apply   []string       `json:"apply,omitempty" yaml:"apply,omitempty"`
+	Options map[string]any `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// A wiring function that can be called from a declarative wiring spec.  It should apply step
+// to spec and return the names of any nodes that the step defined.
+type WiringFunc func(spec wiring.WiringSpec, step Step) ([]string, error)
+
+var registry = make(map[string]WiringFunc)
+
+// Registers a wiring function under name, so that it can be called by the steps of a declarative
+// wiring spec.  By convention, name is the package and function name of the Go wiring function,
+// e.g. "grpc.Deploy".
+func Register(name string, f WiringFunc) {
+	registry[name] = f
+	slog.Info(fmt.Sprintf("%v registered as a declarative wiring function", name))
+}
+
+// Returns the names of all registered wiring functions, sorted
+func Registered() []string {
+	var names []string
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Parses a declarative wiring spec from a file.  Files ending in .json are parsed as JSON;
+// all other files are parsed as YAML.
+func ReadFile(filename string) (*Spec, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, blueprint.Errorf("unable to read declarative wiring spec %v due to %v", filename, err.Error())
+	}
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		return ParseJSON(data)
+	}
+	return ParseYAML(data)
+}
+
+// Parses a declarative wiring spec from YAML
+func ParseYAML(data []byte) (*Spec, error) {
+	var spec Spec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, blueprint.Errorf("invalid declarative wiring spec: %v", err.Error())
+	}
+	return &spec, nil
+}
+
+// Parses a declarative wiring spec from JSON
+func ParseJSON(data []byte) (*Spec, error) {
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, blueprint.Errorf("invalid declarative wiring spec: %v", err.Error())
+	}
+	return &spec, nil
+}
+
+// Applies every step of the declarative spec to the wiring spec, in order.
+//
+// Returns the nodes to instantiate, which can be passed directly to [wiring.WiringSpec.BuildIR].
+// If the spec has an instantiate list then that is returned.  Otherwise, returns the nodes defined
+// by steps that weren't subsequently used to define another node; e.g. a service that is later
+// deployed to a process is not returned, but the process is.
+func (d *Spec) Apply(spec wiring.WiringSpec) ([]string, error) {
+	var defined []string
+	consumed := make(map[string]struct{})
+	for i, step := range d.Steps {
+		f, exists := registry[step.Call]
+		if !exists {
+			return nil, blueprint.Errorf("step %v: unknown wiring function %q; registered functions are %v", i+1, step.Call, strings.Join(Registered(), ", "))
+		}
+		names, err := f(spec, step)
+		if err != nil {
+			return nil, blueprint.Errorf("step %v (%v): %v", i+1, step.Call, err.Error())
+		}
+		if len(names) > 0 {
+			for _, name := range append(append([]string{}, step.Args...), step.Apply...) {
+				consumed[name] = struct{}{}
+			}
+		}
+		defined = append(defined, names...)
+	}
+	if len(d.Instantiate) > 0 {
+		return d.Instantiate, nil
+	}
+	var toInstantiate []string
+	for _, name := range defined {
+		if _, isConsumed := consumed[name]; !isConsumed {
+			toInstantiate = append(toInstantiate, name)
+		}
+	}
+	return toInstantiate, nil
+}
+
+// Returns a wiring spec build function, of the kind used by cmdbuilder, that reads the
+// declarative spec in filename and applies it.
+func BuildFunc(filename string) func(wiring.WiringSpec) ([]string, error) {
+	return func(spec wiring.WiringSpec) ([]string, error) {
+		d, err := ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		return d.Apply(spec)
+	}
+}
+
+// Converts a wiring function that modifies a node, such as grpc.Deploy, into a [WiringFunc] that
+// calls f on every node in the step's apply list.
+func Apply(f func(spec wiring.WiringSpec, nodeName string)) WiringFunc {
+	return func(spec wiring.WiringSpec, step Step) ([]string, error) {
+		if len(step.Apply) == 0 {
+			return nil, blueprint.Errorf("expected the nodes to apply to in apply")
+		}
+		for _, name := range step.Apply {
+			f(spec, name)
+		}
+		return nil, nil
+	}
+}
+
+// Converts a wiring function that wraps a node in a new node, such as goproc.Deploy, into a [WiringFunc]
+// that calls f on every node in the step's apply list.  The new nodes are defined by the step.
+func ApplyAndDefine(f func(spec wiring.WiringSpec, nodeName string) string) WiringFunc {
+	return func(spec wiring.WiringSpec, step Step) ([]string, error) {
+		if len(step.Apply) == 0 {
+			return nil, blueprint.Errorf("expected the nodes to apply to in apply")
+		}
+		var defined []string
+		for _, name := range step.Apply {
+			defined = append(defined, f(spec, name))
+		}
+		return defined, nil
+	}
+}
+
+// Converts a wiring function that defines a new node, such as simple.NoSQLDB, into a [WiringFunc].
+// The node is named by the step's name.
+func Define(f func(spec wiring.WiringSpec, name string) string) WiringFunc {
+	return func(spec wiring.WiringSpec, step Step) ([]string, error) {
+		if step.Name == "" {
+			return nil, blueprint.Errorf("expected the name of the node to define in name")
+		}
+		return []string{f(spec, step.Name)}, nil
+	}
+}
+
+// Converts a wiring function that defines a new node from other nodes, such as goproc.CreateProcess,
+// into a [WiringFunc].  The node is named by the step's name, and the other nodes are the step's args.
+func DefineWith(f func(spec wiring.WiringSpec, name string, args ...string) string) WiringFunc {
+	return func(spec wiring.WiringSpec, step Step) ([]string, error) {
+		if step.Name == "" {
+			return nil, blueprint.Errorf("expected the name of the node to define in name")
+		}
+		return []string{f(spec, step.Name, step.Args...)}, nil
+	}
+}
+
+// Returns the option called key as a string.  Numbers and booleans are converted
+// to strings.  Returns an error if the option is missing.
+func (step Step) GetString(key string) (string, error) {
+	v, exists := step.Options[key]
+	if !exists {
+		return "", blueprint.Errorf("missing option %v", key)
+	}
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case int, int64, float64, bool:
+		return fmt.Sprint(v), nil
+	}
+	return "", blueprint.Errorf("option %v should be a string, got %v", key, v)
+}
+
+// Returns the option called key as an integer.  Returns an error if the option
+// is missing or is not an integer.
+func (step Step) GetInt(key string) (int64, error) {
+	v, exists := step.Options[key]
+	if !exists {
+		return 0, blueprint.Errorf("missing option %v", key)
+	}
+	switch v := v.(type) {
+	case int:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v), nil
+		}
+	case string:
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i, nil
+		}
+	}
+	return 0, blueprint.Errorf("option %v should be an integer, got %v", key, v)
+}
+
+// Returns the option called key as a float.  Returns an error if the option
+// is missing or is not a number.
+func (step Step) GetFloat(key string) (float64, error) {
+	v, exists := step.Options[key]
+	if !exists {
+		return 0, blueprint.Errorf("missing option %v", key)
+	}
+	switch v := v.(type) {
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f, nil
+		}
+	}
+	return 0, blueprint.Errorf("option %v should be a number, got %v", key, v)
+}
+
+// Returns the option called key as a bool.  Returns an error if the option
+// is missing or is not a bool.
+func (step Step) GetBool(key string) (bool, error) {
+	v, exists := step.Options[key]
+	if !exists {
+		return false, blueprint.Errorf("missing option %v", key)
+	}
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b, nil
+		}
+	}
+	return false, blueprint.Errorf("option %v should be true or false, got %v", key, v)
+}
+
+// Returns the option called key as a duration string, e.g. "500ms", checking that it is valid.
+// Returns an error if the option is missing or is not a valid duration.
+func (step Step) GetDuration(key string) (string, error) {
+	s, err := step.GetString(key)
+	if err != nil {
+		return "", err
+	}
+	if _, err := time.ParseDuration(s); err != nil {
+		return "", blueprint.Errorf("option %v should be a duration such as 500ms, got %v", key, s)
+	}
+	return s, nil
+}
+
+// Returns an error if the step has any options other than those listed in keys
+func (step Step) CheckOptions(keys ...string) error {
+	allowed := make(map[string]struct{})
+	for _, key := range keys {
+		allowed[key] = struct{}{}
+	}
+	var unknown []string
+	for key := range step.Options {
+		if _, ok := allowed[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return blueprint.Errorf("unknown options %v; expected %v", strings.Join(unknown, ", "), strings.Join(keys, ", "))
+	}
+	return nil
+}
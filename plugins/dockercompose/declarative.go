@@ -0,0 +1,9 @@
+package dockercompose
+
+import (
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("dockercompose.NewDeployment", declarative.DefineWith(NewDeployment))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"reflect"
+	"sync"
 
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint/ioutil"
@@ -45,6 +46,8 @@ type (
 		InstanceArgs map[string][]ir.IRNode // argnodes for each instance added to the workspace
 
 		DockerComposeFile *dockergen.DockerComposeFile
+
+		lock sync.Mutex // Guards the above, since container images can be generated concurrently
 	}
 )
 
@@ -60,11 +63,15 @@ The basic build process of a docker-compose deployment
 */
 func (node *Deployment) generateArtifacts(workspace *dockerComposeWorkspace) error {
 
-	// Add any locally-built container images
-	for _, node := range ir.Filter[docker.ProvidesContainerImage](node.Nodes) {
-		if err := node.AddContainerArtifacts(workspace); err != nil {
-			return err
-		}
+	// Add any locally-built container images.  Each image has its own subdirectory,
+	// so the images are generated concurrently.
+	var tasks []func() error
+	for _, image := range ir.Filter[docker.ProvidesContainerImage](node.Nodes) {
+		image := image
+		tasks = append(tasks, func() error { return image.AddContainerArtifacts(workspace) })
+	}
+	if err := ir.RunConcurrently(tasks); err != nil {
+		return err
 	}
 
 	// Collect all container instances
@@ -103,6 +110,8 @@ func (d *dockerComposeWorkspace) Info() docker.ContainerWorkspaceInfo {
 func (d *dockerComposeWorkspace) CreateImageDir(imageName string) (string, error) {
 	// Only alphanumeric and underscores are allowed in an proc name
 	imageName = ir.CleanName(imageName)
+	d.lock.Lock()
+	defer d.lock.Unlock()
 	imageDir, err := ioutil.CreateNodeDir(d.info.Path, imageName)
 	d.ImageDirs[imageName] = imageDir
 	return imageDir, err
@@ -110,33 +119,45 @@ func (d *dockerComposeWorkspace) CreateImageDir(imageName string) (string, error
 
 // Implements docker.ContainerWorkspace
 func (d *dockerComposeWorkspace) DeclarePrebuiltInstance(instanceName string, image string, args ...ir.IRNode) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
 	d.InstanceArgs[instanceName] = args
 	return d.DockerComposeFile.AddImageInstance(instanceName, image)
 }
 
 // Implements docker.ContainerWorkspace
 func (d *dockerComposeWorkspace) DeclareLocalImage(instanceName string, imageDir string, args ...ir.IRNode) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
 	d.InstanceArgs[instanceName] = args
 	return d.DockerComposeFile.AddBuildInstance(instanceName, imageDir)
 }
 
 // Implements docker.ContainerWorkspace
 func (d *dockerComposeWorkspace) SetEnvironmentVariable(instanceName string, key string, val string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
 	return d.DockerComposeFile.AddEnvVar(instanceName, key, val)
 }
 
 // Implements docker.ContainerWorkspace
 func (d *dockerComposeWorkspace) SetCustomCommand(instanceName string, command []string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
 	return d.DockerComposeFile.SetCustomCommand(instanceName, command)
 }
 
 // Implements docker.ContainerWorkspace
 func (d *dockerComposeWorkspace) AddVolume(instanceName string, src string, dst string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
 	return d.DockerComposeFile.AddVolume(instanceName, src, dst)
 }
 
 // Implements docker.ContainerWorkspace
 func (d *dockerComposeWorkspace) AddCustomConf(instanceName string, src string, dst string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
 	return d.DockerComposeFile.AddCustomConf(instanceName, src, dst)
 }
 
@@ -4,6 +4,10 @@ import (
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
 )
 
+func init() {
+	ir.RegisterJSONType[*Deployment]()
+}
+
 // An IRNode representing a docker-compose deployment, which is simply a collection of
 // container instances.
 type Deployment struct {
@@ -0,0 +1,33 @@
+package faultinjector
+
+import (
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("faultinjector.AddRandomDelay", func(spec wiring.WiringSpec, step declarative.Step) ([]string, error) {
+		if err := step.CheckOptions("max_delay"); err != nil {
+			return nil, err
+		}
+		max_delay, err := step.GetInt("max_delay")
+		if err != nil {
+			return nil, err
+		}
+		return declarative.Apply(func(spec wiring.WiringSpec, serviceName string) {
+			AddRandomDelay(spec, serviceName, max_delay)
+		})(spec, step)
+	})
+	declarative.Register("faultinjector.AddProbabilisticFailures", func(spec wiring.WiringSpec, step declarative.Step) ([]string, error) {
+		if err := step.CheckOptions("probability"); err != nil {
+			return nil, err
+		}
+		probability, err := step.GetInt("probability")
+		if err != nil {
+			return nil, err
+		}
+		return declarative.Apply(func(spec wiring.WiringSpec, serviceName string) {
+			AddProbabilisticFailures(spec, serviceName, int(probability))
+		})(spec, step)
+	})
+}
@@ -9,6 +9,10 @@ import (
 	"github.com/blueprint-uservices/blueprint/plugins/golang/gocode"
 )
 
+func init() {
+	ir.RegisterJSONType[*RandomDelayServerWrapper]()
+}
+
 // Blueprint IR node representing a RandomDelayServerWrapper
 type RandomDelayServerWrapper struct {
 	golang.Service
@@ -20,7 +24,7 @@ type RandomDelayServerWrapper struct {
 
 	MaxDelay int64
 
-	outputPackage string
+	OutputPackage string
 }
 
 func (node *RandomDelayServerWrapper) ImplementsGolangNode() {}
@@ -52,7 +56,7 @@ func (node *RandomDelayServerWrapper) GenerateFuncs(builder golang.ModuleBuilder
 		return err
 	}
 
-	return generateServerHandler(builder, iface, service, node.outputPackage)
+	return generateServerHandler(builder, iface, service, node.OutputPackage)
 }
 
 func (node *RandomDelayServerWrapper) AddInstantiation(builder golang.NamespaceBuilder) error {
@@ -67,7 +71,7 @@ func (node *RandomDelayServerWrapper) AddInstantiation(builder golang.NamespaceB
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_RandomDelayHandler", iface.BaseName),
 			Arguments: []gocode.Variable{
@@ -85,7 +89,7 @@ func NewRandomDelayServerWrapper(name string, serverNode golang.Service, maxDela
 	node := &RandomDelayServerWrapper{}
 	node.InstanceName = name
 	node.Wrapped = serverNode
-	node.outputPackage = "delay"
+	node.OutputPackage = "delay"
 	node.MaxDelay = maxDelay
 
 	return node, nil
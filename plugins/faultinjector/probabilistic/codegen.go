@@ -29,7 +29,7 @@ func generateServerHandler(builder golang.ModuleBuilder, iface *gocode.ServiceIn
 
 	slog.Info(fmt.Sprintf("Generating %v/%v", server.Package.PackageName, iface.Name))
 	outputFile := filepath.Join(server.Package.Path, iface.Name+".go")
-	return gogen.ExecuteTemplateToFile("ProbabilisticFailureInjector", serverTemplate, server, outputFile)
+	return serverTemplate.ExecuteToFile(builder, server, outputFile)
 }
 
 type serverArgs struct {
@@ -41,7 +41,7 @@ type serverArgs struct {
 	Imports   *gogen.Imports
 }
 
-var serverTemplate = `// Blueprint: Auto-generated by the FaultInjector/probabilistic Plugin
+var serverTemplate = gogen.RegisterTemplate("faultinjector/probabilistic", "server", `// Blueprint: Auto-generated by the FaultInjector/probabilistic Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -80,4 +80,4 @@ func (handler *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.C
 	return handler.Service.{{$f.Name}}({{ArgVars $f "ctx"}})
 }
 {{end}}
-`
+`)
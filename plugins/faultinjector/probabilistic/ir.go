@@ -9,6 +9,10 @@ import (
 	"github.com/blueprint-uservices/blueprint/plugins/golang/gocode"
 )
 
+func init() {
+	ir.RegisterJSONType[*ServerWrapper]()
+}
+
 // Blueprint IR node representing a ServerWrapper
 type ServerWrapper struct {
 	golang.Service
@@ -20,7 +24,7 @@ type ServerWrapper struct {
 
 	Probability int
 
-	outputPackage string
+	OutputPackage string
 }
 
 func (node *ServerWrapper) ImplementsGolangNode() {}
@@ -52,7 +56,7 @@ func (node *ServerWrapper) GenerateFuncs(builder golang.ModuleBuilder) error {
 		return err
 	}
 
-	return generateServerHandler(builder, iface, service, node.outputPackage)
+	return generateServerHandler(builder, iface, service, node.OutputPackage)
 }
 
 func (node *ServerWrapper) AddInstantiation(builder golang.NamespaceBuilder) error {
@@ -67,7 +71,7 @@ func (node *ServerWrapper) AddInstantiation(builder golang.NamespaceBuilder) err
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_ProbabilisticFailureHandler", iface.BaseName),
 			Arguments: []gocode.Variable{
@@ -85,7 +89,7 @@ func NewServerWrapper(name string, serverNode golang.Service, probability int) (
 	node := &ServerWrapper{}
 	node.InstanceName = name
 	node.Wrapped = serverNode
-	node.outputPackage = "prob_failure"
+	node.OutputPackage = "prob_failure"
 	node.Probability = probability
 
 	return node, nil
@@ -32,7 +32,7 @@ func AddRandomDelay(spec wiring.WiringSpec, serviceName string, maxDelay int64)
 		slog.Error("Unable to add random delay node to " + serviceName)
 	}
 
-	serverNext := ptr.AddDstModifier(spec, serverWrapper)
+	serverNext := ptr.AddDstModifier(spec, serverWrapper, pointer.InProcessModifier)
 
 	spec.Define(serverWrapper, &delay.RandomDelayServerWrapper{}, func(ns wiring.Namespace) (ir.IRNode, error) {
 		var server golang.Service
@@ -63,7 +63,7 @@ func AddProbabilisticFailures(spec wiring.WiringSpec, serviceName string, probab
 		slog.Error("Unable to add probabilistic failures node to " + serviceName)
 	}
 
-	serverNext := ptr.AddDstModifier(spec, serverWrapper)
+	serverNext := ptr.AddDstModifier(spec, serverWrapper, pointer.InProcessModifier)
 
 	spec.Define(serverWrapper, &probabilistic.ServerWrapper{}, func(ns wiring.Namespace) (ir.IRNode, error) {
 		var server golang.Service
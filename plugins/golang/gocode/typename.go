@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"unicode"
 
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
 	"golang.org/x/tools/go/packages"
 )
 
@@ -67,6 +69,114 @@ func IsBasicType(name string) bool {
 	return ok
 }
 
+// Reports whether t is a channel of any direction and, if so, returns the type of its elements,
+// e.g. string for <-chan string
+func ChanElem(t TypeName) (TypeName, bool) {
+	switch c := t.(type) {
+	case *Chan:
+		return c.ChanOf, true
+	case *ReceiveChan:
+		return c.ReceiveType, true
+	case *SendChan:
+		return c.SendType, true
+	}
+	return nil, false
+}
+
+/*
+Returns a name for t that can be used as an identifier in generated IDL files, such as proto and
+thrift files, e.g. Workflow_Page_Workflow_Item for workflow.Page[workflow.Item].
+
+User types are qualified by the last element of their package path, so that types with the same name
+in different packages get different names.  Each part of the name is capitalized, because protoc and
+thrift remove underscores that are followed by lowercase letters.
+
+Returns an error if t cannot be named, e.g. because it is an interface or func type, or because its
+package name cannot be made into an identifier.
+*/
+func IdentifierName(t TypeName) (string, error) {
+	var name string
+	switch arg := t.(type) {
+	case *UserType:
+		name = identifierPart(shortName(arg.Package)) + "_" + arg.Name
+	case *BasicType:
+		name = identifierPart(arg.Name)
+	case *GenericType:
+		base, err := IdentifierName(arg.BaseType)
+		if err != nil {
+			return "", err
+		}
+		parts := []string{base}
+		for _, typeArg := range arg.TypeArgs {
+			argName, err := IdentifierName(typeArg)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, argName)
+		}
+		name = strings.Join(parts, "_")
+	case *Pointer:
+		elem, err := IdentifierName(arg.PointerTo)
+		if err != nil {
+			return "", err
+		}
+		name = "Ptr_" + elem
+	case *Slice:
+		elem, err := IdentifierName(arg.SliceOf)
+		if err != nil {
+			return "", err
+		}
+		name = "List_" + elem
+	case *Map:
+		key, err := IdentifierName(arg.KeyType)
+		if err != nil {
+			return "", err
+		}
+		value, err := IdentifierName(arg.ValueType)
+		if err != nil {
+			return "", err
+		}
+		name = "Map_" + key + "_" + value
+	default:
+		return "", blueprint.Errorf("%v cannot be named in generated code", t)
+	}
+	if !isIdentifier(name) {
+		return "", blueprint.Errorf("%v cannot be named in generated code because %v is not a valid identifier", t, name)
+	}
+	return name, nil
+}
+
+// Capitalizes s and removes characters that can't appear in identifiers, capitalizing the letters
+// that follow them, e.g. YamlV3 for yaml.v3
+func identifierPart(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, c := range s {
+		switch {
+		case c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c)):
+			if upper {
+				c = unicode.ToUpper(c)
+			}
+			b.WriteRune(c)
+			upper = false
+		default:
+			upper = true
+		}
+	}
+	return b.String()
+}
+
+func isIdentifier(s string) bool {
+	for i, c := range s {
+		isLetter := c < unicode.MaxASCII && (unicode.IsLetter(c) || c == '_')
+		isDigit := c < unicode.MaxASCII && unicode.IsDigit(c)
+		if !isLetter && !(isDigit && i > 0) {
+			return false
+		}
+	}
+	return s != ""
+}
+
 // Returns a [UserType] for type T,
 func TypeOf[T any]() TypeName {
 	return typeof(reflect.TypeOf(new(T)).Elem())
@@ -243,12 +353,14 @@ type (
 	}
 
 	/*
-		A struct with generics. For now blueprint doesn't support generics in service declarations
+		An instantiation of a generic struct, e.g. Page[Item] or Pair[string, int].
+
+		Use [InstantiateType] to get the types of the struct's fields for the instantiation.
 	*/
 	GenericType struct {
 		TypeName
-		BaseType  TypeName
-		TypeParam TypeName
+		BaseType TypeName   // The generic struct, e.g. Page
+		TypeArgs []TypeName // The type arguments of the instantiation, e.g. Item
 	}
 
 	// The type parameter of a generic struct or func
@@ -316,7 +428,11 @@ func (t *StructType) String() string {
 }
 
 func (t *GenericType) String() string {
-	return fmt.Sprintf("%v[%v]", t.BaseType, t.TypeParam)
+	var args []string
+	for _, arg := range t.TypeArgs {
+		args = append(args, arg.String())
+	}
+	return fmt.Sprintf("%v[%v]", t.BaseType, strings.Join(args, ", "))
 }
 
 func (t *GenericTypeParam) String() string {
@@ -478,7 +594,15 @@ func (t *GenericType) Equals(other TypeName) bool {
 	if !isSameType {
 		return false
 	}
-	return t.BaseType.Equals(t2.BaseType) && t.TypeParam.Equals(t2.TypeParam)
+	if !t.BaseType.Equals(t2.BaseType) || len(t.TypeArgs) != len(t2.TypeArgs) {
+		return false
+	}
+	for i := range t.TypeArgs {
+		if !t.TypeArgs[i].Equals(t2.TypeArgs[i]) {
+			return false
+		}
+	}
+	return true
 }
 
 func (t *GenericTypeParam) Equals(other TypeName) bool {
@@ -491,3 +615,47 @@ func (t *GenericTypeParam) Equals(other TypeName) bool {
 	}
 	return t.ParamName == t2.ParamName
 }
+
+/*
+Substitutes the type params of a generic struct or func in t with their type arguments.
+
+typeParams are the names of the type params, in declaration order, and typeArgs are the
+corresponding type arguments of an instantiation.  For example, if the field of a generic
+struct Page[T any] has type []T, then for the instantiation Page[Item] the field has type
+InstantiateType([]T, ["T"], [Item]), which is []Item.
+
+Type params that aren't in typeParams are left as-is.
+*/
+func InstantiateType(t TypeName, typeParams []string, typeArgs []TypeName) TypeName {
+	switch t := t.(type) {
+	case *GenericTypeParam:
+		for i, param := range typeParams {
+			if param == t.ParamName && i < len(typeArgs) {
+				return typeArgs[i]
+			}
+		}
+		return t
+	case *GenericType:
+		instantiated := &GenericType{BaseType: InstantiateType(t.BaseType, typeParams, typeArgs)}
+		for _, arg := range t.TypeArgs {
+			instantiated.TypeArgs = append(instantiated.TypeArgs, InstantiateType(arg, typeParams, typeArgs))
+		}
+		return instantiated
+	case *Slice:
+		return &Slice{SliceOf: InstantiateType(t.SliceOf, typeParams, typeArgs)}
+	case *Ellipsis:
+		return &Ellipsis{EllipsisOf: InstantiateType(t.EllipsisOf, typeParams, typeArgs)}
+	case *Pointer:
+		return &Pointer{PointerTo: InstantiateType(t.PointerTo, typeParams, typeArgs)}
+	case *Map:
+		return &Map{KeyType: InstantiateType(t.KeyType, typeParams, typeArgs), ValueType: InstantiateType(t.ValueType, typeParams, typeArgs)}
+	case *Chan:
+		return &Chan{ChanOf: InstantiateType(t.ChanOf, typeParams, typeArgs)}
+	case *ReceiveChan:
+		return &ReceiveChan{ReceiveType: InstantiateType(t.ReceiveType, typeParams, typeArgs)}
+	case *SendChan:
+		return &SendChan{SendType: InstantiateType(t.SendType, typeParams, typeArgs)}
+	default:
+		return t
+	}
+}
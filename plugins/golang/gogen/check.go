@@ -0,0 +1,121 @@
+package gogen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"golang.org/x/exp/slog"
+	"golang.org/x/tools/go/packages"
+)
+
+var (
+	generatedFilesLock sync.Mutex
+	generatedFiles     = make(map[string]string) // map from absolute filename to the template that generated it
+	typeCheck          = false
+)
+
+// Enables or disables type-checking of generated code.  If enabled, [WorkspaceBuilderImpl.Finish] type checks
+// every generated module of the workspace using [golang.org/x/tools/go/packages], and returns an error for any
+// problem found, attributed to the plugin and template that generated the offending file.  This catches bad
+// generated code at compile time, rather than when the code is built, e.g. in a Docker image.
+//
+// Type checking requires the dependencies of the generated modules to be available.  Defaults to false.
+func SetTypeCheck(enabled bool) {
+	typeCheck = enabled
+}
+
+// Records that filename was generated by the template described by origin
+func recordGenerated(filename string, origin string) {
+	if abs, err := filepath.Abs(filename); err == nil {
+		filename = abs
+	}
+	generatedFilesLock.Lock()
+	defer generatedFilesLock.Unlock()
+	generatedFiles[filename] = origin
+}
+
+// Returns a description of the template that generated filename, if it is known, for error messages
+func describeOrigin(filename string) string {
+	if abs, err := filepath.Abs(filename); err == nil {
+		filename = abs
+	}
+	generatedFilesLock.Lock()
+	defer generatedFilesLock.Unlock()
+	if origin, exists := generatedFiles[filename]; exists {
+		return " (generated by " + origin + ")"
+	}
+	return ""
+}
+
+// Formats every Go file in moduleDir with [go/format].  Returns an error if any file is not valid Go.
+func formatModule(moduleDir string) error {
+	var errs []string
+	err := filepath.WalkDir(moduleDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".go" {
+			return err
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		formatted, err := format.Source(src)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%v:%v%v", rel(path), err.Error(), describeOrigin(path)))
+			return nil
+		}
+		if bytes.Equal(src, formatted) {
+			return nil
+		}
+		return os.WriteFile(path, formatted, 0755)
+	})
+	if err != nil {
+		return blueprint.Errorf("unable to format generated module %v due to %v", moduleDir, err.Error())
+	}
+	if len(errs) > 0 {
+		return blueprint.Errorf("generated invalid Go code in module %v:\n  %v", moduleDir, strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+// Type checks the packages of moduleDir, using the go.work file of the workspace in workspaceDir.  Returns an error
+// listing every problem found.
+func typeCheckModule(workspaceDir string, moduleDir string) error {
+	slog.Info(fmt.Sprintf("Type checking %v", rel(moduleDir)))
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedDeps | packages.NeedImports,
+		Dir:  moduleDir,
+		Env:  append(os.Environ(), "GOWORK="+filepath.Join(workspaceDir, "go.work")),
+	}
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return blueprint.Errorf("unable to type check generated module %v due to %v", moduleDir, err.Error())
+	}
+
+	seen := make(map[string]bool)
+	var errs []string
+	for _, pkg := range pkgs {
+		for _, pkgErr := range pkg.Errors {
+			msg := pkgErr.Error()
+			if filename, _, found := strings.Cut(pkgErr.Pos, ":"); found {
+				msg += describeOrigin(filename)
+			}
+			if !seen[msg] {
+				seen[msg] = true
+				errs = append(errs, msg)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return blueprint.Errorf("generated code in module %v has errors:\n  %v", moduleDir, strings.Join(errs, "\n  "))
+	}
+	return nil
+}
@@ -95,6 +95,13 @@ func (imports *Imports) AddType(typeName gocode.TypeName) {
 			imports.AddType(t.KeyType)
 			imports.AddType(t.ValueType)
 		}
+	case *gocode.GenericType:
+		{
+			imports.AddType(t.BaseType)
+			for _, arg := range t.TypeArgs {
+				imports.AddType(arg)
+			}
+		}
 	case *gocode.Chan:
 		{
 			imports.AddType(t.ChanOf)
@@ -163,6 +170,14 @@ func (imports *Imports) NameOf(typeName gocode.TypeName) string {
 		{
 			return fmt.Sprintf("map[%s]%s", imports.NameOf(t.KeyType), imports.NameOf(t.ValueType))
 		}
+	case *gocode.GenericType:
+		{
+			var args []string
+			for _, arg := range t.TypeArgs {
+				args = append(args, imports.NameOf(arg))
+			}
+			return fmt.Sprintf("%s[%s]", imports.NameOf(t.BaseType), strings.Join(args, ", "))
+		}
 	case *gocode.Chan:
 		{
 			return "chan " + imports.NameOf(t.ChanOf)
@@ -89,13 +89,19 @@ func (module *ModuleBuilderImpl) CreatePackage(packageName string) (golang.Packa
 	}
 }
 
-// Implements [golang.ModuleBuilder]
+// Implements [golang.ModuleBuilder].
+//
+// If moduleName is required more than once, by this or other modules of the workspace, the highest
+// required version is used, unless the version has been pinned with [PinModuleVersion].
 func (module *ModuleBuilderImpl) Require(moduleName string, version string) error {
 	if version == "" {
 		return blueprint.Errorf("%s go.mod require needs a version for %s", module.Name, moduleName)
 	}
-	slog.Info(fmt.Sprintf("require %s %s", moduleName, version))
-	module.modfile.AddNewRequire(moduleName, version, false)
+	selected := module.workspace.require(moduleName, version, module.Name)
+	slog.Info(fmt.Sprintf("require %s %s", moduleName, selected))
+	if err := module.modfile.AddRequire(moduleName, selected); err != nil {
+		return err
+	}
 	return savemodfile(module.modfile, filepath.Join(module.ModuleDir, "go.mod"))
 }
 
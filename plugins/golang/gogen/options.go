@@ -0,0 +1,128 @@
+package gogen
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/golang"
+)
+
+func init() {
+	ir.RegisterJSONType[*Options]()
+}
+
+// The name of the [Options] node in a wiring spec
+const OptionsName = "gogen.options"
+
+// A plugin's template that a wiring spec overrides with [OverrideTemplate]
+type TemplateOverride struct {
+	Plugin string
+	Name   string
+	Body   string
+}
+
+// A module version that a wiring spec pins with [PinModuleVersion]
+type ModulePin struct {
+	Module  string
+	Version string
+}
+
+var (
+	templateOverrideProperty = wiring.DeclareProperty(wiring.Property[TemplateOverride]{Key: "gogen.TemplateOverride", Multi: true})
+	modulePinProperty        = wiring.DeclareProperty(wiring.Property[ModulePin]{Key: "gogen.ModulePin", Multi: true})
+)
+
+/*
+An IR node holding the template overrides and module version pins of a wiring spec.
+
+The node is only defined if the wiring spec calls [OverrideTemplate] or [PinModuleVersion].
+Plugins that generate golang workspaces, such as goproc, add it to their namespace with [AddOptions],
+so that it is saved with the rest of the IR.  When the workspace is generated, the node's
+AddToWorkspace configures the [WorkspaceBuilderImpl] with the overrides and pins.
+*/
+type Options struct {
+	golang.Node
+	golang.ProvidesModule
+	ir.IRMetadata
+
+	TemplateOverrides map[string]string // Map from plugin/name of a template to the body that overrides it
+	ModulePins        map[string]string // Map from FQ module name to its pinned version
+}
+
+// Implements [ir.IRNode]
+func (node *Options) Name() string {
+	return OptionsName
+}
+
+// Implements [ir.IRNode]
+func (node *Options) String() string {
+	var opts []string
+	for key := range node.TemplateOverrides {
+		opts = append(opts, key)
+	}
+	for module, version := range node.ModulePins {
+		opts = append(opts, module+"@"+version)
+	}
+	sort.Strings(opts)
+	return OptionsName + " = GolangOptions(" + strings.Join(opts, ", ") + ")"
+}
+
+// Implements [golang.Node]
+func (node *Options) ImplementsGolangNode() {}
+
+// Implements [ir.IRMetadata]
+func (node *Options) ImplementsIRMetadata() {}
+
+// Implements [golang.ProvidesModule]
+func (node *Options) AddToWorkspace(builder golang.WorkspaceBuilder) error {
+	workspace, isGogen := builder.(*WorkspaceBuilderImpl)
+	if !isGogen {
+		return blueprint.Errorf("%v can only configure a gogen workspace, not %T", OptionsName, builder)
+	}
+	for key, body := range node.TemplateOverrides {
+		workspace.TemplateOverrides[key] = body
+	}
+	for module, version := range node.ModulePins {
+		workspace.ModulePins[module] = version
+	}
+	return nil
+}
+
+// Defines the [Options] node, which is built from the properties set by [OverrideTemplate] and [PinModuleVersion]
+func defineOptions(spec wiring.WiringSpec) {
+	spec.Define(OptionsName, &Options{}, func(namespace wiring.Namespace) (ir.IRNode, error) {
+		node := &Options{
+			TemplateOverrides: make(map[string]string),
+			ModulePins:        make(map[string]string),
+		}
+		overrides, err := templateOverrideProperty.GetAll(namespace, OptionsName)
+		if err != nil {
+			return nil, err
+		}
+		for _, override := range overrides {
+			node.TemplateOverrides[templateKey(override.Plugin, override.Name)] = override.Body
+		}
+		pins, err := modulePinProperty.GetAll(namespace, OptionsName)
+		if err != nil {
+			return nil, err
+		}
+		for _, pin := range pins {
+			node.ModulePins[pin.Module] = pin.Version
+		}
+		return node, nil
+	})
+}
+
+// Adds the [Options] node to namespace, if the wiring spec overrides any templates or pins any module
+// versions.  Plugins that generate golang workspaces, such as goproc, call this when building their
+// namespace, so that the generated workspace uses the wiring spec's overrides and pins.
+func AddOptions(spec wiring.WiringSpec, namespace wiring.Namespace) error {
+	if def := spec.GetDef(OptionsName); def == nil || def.Build == nil {
+		return nil
+	}
+	var options ir.IRNode
+	return namespace.Get(OptionsName, &options)
+}
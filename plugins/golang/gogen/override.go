@@ -0,0 +1,127 @@
+package gogen
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/golang"
+)
+
+// A [text/template] template that a plugin generates code from.  Plugins declare their templates with
+// [RegisterTemplate], which enables projects to replace or extend them with [OverrideTemplate] instead
+// of forking the plugin.
+//
+// Templates are executed with the same helper functions as [ExecuteTemplate].
+type Template struct {
+	Plugin string // The plugin that registered the template, e.g. retries
+	Name   string // The name of the template, unique within the plugin, e.g. client
+	Body   string // The plugin's template
+}
+
+var (
+	templatesLock sync.RWMutex
+	templates     = make(map[string]*Template)
+)
+
+func templateKey(plugin, name string) string {
+	return plugin + "/" + name
+}
+
+// Registers a code-generation template of a plugin, so that it can be overridden, and returns it.
+// Plugins typically register their templates as package variables, e.g.
+//
+//	var clientTemplate = gogen.RegisterTemplate("retries", "client", `...`)
+//
+// and then execute them with [Template.ExecuteToFile].
+func RegisterTemplate(plugin, name, body string) *Template {
+	t := &Template{Plugin: plugin, Name: name, Body: body}
+	templatesLock.Lock()
+	defer templatesLock.Unlock()
+	templates[templateKey(plugin, name)] = t
+	return t
+}
+
+/*
+Overrides the template name of plugin with body, for the code generated for this wiring spec.  Call
+this from a wiring spec, e.g. to add logging to the clients generated by the retries plugin:
+
+	gogen.OverrideTemplate(spec, "retries", "client", myClientTemplate)
+
+The override is executed with the same arguments and helper functions, such as Imports and ArgVars,
+as the plugin's template.  To extend the plugin's template rather than replace it, the override can
+include it with {{template "default" .}}.
+
+The override is stored in the [Options] node of the spec, so it is saved with the IR and only applies
+to the workspaces generated for this spec.
+
+If plugin has no template called name, or body can't be parsed, an error is added to the spec.
+*/
+func OverrideTemplate(spec wiring.WiringSpec, plugin, name, body string) {
+	templatesLock.RLock()
+	defer templatesLock.RUnlock()
+
+	key := templateKey(plugin, name)
+	if _, exists := templates[key]; !exists {
+		spec.AddError(blueprint.Errorf("cannot override template %v of plugin %v because it does not exist; templates that can be overridden are:\n%v", name, plugin, listTemplates()))
+		return
+	}
+	if _, err := template.New(key).Funcs(newTemplateExecutor(nil).Funcs).Parse(body); err != nil {
+		spec.AddError(blueprint.Errorf("invalid override of template %v of plugin %v: %v", name, plugin, err.Error()))
+		return
+	}
+	templateOverrideProperty.Add(spec, OptionsName, TemplateOverride{Plugin: plugin, Name: name, Body: body})
+	defineOptions(spec)
+}
+
+// Requires templatesLock
+func listTemplates() string {
+	var names []string
+	for _, t := range templates {
+		names = append(names, fmt.Sprintf("  %v %v", t.Plugin, t.Name))
+	}
+	sort.Strings(names)
+	return strings.Join(names, "\n")
+}
+
+// Returns the body that overrides the template in the workspace of builder, or the empty string if it
+// hasn't been overridden
+func (t *Template) override(builder golang.ModuleBuilder) string {
+	if module, isGogen := builder.(*ModuleBuilderImpl); isGogen && module.workspace != nil {
+		return module.workspace.TemplateOverrides[templateKey(t.Plugin, t.Name)]
+	}
+	return ""
+}
+
+// Like [ExecuteTemplate], but executes the wiring spec's override of the template, if there is one
+// in the workspace of builder.
+func (t *Template) Execute(builder golang.ModuleBuilder, args any) (string, error) {
+	code, err := executeTemplate(templateKey(t.Plugin, t.Name), t.Body, t.override(builder), args)
+	if err != nil {
+		return "", blueprint.Errorf("unable to execute template %v of plugin %v due to %v", t.Name, t.Plugin, err.Error())
+	}
+	return code, nil
+}
+
+// Like [ExecuteTemplateToFile], but executes the wiring spec's override of the template, if there is one
+// in the workspace of builder.
+func (t *Template) ExecuteToFile(builder golang.ModuleBuilder, args any, filename string) error {
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_RDWR, 0755)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	code, err := t.Execute(builder, args)
+	if err != nil {
+		return err
+	}
+	recordGenerated(filename, fmt.Sprintf("template %v of plugin %v", t.Name, t.Plugin))
+	_, err = f.WriteString(code)
+	return err
+}
@@ -24,17 +24,22 @@ the helper functions defined in this file within the template.
 //
 // [gogen/template.go]: https://github.com/Blueprint-uServices/blueprint/tree/main/plugins/golang/gogen/template.go
 func ExecuteTemplate(name string, body string, args any) (string, error) {
+	return executeTemplate(name, body, "", args)
+}
+
+// If override is not empty, it is executed instead of body, and can include body with {{template "default" .}}
+func executeTemplate(name string, body string, override string, args any) (string, error) {
 	e := newTemplateExecutor(args)
 
 	// This is a hacky but very convenient way of dealing with the fact that imports
 	// get declared before they're used... just compile twice.  The second pass
 	// will compile the correct imports.  Alternative is much more verbose.
 	// In the long run we can implement this properly but for now this works just fine.
-	_, err := e.exec(name, body, args)
+	_, err := e.execOverride(name, body, override, args)
 	if err != nil {
 		return "", err
 	}
-	return e.exec(name, body, args)
+	return e.execOverride(name, body, override, args)
 }
 
 // A helper function for executing [text/template] templates to file
@@ -54,6 +59,7 @@ func ExecuteTemplateToFile(name string, body string, args any, filename string)
 	if err != nil {
 		return err
 	}
+	recordGenerated(filename, "template "+name)
 	_, err = f.WriteString(code)
 	return err
 }
@@ -101,6 +107,22 @@ func (e *templateExecutor) exec(name string, body string, args any) (string, err
 	return buf.String(), err
 }
 
+func (e *templateExecutor) execOverride(name string, body string, override string, args any) (string, error) {
+	if override == "" {
+		return e.exec(name, body, args)
+	}
+	t, err := template.New(name).Funcs(e.Funcs).Parse(override)
+	if err != nil {
+		return "", err
+	}
+	if _, err := t.New("default").Parse(body); err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	err = t.Execute(buf, args)
+	return buf.String(), err
+}
+
 func (e *templateExecutor) NameOf(typeName gocode.TypeName) (string, error) {
 	if e.Imports != nil {
 		return e.Imports.NameOf(typeName), nil
@@ -237,6 +259,9 @@ func (e *templateExecutor) HasNewReturnVars(f gocode.Func) (string, error) {
 // Looks for any field of type *Imports on the provided obj
 func getImports(args any) *Imports {
 	v := reflect.Indirect(reflect.ValueOf(args))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
 	imp := reflect.TypeOf(&Imports{})
 	for i := 0; i < v.NumField(); i++ {
 		f := v.Field(i)
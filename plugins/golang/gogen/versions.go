@@ -0,0 +1,180 @@
+package gogen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"golang.org/x/exp/slog"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// A version of a module that was required by a generated module
+type ModuleRequirement struct {
+	Version    string // The required version
+	RequiredBy string // The generated module and the plugin code that required the version
+}
+
+/*
+Pins the version of module in the workspaces generated for this wiring spec.  Call this from a wiring
+spec, e.g. to resolve a version conflict between plugins:
+
+	gogen.PinModuleVersion(spec, "go.opentelemetry.io/otel/trace", "v1.26.0")
+
+Without a pin, when services or plugins require different versions of a module, the highest required
+version is used, as with Go's minimum version selection.  A pinned version is used even if it is lower
+than a required version, and also applies when the module is only an indirect dependency.
+
+The pin is stored in the [Options] node of the spec, so it is saved with the IR and only applies to
+the workspaces generated for this spec.
+
+If version is not a valid semantic version, an error is added to the spec.
+*/
+func PinModuleVersion(spec wiring.WiringSpec, module string, version string) {
+	if !semver.IsValid(version) {
+		spec.AddError(blueprint.Errorf("cannot pin module %v to version %v because it is not a valid semantic version", module, version))
+		return
+	}
+	pins, err := modulePinProperty.GetAll(spec, OptionsName)
+	if err != nil {
+		spec.AddError(err)
+		return
+	}
+	for _, pin := range pins {
+		if pin.Module == module && pin.Version != version {
+			spec.AddError(blueprint.Errorf("cannot pin module %v to version %v because it is already pinned to version %v", module, version, pin.Version))
+			return
+		}
+	}
+	modulePinProperty.Add(spec, OptionsName, ModulePin{Module: module, Version: version})
+	defineOptions(spec)
+}
+
+// Records that the generated module requiredBy requires version of module, and returns the version
+// that is currently selected for module
+func (workspace *WorkspaceBuilderImpl) require(module string, version string, requiredBy string) string {
+	workspace.Requirements[module] = append(workspace.Requirements[module], ModuleRequirement{
+		Version:    version,
+		RequiredBy: requiredBy + " (" + requirer() + ")",
+	})
+	return workspace.selectVersion(module)
+}
+
+// Selects the version of module that the workspace will use: the pinned version, if there is one, or
+// else the highest version that is required.
+func (workspace *WorkspaceBuilderImpl) selectVersion(module string) string {
+	if version, pinned := workspace.ModulePins[module]; pinned {
+		return version
+	}
+	selected := ""
+	for _, req := range workspace.Requirements[module] {
+		if selected == "" || semver.Compare(req.Version, selected) > 0 {
+			selected = req.Version
+		}
+	}
+	return selected
+}
+
+// Returns a report of the modules that are required at more than one version, or the empty string if
+// there are none
+func (workspace *WorkspaceBuilderImpl) versionConflicts() string {
+	var modules []string
+	for module := range workspace.Requirements {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	var b strings.Builder
+	for _, module := range modules {
+		reqs := workspace.Requirements[module]
+		versions := make(map[string]struct{})
+		for _, req := range reqs {
+			versions[req.Version] = struct{}{}
+		}
+		if len(versions) < 2 {
+			continue
+		}
+		selected := workspace.selectVersion(module)
+		reason := "the highest required version"
+		if _, pinned := workspace.ModulePins[module]; pinned {
+			reason = "pinned"
+		}
+		fmt.Fprintf(&b, "  %v: using %v (%v)\n", module, selected, reason)
+		for _, req := range reqs {
+			fmt.Fprintf(&b, "    %v requires %v\n", req.RequiredBy, req.Version)
+		}
+	}
+	return b.String()
+}
+
+// Updates the go.mod files of generated modules to require the selected version of each module, and adds
+// the pinned versions of modules to the go.work file
+func (workspace *WorkspaceBuilderImpl) resolveVersions(workFileName string) error {
+	if conflicts := workspace.versionConflicts(); conflicts != "" {
+		slog.Warn(fmt.Sprintf("Modules in workspace %v are required at different versions:\n%v", workspace.WorkspaceDir, conflicts))
+	}
+
+	for moduleSubDir := range workspace.GeneratedModules {
+		modFile, err := workspace.readModfile(moduleSubDir)
+		if err != nil {
+			return err
+		}
+		for _, req := range modFile.Require {
+			if selected := workspace.selectVersion(req.Mod.Path); selected != "" && selected != req.Mod.Version {
+				if err := modFile.AddRequire(req.Mod.Path, selected); err != nil {
+					return err
+				}
+			}
+		}
+		if err := savemodfile(modFile, filepath.Join(workspace.WorkspaceDir, moduleSubDir, "go.mod")); err != nil {
+			return err
+		}
+	}
+
+	if len(workspace.ModulePins) == 0 {
+		return nil
+	}
+	workData, err := os.ReadFile(workFileName)
+	if err != nil {
+		return err
+	}
+	workFile, err := modfile.ParseWork(workFileName, workData, nil)
+	if err != nil {
+		return err
+	}
+	for module, version := range workspace.ModulePins {
+		if _, isLocal := workspace.ModuleDirs[module]; isLocal {
+			return blueprint.Errorf("cannot pin module %v to version %v because it is a local module of workspace %v", module, version, workspace.WorkspaceDir)
+		}
+		if err := workFile.AddReplace(module, "", module, version); err != nil {
+			return err
+		}
+	}
+	workFile.SortBlocks()
+	return os.WriteFile(workFileName, modfile.Format(workFile.Syntax), 0755)
+}
+
+// Returns the function that called into the golang plugin, so that requirements can be attributed to the
+// plugin that made them
+func requirer() string {
+	pc := make([]uintptr, 20)
+	n := runtime.Callers(3, pc)
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		frame, more := frames.Next()
+		function := frame.Function[strings.LastIndex(frame.Function, "/")+1:]
+		pkg, _, _ := strings.Cut(function, ".")
+		if pkg != "golang" && pkg != "gogen" {
+			return function
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
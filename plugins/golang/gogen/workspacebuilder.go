@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"sort"
 	"strings"
 
 	"path/filepath"
@@ -26,6 +27,12 @@ type WorkspaceBuilderImpl struct {
 	ModuleDirs       map[string]string // map from FQ module name to directory name within WorkspaceDir
 	Modules          map[string]string // map from directory name to FQ module name within WorkspaceDir
 	GeneratedModules map[string]string // map from directory name to FQ module name within WorkspaceDir
+	LocalModuleSrcs  map[string]string // map from FQ module name to the directory that a local module was copied from
+
+	Requirements map[string][]ModuleRequirement // map from FQ module name to the versions required by generated modules
+
+	TemplateOverrides map[string]string // map from plugin/name of a template to its override; set by [Options]
+	ModulePins        map[string]string // map from FQ module name to its pinned version; set by [Options]
 }
 
 // Creates a golang workspace in the specified directory on the local filesystem.
@@ -50,6 +57,10 @@ func NewWorkspaceBuilder(workspaceDir string) (*WorkspaceBuilderImpl, error) {
 	workspace.ModuleDirs = make(map[string]string)
 	workspace.Modules = make(map[string]string)
 	workspace.GeneratedModules = make(map[string]string)
+	workspace.LocalModuleSrcs = make(map[string]string)
+	workspace.Requirements = make(map[string][]ModuleRequirement)
+	workspace.TemplateOverrides = make(map[string]string)
+	workspace.ModulePins = make(map[string]string)
 	return workspace, nil
 }
 
@@ -117,8 +128,9 @@ func (workspace *WorkspaceBuilderImpl) AddLocalModule(shortName string, moduleSr
 	if existingShortName, exists := workspace.ModuleDirs[modulePath]; exists {
 		if existingShortName != shortName {
 			return "", blueprint.Errorf("redeclaration of module %s as %s - already exists in %s", modulePath, shortName, existingShortName)
+		} else if existingSrcPath, copied := workspace.LocalModuleSrcs[modulePath]; copied && !sameDir(existingSrcPath, moduleSrcPath) {
+			return "", blueprint.Errorf("conflicting copies of module %s - cannot copy %s to workspace because %s was already copied", modulePath, moduleSrcPath, existingSrcPath)
 		} else {
-			// TODO: here, check module versions are the same
 			return filepath.Join(workspace.WorkspaceDir, workspace.ModuleDirs[modulePath]), nil
 		}
 	} else {
@@ -134,6 +146,8 @@ func (workspace *WorkspaceBuilderImpl) AddLocalModule(shortName string, moduleSr
 		workspace.Modules[shortName] = modulePath
 	}
 
+	workspace.LocalModuleSrcs[modulePath] = moduleSrcPath
+
 	moduleDstPath := filepath.Join(workspace.WorkspaceDir, shortName)
 	err = ioutil.CheckDir(moduleDstPath, true)
 	if err != nil {
@@ -180,6 +194,11 @@ use (
 // The method will do the following:
 //   - creates a go.work file in the root of the workspace that points to all of the modules contained therein
 //   - updates the go.mod files of all contained modules with 'replace' directives for any required modules that exist in the workspace
+//   - resolves modules required at different versions to a single version, reporting the conflict; see [PinModuleVersion]
+//   - formats the Go files of generated modules with go/format
+//   - if enabled with [SetTypeCheck], type checks the generated modules
+//
+// Errors in generated code are reported with the plugin and template that generated the offending file.
 func (workspace *WorkspaceBuilderImpl) Finish() error {
 	// Generate the go.work file
 	workFileName := filepath.Join(workspace.WorkspaceDir, "go.work")
@@ -198,16 +217,37 @@ func (workspace *WorkspaceBuilderImpl) Finish() error {
 		return blueprint.Errorf("generated an invalid go.work file for workspace %v due to %v", workspace.WorkspaceDir, err.Error())
 	}
 
+	// Use one version of each required module
+	if err := workspace.resolveVersions(workFileName); err != nil {
+		return err
+	}
+
 	// Rewrite the go.mod files to redirect to local modules
 	for moduleSubDir, moduleName := range workspace.Modules {
 		workspace.updateModfile(moduleSubDir, moduleName)
 	}
 
+	// Format the code of generated modules
+	for moduleSubDir := range workspace.GeneratedModules {
+		if err := formatModule(filepath.Join(workspace.WorkspaceDir, moduleSubDir)); err != nil {
+			return err
+		}
+	}
+
 	// Resolve imported packages for generated modules
 	for moduleSubDir := range workspace.GeneratedModules {
 		workspace.goModTidy(moduleSubDir)
 	}
 
+	// Check the generated code compiles
+	if typeCheck {
+		for moduleSubDir := range workspace.GeneratedModules {
+			if err := typeCheckModule(workspace.WorkspaceDir, filepath.Join(workspace.WorkspaceDir, moduleSubDir)); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -220,8 +260,14 @@ func (workspace *WorkspaceBuilderImpl) updateModfile(moduleSubDir string, module
 		return err
 	}
 
-	// Now we add replace directives
-	for otherModuleSubDir, otherModuleName := range workspace.Modules {
+	// Now we add replace directives, in a fixed order so that the go.mod file is the same every time
+	otherModuleSubDirs := make([]string, 0, len(workspace.Modules))
+	for otherModuleSubDir := range workspace.Modules {
+		otherModuleSubDirs = append(otherModuleSubDirs, otherModuleSubDir)
+	}
+	sort.Strings(otherModuleSubDirs)
+	for _, otherModuleSubDir := range otherModuleSubDirs {
+		otherModuleName := workspace.Modules[otherModuleSubDir]
 		if moduleName == otherModuleName {
 			continue
 		}
@@ -243,6 +289,12 @@ func (workspace *WorkspaceBuilderImpl) updateModfile(moduleSubDir string, module
 	return os.WriteFile(modFileName, data, 0755)
 }
 
+func sameDir(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
 func rel(path string) string {
 	pwd, err := os.Getwd()
 	if err != nil {
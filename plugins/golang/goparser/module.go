@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
 	"github.com/blueprint-uservices/blueprint/plugins/golang/gocode"
@@ -26,10 +27,15 @@ func (m *ModuleInfo) String() string {
 
 var cache = make(map[string]*ModuleInfo)
 
+// Guards cache; artifacts for different namespaces can be generated concurrently
+var cacheMu sync.Mutex
+
 // Get the info for a module.  Better than reading the go.mod.
 // Better than calling FindPackageModule because the root of the module
 // doesn't need to be a golang package.
 func GetModuleInfo(moduleName string) (*ModuleInfo, error) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
 	if m, ok := cache[moduleName]; ok {
 		return m, nil
 	}
@@ -46,6 +52,8 @@ func GetModuleInfo(moduleName string) (*ModuleInfo, error) {
 
 // Get the module info for a package.
 func FindPackageModule(pkgName string) (*ModuleInfo, error) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
 	if m, ok := cache[pkgName]; ok {
 		return m, nil
 	}
@@ -4,8 +4,10 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/plugins/golang/gocode"
 )
 
 // Represents a set of code modules that have been parsed.
@@ -13,6 +15,8 @@ type ParsedModuleSet struct {
 	Modules    map[string]*ParsedModule // Map from FQ module name to module object
 	ModuleDirs map[string]*ParsedModule // Map from module SrcDir to module object
 	Parent     *ParsedModuleSet         // Another module set to consult for modules if not present in this one
+
+	lock sync.Mutex // Guards Modules and ModuleDirs; artifact generators that share a module set can run concurrently
 }
 
 // Returns a new [*ParsedModuleSet], optionally with a parent module set, which can be nil.
@@ -33,10 +37,19 @@ func (set *ParsedModuleSet) Add(info *ModuleInfo) (*ParsedModule, error) {
 	if err != nil {
 		return nil, err
 	}
-	mod.IsLocal = info.IsLocal
+	setIsLocal(mod, info.IsLocal)
 	return mod, nil
 }
 
+// Parsed modules are shared between module sets, so IsLocal is only written when it changes
+func setIsLocal(mod *ParsedModule, isLocal bool) {
+	parsedModulesMu.Lock()
+	defer parsedModulesMu.Unlock()
+	if mod.IsLocal != isLocal {
+		mod.IsLocal = isLocal
+	}
+}
+
 // Manually parse and add a module to the parsed module set
 //
 // If the srcDir has already been parsed, then this function will do nothing.
@@ -52,7 +65,25 @@ func (set *ParsedModuleSet) AddModule(srcDir string) (*ParsedModule, error) {
 	if err := set.AddModules(srcDir); err != nil {
 		return nil, err
 	}
-	return set.ModuleDirs[srcDir], nil
+	return set.lookupDir(filepath.Clean(srcDir)), nil
+}
+
+// Returns the module in srcDir if it has been added to set, or nil otherwise
+func (set *ParsedModuleSet) lookupDir(srcDir string) *ParsedModule {
+	set.lock.Lock()
+	defer set.lock.Unlock()
+	return set.ModuleDirs[srcDir]
+}
+
+// Returns a snapshot of the modules in set, so that they can be searched without holding the lock
+func (set *ParsedModuleSet) modules() []*ParsedModule {
+	set.lock.Lock()
+	defer set.lock.Unlock()
+	mods := make([]*ParsedModule, 0, len(set.Modules))
+	for _, mod := range set.Modules {
+		mods = append(mods, mod)
+	}
+	return mods
 }
 
 // Avoids parsing the same srcDir multiple times.
@@ -60,6 +91,9 @@ func (set *ParsedModuleSet) AddModule(srcDir string) (*ParsedModule, error) {
 // it's a different ParsedModuleSet instance.
 var parsedModules = make(map[string]*ParsedModule)
 
+// Guards parsedModules; artifacts for different namespaces can be generated concurrently
+var parsedModulesMu sync.Mutex
+
 // Manually parse and add multiple modules to the set.
 //
 // Equivalent to calling [AddModule] for each srcDir. If a srcDir has already
@@ -74,48 +108,96 @@ func (set *ParsedModuleSet) AddModules(srcDirs ...string) error {
 		srcDir = filepath.Clean(srcDir)
 
 		// Have we parsed this module already?
-		if _, exists := set.ModuleDirs[srcDir]; exists {
+		if set.lookupDir(srcDir) != nil {
 			continue
 		}
 
 		// Has a parent parsed this module already?
 		var mod *ParsedModule
 		for parent := set.Parent; parent != nil; parent = parent.Parent {
-			if parentMod, existsInParent := parent.ModuleDirs[srcDir]; existsInParent {
-				mod = parentMod
+			if mod = parent.lookupDir(srcDir); mod != nil {
 				break
 			}
 		}
 
-		if mod, exists := parsedModules[srcDir]; exists {
-			newModules = append(newModules, mod)
-			continue
-		}
-
-		// Parse it
-		if mod == nil {
+		parsedModulesMu.Lock()
+		if cached, exists := parsedModules[srcDir]; exists {
+			mod = cached
+		} else if mod == nil {
+			// Parse it
 			if mod, err = parseModule(srcDir); err != nil {
+				parsedModulesMu.Unlock()
 				return err
 			}
 			parsedModules[srcDir] = mod
 		}
-
-		// Does the same module exist in multiple different directories?
-		if existingMod, exists := set.Modules[mod.Name]; exists {
-			return blueprint.Errorf("redeclaration of module %v found in %v and %v", mod.Name, existingMod.SrcDir, srcDir)
-		}
+		parsedModulesMu.Unlock()
 
 		newModules = append(newModules, mod)
 	}
 
-	// No errors encountered; save the new modules
+	// The lock is released between the checks above and here, so another caller may have added
+	// the same modules in the meantime
+	set.lock.Lock()
+	var added []*ParsedModule
 	for _, mod := range newModules {
+		if existingMod, exists := set.Modules[mod.Name]; exists && existingMod != mod {
+			// Does the same module exist in multiple different directories?
+			set.lock.Unlock()
+			return blueprint.Errorf("redeclaration of module %v found in %v and %v", mod.Name, existingMod.SrcDir, mod.SrcDir)
+		} else if !exists {
+			added = append(added, mod)
+		}
+	}
+
+	// No errors encountered; save the new modules
+	for _, mod := range added {
 		set.ModuleDirs[mod.SrcDir] = mod
 		set.Modules[mod.Name] = mod
 	}
+	set.lock.Unlock()
+
+	// Embedded interfaces can be declared in other modules, so are flattened once the modules are added.
+	// The lock isn't held here, because flattening can add further modules to set.
+	for _, mod := range newModules {
+		for _, pkg := range mod.Packages {
+			for _, iface := range pkg.Interfaces {
+				set.flattenInterface(iface)
+			}
+		}
+	}
 	return nil
 }
 
+// Adds the methods of the interfaces embedded by iface to iface.Methods, possibly searching for and parsing
+// the packages of the embedded interfaces.  Embedded interfaces that can't be found, including interfaces
+// of builtin packages, are added to iface.Unresolved.
+//
+// Each interface is only flattened once, because parsed modules are shared between module sets.
+func (set *ParsedModuleSet) flattenInterface(iface *ParsedInterface) {
+	iface.flattened.Do(func() {
+		for _, embedded := range iface.Embedded {
+			t, isUserType := embedded.(*gocode.UserType)
+			if !isUserType || gocode.IsBuiltinPackage(t.Package) {
+				iface.Unresolved = append(iface.Unresolved, embedded)
+				continue
+			}
+			embeddedIface, err := set.FindInterface(t.Package, t.Name)
+			if err != nil || embeddedIface == nil {
+				iface.Unresolved = append(iface.Unresolved, embedded)
+				continue
+			}
+			set.flattenInterface(embeddedIface)
+			for name, method := range embeddedIface.Methods {
+				if _, exists := iface.Methods[name]; !exists {
+					iface.Methods[name] = method
+				}
+			}
+			iface.Unresolved = append(iface.Unresolved, embeddedIface.Unresolved...)
+		}
+	})
+}
+
 // Parses and adds all modules in the specified workspaceDir
 func (set *ParsedModuleSet) AddWorkspace(workspaceDir string) error {
 	entries, err := os.ReadDir(workspaceDir)
@@ -139,8 +221,8 @@ func (set *ParsedModuleSet) AddWorkspace(workspaceDir string) error {
 // there was a parse error
 func (set *ParsedModuleSet) GetPackage(name string) (*ParsedPackage, error) {
 	// See if we've parsed the package
-	for moduleName, mod := range set.Modules {
-		if strings.HasPrefix(name, moduleName) {
+	for _, mod := range set.modules() {
+		if strings.HasPrefix(name, mod.Name) {
 			if pkg, exists := mod.Packages[name]; exists {
 				return pkg, nil
 			}
@@ -164,7 +246,7 @@ func (set *ParsedModuleSet) GetPackage(name string) (*ParsedPackage, error) {
 	if err != nil {
 		return nil, err
 	}
-	mod.IsLocal = info.IsLocal
+	setIsLocal(mod, info.IsLocal)
 
 	// Return the package if it exists
 	if pkg, exists := mod.Packages[name]; exists {
@@ -191,6 +273,22 @@ func (set *ParsedModuleSet) FindStruct(pkgName string, name string) (*ParsedStru
 	return nil, nil
 }
 
+// Reports whether t is a struct, a pointer to a struct, or an instantiation of a generic struct, possibly
+// searching for and parsing the package of the struct.
+func (set *ParsedModuleSet) IsStruct(t gocode.TypeName) bool {
+	switch s := t.(type) {
+	case *gocode.UserType:
+		struc, err := set.FindStruct(s.Package, s.Name)
+		return err == nil && struc != nil
+	case *gocode.Pointer:
+		return set.IsStruct(s.PointerTo)
+	case *gocode.GenericType:
+		return set.IsStruct(s.BaseType)
+	default:
+		return false
+	}
+}
+
 // Looks up the specified interface, possibly searching for and parsing the package.
 //
 // Returns an error if the package cannot be found or parsed.
@@ -203,6 +301,8 @@ func (set *ParsedModuleSet) FindInterface(pkgName string, name string) (*ParsedI
 	}
 
 	if intf, exists := pkg.Interfaces[name]; exists {
+		// The module might have been added by a concurrent caller that hasn't yet flattened its interfaces
+		set.flattenInterface(intf)
 		return intf, nil
 	}
 	return nil, nil
@@ -6,12 +6,14 @@ package goparser
 import (
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/parser"
 	"go/token"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 
 	"golang.org/x/exp/slog"
 
@@ -32,7 +34,9 @@ type (
 	    * To make sure things are resolved correctly across modules, packages, and files, we need to parse things breadth-first (first modules, then packages, etc.)
 	    * import . "something" is likely to cause problems.  If only one package is imported like this, we can assume unresolved types come from that module; more than one we error
 	    * any interface is valid for a workflow service.  typechecking function arguments is only needed when there is something like serialization; then there is a restriction on arg types
-	    * not implemented yet: we don't currently support structs or interfaces that extend other structs/interfaces
+	    * interfaces that embed other interfaces are flattened, so that Methods includes the promoted methods; this happens when the module is added to a ParsedModuleSet, since embedded interfaces can be declared in other modules
+	    * embedded struct fields are named after their type, as in Go; promoted methods of embedded structs are not implemented yet
+	    * constants are only evaluated if their values can be computed from literals, iota, and previously declared constants, which covers enum-style declarations
 	*/
 
 	ParsedModule struct {
@@ -43,6 +47,7 @@ type (
 		IsLocal   bool                      // Is this a local module or from the go cache?
 		Modfile   *modfile.File             // The modfile File struct is sufficiently simple that we just use it directly
 		Packages  map[string]*ParsedPackage // Map from fully qualified package name to ParsedPackage
+		Fset      *token.FileSet            // The file set that the module was parsed with; used to find the position of AST nodes
 	}
 
 	ParsedPackage struct {
@@ -58,6 +63,8 @@ type (
 		Interfaces    map[string]*ParsedInterface // Interfaces parsed from this package
 		Funcs         map[string]*ParsedFunc      // Functions parsed from this package (does not include funcs with receiver types)
 		Vars          map[string]*ParsedVar       // Vars declared in this package; we save their AST but don't process them
+		NamedTypes    map[string]*ParsedNamedType // Non-generic types declared in this package that are neither structs nor interfaces
+		Consts        map[string]*ParsedConst     // Constants declared in this package
 	}
 
 	ParsedFile struct {
@@ -83,18 +90,47 @@ type (
 	}
 
 	ParsedInterface struct {
-		File    *ParsedFile
-		Ast     *ast.InterfaceType
-		Name    string
-		Methods map[string]*ParsedFunc
+		File       *ParsedFile
+		Ast        *ast.InterfaceType
+		Name       string
+		Methods    map[string]*ParsedFunc // Methods declared in this interface, including methods promoted from embedded interfaces
+		Embedded   []gocode.TypeName      // Interfaces embedded in this interface
+		Unresolved []gocode.TypeName      // Embedded interfaces that could not be found, so whose methods are missing from Methods
+
+		embeddedAst []ast.Expr
+		flattened   sync.Once
 	}
 
 	ParsedFunc struct {
 		gocode.Func
-		File     *ParsedFile
-		Ast      *ast.FuncType
-		Body     *ast.BlockStmt
-		Receiver *ast.FieldList
+		File       *ParsedFile
+		Ast        *ast.FuncType
+		Body       *ast.BlockStmt
+		Receiver   *ast.FieldList
+		TypeParams []string // Names of generic type parameters of the func, or of its receiver if it is a method of a generic struct
+	}
+
+	// A declared type that is neither a struct nor an interface, e.g. type Status int, type Tags []string, or an alias
+	ParsedNamedType struct {
+		File       *ParsedFile
+		Ast        *ast.TypeSpec
+		Name       string
+		Underlying gocode.TypeName // The type that this type is declared as, e.g. int for type Status int; nil if it could not be resolved
+		IsAlias    bool            // True if the type is an alias, e.g. type Tags = []string
+		Consts     []*ParsedConst  // Constants of this type declared in the package, in declaration order
+	}
+
+	ParsedConst struct {
+		File  *ParsedFile
+		Name  string
+		Type  gocode.TypeName // The declared type of the constant, or nil if it is untyped
+		Value constant.Value  // The value of the constant, or nil if it could not be evaluated
+		Ast   *ast.ValueSpec
+
+		typeAst   ast.Expr
+		valueAst  ast.Expr
+		iota      int
+		evaluated bool // True once the constant's value has been evaluated, or is being evaluated
 	}
 
 	// Currently we save var statements but don't do anything with them
@@ -114,6 +150,7 @@ type (
 		Struct   *ParsedStruct
 		Position int
 		Ast      *ast.Field
+		Embedded bool // True if the field is an embedded field, in which case its name is the name of its type
 	}
 )
 
@@ -167,7 +204,7 @@ func parseModule(srcDir string) (*ParsedModule, error) {
 
 func (mod *ParsedModule) Load() error {
 	// Find all packages within the module, parse them, save but don't process the AST
-	var fset token.FileSet
+	mod.Fset = token.NewFileSet()
 	err := filepath.Walk(mod.SrcDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -176,7 +213,7 @@ func (mod *ParsedModule) Load() error {
 			return nil
 		}
 
-		pkgs, err := parser.ParseDir(&fset, path, nil, parser.ParseComments)
+		pkgs, err := parser.ParseDir(mod.Fset, path, nil, parser.ParseComments)
 		if err != nil {
 			return blueprint.Errorf("unable to parse package %v due to %s", path, err.Error())
 		}
@@ -207,6 +244,8 @@ func (mod *ParsedModule) Load() error {
 			p.Structs = make(map[string]*ParsedStruct)
 			p.Funcs = make(map[string]*ParsedFunc)
 			p.Vars = make(map[string]*ParsedVar)
+			p.NamedTypes = make(map[string]*ParsedNamedType)
+			p.Consts = make(map[string]*ParsedConst)
 
 			if existing, exists := mod.Packages[p.Name]; exists {
 				return blueprint.Errorf("duplicate definition of package %v at %v and %v", p.Name, path, existing.SrcDir)
@@ -275,6 +314,18 @@ func (pkg *ParsedPackage) Load() error {
 		}
 	}
 
+	// Load all of the constants before evaluating any, since constants can refer to constants declared
+	// later or in other files
+	for _, f := range pkg.Files {
+		err := f.LoadConsts()
+		if err != nil {
+			return err
+		}
+	}
+	for _, c := range pkg.Consts {
+		c.eval()
+	}
+
 	return nil
 }
 
@@ -286,6 +337,13 @@ func (pkg *ParsedPackage) Parse() error {
 				return err
 			}
 		}
+		for _, embedded := range iface.embeddedAst {
+			embeddedType := iface.File.ResolveType(embedded)
+			if embeddedType == nil {
+				return blueprint.Errorf("unable to resolve the type of %v embedded in interface %v", ExprStr(embedded), iface.Name)
+			}
+			iface.Embedded = append(iface.Embedded, embeddedType)
+		}
 	}
 	for _, struc := range pkg.Structs {
 		for _, method := range struc.Methods {
@@ -309,6 +367,24 @@ func (pkg *ParsedPackage) Parse() error {
 			return err
 		}
 	}
+	for _, t := range pkg.NamedTypes {
+		t.Underlying = t.File.ResolveType(t.Ast.Type)
+	}
+	var consts []*ParsedConst
+	for _, c := range pkg.Consts {
+		if c.typeAst != nil {
+			c.Type = c.File.ResolveType(c.typeAst)
+		}
+		consts = append(consts, c)
+	}
+	slices.SortFunc(consts, func(a, b *ParsedConst) int { return int(a.Pos() - b.Pos()) })
+	for _, c := range consts {
+		if u, isUserType := c.Type.(*gocode.UserType); isUserType && u.Package == pkg.Name {
+			if t, isNamedType := pkg.NamedTypes[u.Name]; isNamedType {
+				t.Consts = append(t.Consts, c)
+			}
+		}
+	}
 	return nil
 }
 
@@ -317,14 +393,38 @@ func (f *ParsedField) Parse() error {
 	if f.Type == nil {
 		return blueprint.Errorf("unable to resolve the type of %v field %v", f.Struct.Name, f)
 	}
+	if f.Embedded {
+		f.Name = embeddedFieldName(f.Type)
+	}
 	return nil
 }
 
+// Returns the name of an embedded field of type t, which is the name of the type without its package,
+// pointer, or type arguments, e.g. Envelope for *common.Envelope
+func embeddedFieldName(t gocode.TypeName) string {
+	switch e := t.(type) {
+	case *gocode.UserType:
+		return e.Name
+	case *gocode.Pointer:
+		return embeddedFieldName(e.PointerTo)
+	case *gocode.GenericType:
+		return embeddedFieldName(e.BaseType)
+	default:
+		return t.String()
+	}
+}
+
+// Returns the type of the field when the struct is instantiated with typeArgs, e.g. if the field
+// has type []T, then for the instantiation Page[Item] the field has type []Item
+func (f *ParsedField) InstantiatedType(typeArgs []gocode.TypeName) gocode.TypeName {
+	return gocode.InstantiateType(f.Type, f.Struct.TypeParams, typeArgs)
+}
+
 func (f *ParsedFunc) Parse() error {
 	if f.Ast.Params != nil {
 		for _, p := range f.Ast.Params.List {
 			// Determine the argument's type
-			argType := f.File.ResolveType(p.Type)
+			argType := f.File.ResolveType(p.Type, f.TypeParams...)
 			if argType == nil {
 				return blueprint.Errorf("%v unable to resolve type of argument %v", f.Name, p.Type)
 			}
@@ -343,7 +443,7 @@ func (f *ParsedFunc) Parse() error {
 	if f.Ast.Results != nil {
 		for _, r := range f.Ast.Results.List {
 			// Determine the retval's type
-			retType := f.File.ResolveType(r.Type)
+			retType := f.File.ResolveType(r.Type, f.TypeParams...)
 			if retType == nil {
 				return blueprint.Errorf("%v unable to resolve type of retval %v", f.Name, r.Type)
 			}
@@ -447,15 +547,31 @@ func (f *ParsedFile) ResolveType(expr ast.Expr, typeParams ...string) gocode.Typ
 	case *ast.StructType:
 		return &gocode.StructType{}
 	case *ast.IndexExpr:
-		return &gocode.GenericType{BaseType: f.ResolveType(e.X, typeParams...)}
+		return f.resolveGenericType(e.X, []ast.Expr{e.Index}, typeParams...)
 	case *ast.IndexListExpr:
-		return &gocode.GenericType{BaseType: f.ResolveType(e.X, typeParams...)}
+		return f.resolveGenericType(e.X, e.Indices, typeParams...)
 	default:
 		fmt.Printf("unknown or invalid expr type %v %v\n", reflect.TypeOf(expr), expr)
 	}
 	return nil
 }
 
+// Resolves the instantiation of generic type base with the type arguments args, e.g. Page[Item]
+func (f *ParsedFile) resolveGenericType(base ast.Expr, args []ast.Expr, typeParams ...string) gocode.TypeName {
+	t := &gocode.GenericType{BaseType: f.ResolveType(base, typeParams...)}
+	if t.BaseType == nil {
+		return nil
+	}
+	for _, arg := range args {
+		argType := f.ResolveType(arg, typeParams...)
+		if argType == nil {
+			return nil
+		}
+		t.TypeArgs = append(t.TypeArgs, argType)
+	}
+	return t
+}
+
 func (f *ParsedFile) LoadImports() error {
 	for _, imp := range f.Ast.Imports {
 		i := &ParsedImport{}
@@ -508,23 +624,14 @@ func (f *ParsedFile) LoadStructsAndInterfaces() error {
 				return blueprint.Errorf("parsing error, expected typespec in decls of %v", f.Name)
 			}
 
-			var typeParams []string
-			if typespec.TypeParams != nil {
-				for _, field := range typespec.TypeParams.List {
-					if field.Names != nil {
-						for _, name := range field.Names {
-							typeParams = append(typeParams, name.Name)
-						}
-					}
-				}
-			}
+			typeParams := typeParamNames(typespec.TypeParams)
 
 			// Save all types that are declared in the file
 			u := gocode.UserType{Package: f.Package.Name, Name: typespec.Name.Name}
 			f.Package.DeclaredTypes[u.Name] = u
 
 			// Also specifically save interface and struct AST info which we later want to parse.
-			// This ignores enums
+			// Other non-generic types, such as enums and aliases, are saved as named types.
 			switch t := typespec.Type.(type) {
 			case *ast.InterfaceType:
 				{
@@ -535,8 +642,16 @@ func (f *ParsedFile) LoadStructsAndInterfaces() error {
 					iface.Methods = make(map[string]*ParsedFunc)
 					f.Package.Interfaces[iface.Name] = iface
 
-					// Can load interface funcs immediately
+					// Can load interface funcs immediately.  Embedded interfaces are resolved when the package is parsed.
 					for _, methodDecl := range t.Methods.List {
+						if len(methodDecl.Names) == 0 {
+							switch methodDecl.Type.(type) {
+							case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+								iface.embeddedAst = append(iface.embeddedAst, methodDecl.Type)
+							}
+							// Other embedded elements are type sets, e.g. ~int | ~string, which only constraints have
+							continue
+						}
 						funcType, isFuncType := methodDecl.Type.(*ast.FuncType)
 						if !isFuncType {
 							return blueprint.Errorf("expected a function declaration in interface " + iface.Name)
@@ -571,8 +686,10 @@ func (f *ParsedFile) LoadStructsAndInterfaces() error {
 								field.Name = fieldDecl.Names[0].Name
 								struc.Fields[field.Name] = field
 							} else if struc.PromotedField == nil {
+								field.Embedded = true
 								struc.PromotedField = field
 							} else {
+								field.Embedded = true
 								struc.AnonymousFields = append(struc.AnonymousFields, field)
 							}
 							field.Position = i
@@ -582,6 +699,15 @@ func (f *ParsedFile) LoadStructsAndInterfaces() error {
 						}
 					}
 				}
+			default:
+				if len(typeParams) == 0 {
+					f.Package.NamedTypes[u.Name] = &ParsedNamedType{
+						File:    f,
+						Ast:     typespec,
+						Name:    u.Name,
+						IsAlias: typespec.Assign.IsValid(),
+					}
+				}
 			}
 		}
 	}
@@ -617,6 +743,140 @@ func (f *ParsedFile) LoadVars() error {
 	return nil
 }
 
+/*
+Assumes that all declared types have been loaded for the package containing the file.
+
+Loads the constants declared in the file, evaluating their values where possible.  Within a
+const block, a spec without values repeats the type and values of the previous spec, with
+the next value of iota.
+*/
+func (f *ParsedFile) LoadConsts() error {
+	for _, decl := range f.Ast.Decls {
+		// We are only looking for CONST declarations.
+		d, is_gendecl := decl.(*ast.GenDecl)
+		if !is_gendecl || d.Tok != token.CONST {
+			continue
+		}
+
+		var typeAst ast.Expr
+		var values []ast.Expr
+		for iota, spec := range d.Specs {
+			valspec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				return blueprint.Errorf("parsing error, expected valuespec in decls of %v", f.Name)
+			}
+			if len(valspec.Values) > 0 {
+				typeAst, values = valspec.Type, valspec.Values
+			}
+
+			for i, name := range valspec.Names {
+				if name.Name == "_" || i >= len(values) {
+					continue
+				}
+				c := &ParsedConst{File: f, Name: name.Name, Ast: valspec, typeAst: typeAst, valueAst: values[i], iota: iota}
+
+				// An untyped constant that converts its value, e.g. Status(1), has the type it converts to
+				if call, isCall := c.valueAst.(*ast.CallExpr); isCall && f.isTypeConversion(call) {
+					if c.typeAst == nil {
+						c.typeAst = call.Fun
+					}
+					c.valueAst = call.Args[0]
+				}
+				f.Package.Consts[c.Name] = c
+			}
+		}
+	}
+	return nil
+}
+
+// Evaluates the value of the constant, first evaluating any constants that it refers to.  The value is
+// nil if it could not be evaluated, including if the constant refers to itself.
+func (c *ParsedConst) eval() constant.Value {
+	if c.evaluated {
+		return c.Value
+	}
+	c.evaluated = true
+
+	// An untyped constant that is another constant, e.g. B = A, has the type of that constant
+	if ident, isIdent := c.valueAst.(*ast.Ident); isIdent && c.typeAst == nil {
+		if other, exists := c.File.Package.Consts[ident.Name]; exists {
+			other.eval()
+			c.typeAst = other.typeAst
+		}
+	}
+	c.Value = c.File.evalConst(c.valueAst, c.iota)
+	return c.Value
+}
+
+// Reports whether call converts a single value to a basic type or a type declared in the package
+func (f *ParsedFile) isTypeConversion(call *ast.CallExpr) bool {
+	if len(call.Args) != 1 {
+		return false
+	}
+	ident, isIdent := call.Fun.(*ast.Ident)
+	if !isIdent {
+		return false
+	}
+	_, isDeclared := f.Package.DeclaredTypes[ident.Name]
+	return isDeclared || gocode.IsBasicType(ident.Name)
+}
+
+// Evaluates a constant expression, returning nil if the expression is not supported
+func (f *ParsedFile) evalConst(expr ast.Expr, iota int) (value constant.Value) {
+	// The constant package panics on operations that don't type check
+	defer func() {
+		if recover() != nil {
+			value = nil
+		}
+	}()
+
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return constant.MakeFromLiteral(e.Value, e.Kind, 0)
+	case *ast.Ident:
+		switch e.Name {
+		case "iota":
+			return constant.MakeInt64(int64(iota))
+		case "true", "false":
+			return constant.MakeBool(e.Name == "true")
+		}
+		if c, exists := f.Package.Consts[e.Name]; exists {
+			return c.eval()
+		}
+	case *ast.ParenExpr:
+		return f.evalConst(e.X, iota)
+	case *ast.CallExpr:
+		if f.isTypeConversion(e) {
+			return f.evalConst(e.Args[0], iota)
+		}
+	case *ast.UnaryExpr:
+		if x := f.evalConst(e.X, iota); x != nil {
+			return constant.UnaryOp(e.Op, x, 0)
+		}
+	case *ast.BinaryExpr:
+		x, y := f.evalConst(e.X, iota), f.evalConst(e.Y, iota)
+		if x == nil || y == nil {
+			return nil
+		}
+		switch e.Op {
+		case token.SHL, token.SHR:
+			if s, isExact := constant.Uint64Val(y); isExact {
+				return constant.Shift(x, e.Op, uint(s))
+			}
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+			return constant.MakeBool(constant.Compare(x, e.Op, y))
+		case token.QUO:
+			if x.Kind() == constant.Int && y.Kind() == constant.Int {
+				return constant.BinaryOp(x, token.QUO_ASSIGN, y) // integer division
+			}
+			return constant.BinaryOp(x, e.Op, y)
+		default:
+			return constant.BinaryOp(x, e.Op, y)
+		}
+	}
+	return nil
+}
+
 /*
 Assumes that all structs and interfaces have been loaded for the package containing the file.
 
@@ -640,6 +900,8 @@ func (f *ParsedFile) LoadFuncs() error {
 		fun.Body = d.Body
 		fun.Receiver = d.Recv
 
+		fun.TypeParams = typeParamNames(d.Type.TypeParams)
+
 		if d.Recv == nil {
 			// This function is not associated with a struct, but it might still be a constructor
 			// We will associate constructors to structs later
@@ -647,29 +909,32 @@ func (f *ParsedFile) LoadFuncs() error {
 			continue
 		}
 
-		// This doesn't work with generic types
-
-		// Pull out the name of the receiver struct
-		var receiverName string
-		switch receiverType := d.Recv.List[0].Type.(type) {
-		case *ast.StarExpr:
-			{
-				switch pointerReceiverType := receiverType.X.(type) {
-				case *ast.Ident:
-					{
-						receiverName = pointerReceiverType.Name
-					}
-				default:
-					{
-						return blueprint.Errorf("unable to parse receiver type of function %v", fun.Name)
-					}
-				}
-			}
-		case *ast.Ident:
-			{
-				// Declared as func(receiver MyType) funcName(...) {}
-				receiverName = receiverType.Name
+		// Pull out the name of the receiver struct.  Methods of generic structs name the struct's
+		// type params in the receiver, e.g. func (p *Page[T]) funcName(...) {}
+		receiverType := d.Recv.List[0].Type
+		if pointerReceiverType, isPointer := receiverType.(*ast.StarExpr); isPointer {
+			receiverType = pointerReceiverType.X
+		}
+		var receiverTypeParams []ast.Expr
+		switch genericReceiverType := receiverType.(type) {
+		case *ast.IndexExpr:
+			receiverType = genericReceiverType.X
+			receiverTypeParams = []ast.Expr{genericReceiverType.Index}
+		case *ast.IndexListExpr:
+			receiverType = genericReceiverType.X
+			receiverTypeParams = genericReceiverType.Indices
+		}
+		receiverIdent, isIdent := receiverType.(*ast.Ident)
+		if !isIdent {
+			return blueprint.Errorf("unable to parse receiver type of function %v", fun.Name)
+		}
+		receiverName := receiverIdent.Name
+		for _, param := range receiverTypeParams {
+			paramIdent, isIdent := param.(*ast.Ident)
+			if !isIdent {
+				return blueprint.Errorf("unable to parse receiver type params of function %v", fun.Name)
 			}
+			fun.TypeParams = append(fun.TypeParams, paramIdent.Name)
 		}
 
 		// Associate the func with the receiver struct
@@ -683,6 +948,19 @@ func (f *ParsedFile) LoadFuncs() error {
 	return nil
 }
 
+// Returns the names of the type params declared by a generic type or func
+func typeParamNames(fields *ast.FieldList) []string {
+	var names []string
+	if fields != nil {
+		for _, field := range fields.List {
+			for _, name := range field.Names {
+				names = append(names, name.Name)
+			}
+		}
+	}
+	return names
+}
+
 func indent(str string, amount int) string {
 	lines := strings.Split(str, "\n")
 	for i, line := range lines {
@@ -691,6 +969,23 @@ func indent(str string, amount int) string {
 	return strings.Join(lines, "\n")
 }
 
+func (t *ParsedNamedType) Type() *gocode.UserType {
+	return &gocode.UserType{
+		Name:    t.Name,
+		Package: t.File.Package.Name,
+	}
+}
+
+// The position of the constant's declaration
+func (c *ParsedConst) Pos() token.Pos {
+	for _, name := range c.Ast.Names {
+		if name.Name == c.Name {
+			return name.Pos()
+		}
+	}
+	return c.Ast.Pos()
+}
+
 func (iface *ParsedInterface) Type() *gocode.UserType {
 	return &gocode.UserType{
 		Name:    iface.Name,
@@ -730,7 +1025,7 @@ func (f *ParsedFunc) AsConstructor() *gocode.Constructor {
 
 func (set *ParsedModuleSet) String() string {
 	var modStrings []string
-	for _, mod := range set.Modules {
+	for _, mod := range set.modules() {
 		modStrings = append(modStrings, mod.String())
 	}
 	return strings.Join(modStrings, "\n")
@@ -827,7 +1122,7 @@ func (f *ParsedFunc) String() string {
 }
 
 func (f *ParsedField) String() string {
-	if f.Name == "" {
+	if f.Name == "" || f.Embedded {
 		return f.Type.String()
 	} else {
 		return f.Name + " " + f.Type.String()
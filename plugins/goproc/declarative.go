@@ -0,0 +1,11 @@
+package goproc
+
+import (
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("goproc.Deploy", declarative.ApplyAndDefine(Deploy))
+	declarative.Register("goproc.CreateProcess", declarative.DefineWith(CreateProcess))
+	declarative.Register("goproc.CreateClientProcess", declarative.DefineWith(CreateClientProcess))
+}
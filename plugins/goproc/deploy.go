@@ -99,7 +99,7 @@ func (node *Process) GenerateArtifacts(workspaceDir string) error {
 	}
 
 	// For now, instantiate all contained nodes
-	for _, node := range node.Nodes {
+	for _, node := range ir.FilterNodes[golang.Instantiable](node.Nodes) {
 		namespaceBuilder.Instantiate(node.Name())
 	}
 
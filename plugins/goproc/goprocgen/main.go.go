@@ -54,7 +54,7 @@ func GenerateMain(
 
 	slog.Info(fmt.Sprintf("Generating %v/main.go", module.Info().Name))
 	mainFileName := filepath.Join(module.Info().Path, "main.go")
-	return gogen.ExecuteTemplateToFile("goprocMain", mainTemplate, mainArgs, mainFileName)
+	return mainTemplate.ExecuteToFile(module, mainArgs, mainFileName)
 }
 
 type mainArg struct {
@@ -71,7 +71,7 @@ type mainTemplateArgs struct {
 	Instantiate          []string
 }
 
-var mainTemplate = `// {{.Name}} runs the {{.Name}} Golang process.
+var mainTemplate = gogen.RegisterTemplate("goproc", "main", `// {{.Name}} runs the {{.Name}} Golang process.
 //
 // {{.Name}} is auto-generated by Blueprint's goproc plugin (goproc/goprocgen/main.go.go)
 //
@@ -109,4 +109,4 @@ func main() {
 	}
 	n.Await()
 	slog.Info("{{.Name}} exiting")
-}`
+}`)
@@ -4,6 +4,10 @@ import (
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
 )
 
+func init() {
+	ir.RegisterJSONType[*Process]()
+}
+
 var generatedModulePrefix = "blueprint/goproc"
 
 // An IRNode representing a golang process, which is a collection of application-level golang instances.
@@ -18,8 +22,8 @@ type Process struct {
 	ModuleName     string
 	Nodes          []ir.IRNode
 	Edges          []ir.IRNode
-	metricProvider ir.IRNode
-	logger         ir.IRNode
+	MetricProvider ir.IRNode
+	Logger         ir.IRNode
 }
 
 func newGolangProcessNode(name string) *Process {
@@ -11,6 +11,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*stdoutLogger]()
+}
+
 type stdoutLogger struct {
 	golang.Node
 	service.ServiceNode
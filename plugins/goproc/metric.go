@@ -11,6 +11,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*stdoutMetricCollector]()
+}
+
 type stdoutMetricCollector struct {
 	golang.Node
 	service.ServiceNode
@@ -58,6 +58,7 @@ import (
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
 	"github.com/blueprint-uservices/blueprint/plugins/golang"
+	"github.com/blueprint-uservices/blueprint/plugins/golang/gogen"
 )
 
 // AddToProcess can be used by wiring specs to add a golang instance to an existing golang process.
@@ -134,11 +135,14 @@ func CreateProcess(spec wiring.WiringSpec, procName string, children ...string)
 		if err != nil {
 			return nil, err
 		}
-		err = procNamespace.Get(metric_coll, &proc.metricProvider)
+		if err := gogen.AddOptions(spec, procNamespace); err != nil {
+			return nil, err
+		}
+		err = procNamespace.Get(metric_coll, &proc.MetricProvider)
 		if err != nil {
 			return nil, err
 		}
-		err = procNamespace.Get(logger_name, &proc.logger)
+		err = procNamespace.Get(logger_name, &proc.Logger)
 		if err != nil {
 			return nil, err
 		}
@@ -157,6 +161,9 @@ func CreateClientProcess(spec wiring.WiringSpec, procName string, children ...st
 		if err != nil {
 			return nil, err
 		}
+		if err := gogen.AddOptions(spec, procNamespace); err != nil {
+			return nil, err
+		}
 		for _, child := range children {
 			var childNode ir.IRNode
 			if err := procNamespace.Get(child, &childNode); err != nil {
@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/blueprint-uservices/blueprint/plugins/golang"
 	"github.com/blueprint-uservices/blueprint/plugins/golang/gocode"
 	"github.com/blueprint-uservices/blueprint/plugins/golang/gogen"
 	"golang.org/x/exp/slog"
@@ -74,16 +75,16 @@ func (b *ClientBuilder) AddClient(registryVar, clientName, nodeToInstantiate str
 	b.Clients = append(b.Clients, r)
 }
 
-// Generate the blueprint_clients.go file.
-func (b *ClientBuilder) Build() error {
+// Generate the blueprint_clients.go file.  module is the client library, whose workspace the file is generated in.
+func (b *ClientBuilder) Build(module golang.ModuleBuilder) error {
 	filename := "blueprint_clients.go"
 
 	slog.Info(fmt.Sprintf("Generating %v/%v.go", b.OutputDir, filename))
 	outputFile := filepath.Join(b.OutputDir, filename)
-	return gogen.ExecuteTemplateToFile("ServiceRegistryTestClientInit", initTestClientsTemplate, b, outputFile)
+	return initTestClientsTemplate.ExecuteToFile(module, b, outputFile)
 }
 
-var initTestClientsTemplate = `
+var initTestClientsTemplate = gogen.RegisterTemplate("gotests", "initTestClients", `
 package {{ .PackageShortName }}
 
 {{ .Imports }}
@@ -108,4 +109,4 @@ func init() {
 	})
 	{{end}}
 }
-`
+`)
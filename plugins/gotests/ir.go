@@ -12,6 +12,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*testLibrary]()
+}
+
 // An IR node that:
 //   - creates a golang workspace with all client library code
 //   - copies tests from the source application to the output
@@ -180,7 +184,7 @@ func (lib *testLibrary) GenerateArtifacts(workspaceDir string) error {
 
 	// Do the codegen
 	for _, b := range builders {
-		err = b.Build()
+		err = b.Build(module)
 		if err != nil {
 			return err
 		}
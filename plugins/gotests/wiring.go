@@ -130,9 +130,12 @@ import (
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
 	"github.com/blueprint-uservices/blueprint/plugins/golang"
+	"github.com/blueprint-uservices/blueprint/plugins/golang/gogen"
 )
 
-var prop_SERVICESTOTEST = "Services"
+var prop_SERVICESTOTEST = "gotests.Services"
+
+var servicesToTestProperty = wiring.DeclareProperty(wiring.Property[string]{Key: prop_SERVICESTOTEST, Multi: true})
 
 // [Test] can be used by wiring specs to convert existing black-box workflow tests into tests that use
 // generated service clients and can be run against the compiled Blueprint application.
@@ -158,7 +161,7 @@ func Test(spec wiring.WiringSpec, servicesToTest ...string) string {
 	name := "gotests"
 
 	for _, serviceName := range servicesToTest {
-		spec.AddProperty(name, prop_SERVICESTOTEST, serviceName)
+		servicesToTestProperty.Add(spec, name, serviceName)
 	}
 
 	spec.Define(name, &testLibrary{}, func(namespace wiring.Namespace) (ir.IRNode, error) {
@@ -167,9 +170,12 @@ func Test(spec wiring.WiringSpec, servicesToTest ...string) string {
 		if err != nil {
 			return nil, err
 		}
+		if err := gogen.AddOptions(spec, libNamespace); err != nil {
+			return nil, err
+		}
 
-		var servicesToTest []string
-		if err := namespace.GetProperties(name, prop_SERVICESTOTEST, &servicesToTest); err != nil {
+		servicesToTest, err := servicesToTestProperty.GetAll(namespace, name)
+		if err != nil {
 			return nil, err
 		}
 
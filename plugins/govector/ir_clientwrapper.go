@@ -13,6 +13,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*GovecClientWrapper]()
+}
+
 // Blueprint IR Node that wraps the client-side of a service to generate govec logs
 type GovecClientWrapper struct {
 	golang.Service
@@ -20,7 +24,7 @@ type GovecClientWrapper struct {
 	golang.GeneratesFuncs
 
 	InstanceName  string
-	outputPackage string
+	OutputPackage string
 	Wrapped       golang.Service
 	GoVecClient   *GoVecLoggerClient
 }
@@ -44,7 +48,7 @@ func (node *GovecClientWrapper) ImplementsGolangService() {}
 func newGovecClientWrapper(name string, wrapped golang.Service) (*GovecClientWrapper, error) {
 	node := &GovecClientWrapper{}
 	node.InstanceName = name
-	node.outputPackage = "govec"
+	node.OutputPackage = "govec"
 	node.Wrapped = wrapped
 	return node, nil
 }
@@ -58,7 +62,7 @@ func (node *GovecClientWrapper) genInterface(ctx ir.BuildContext) (*gocode.Servi
 	if !valid {
 		return nil, blueprint.Errorf("GoVecClientWrapper expected build context to be a ModuleBuilder, got %v", ctx)
 	}
-	i := gocode.CopyServiceInterface(fmt.Sprintf("%v_GoVecClientWrapperInterface", iface.BaseName), module_ctx.Info().Name+"/"+node.outputPackage, iface)
+	i := gocode.CopyServiceInterface(fmt.Sprintf("%v_GoVecClientWrapperInterface", iface.BaseName), module_ctx.Info().Name+"/"+node.OutputPackage, iface)
 	for name, method := range i.Methods {
 		method.Arguments = method.Arguments[:len(method.Arguments)-1]
 		method.Returns = method.Returns[:len(method.Returns)-1]
@@ -79,7 +83,7 @@ func (node *GovecClientWrapper) AddInstantiation(builder golang.NamespaceBuilder
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_GoVecClientWrapper", iface.BaseName),
 			Arguments: []gocode.Variable{
@@ -103,7 +107,7 @@ func (node *GovecClientWrapper) GenerateFuncs(builder golang.ModuleBuilder) erro
 		return err
 	}
 
-	return generateClientHandler(builder, wrapped_iface, impl_iface, node.outputPackage)
+	return generateClientHandler(builder, wrapped_iface, impl_iface, node.OutputPackage)
 }
 
 // Implements service.ServiceNode
@@ -136,7 +140,7 @@ func generateClientHandler(builder golang.ModuleBuilder, wrapped *gocode.Service
 
 	slog.Info(fmt.Sprintf("Generating %v/%v", client.Package.PackageName, impl.Name))
 	outputFile := filepath.Join(client.Package.Path, impl.Name+".go")
-	return gogen.ExecuteTemplateToFile("GoVector", clientTemplate, client, outputFile)
+	return clientTemplate.ExecuteToFile(builder, client, outputFile)
 }
 
 type clientArgs struct {
@@ -149,7 +153,7 @@ type clientArgs struct {
 	Imports         *gogen.Imports
 }
 
-var clientTemplate = `// Blueprint: Auto-generated by GoVector Plugin
+var clientTemplate = gogen.RegisterTemplate("govector", "client", `// Blueprint: Auto-generated by GoVector Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -186,4 +190,4 @@ func (handler *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.C
 	return
 }
 {{end}}
-`
+`)
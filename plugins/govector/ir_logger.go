@@ -11,6 +11,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*GoVecLoggerClient]()
+}
+
 // Blueprint IR Node that represents a GoVector logger instance
 type GoVecLoggerClient struct {
 	golang.Node
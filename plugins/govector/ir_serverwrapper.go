@@ -13,6 +13,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*GovecServerWrapper]()
+}
+
 // Blueprint IR node that wraps the server-side of a service to generate govec compatible logs
 type GovecServerWrapper struct {
 	golang.Service
@@ -20,7 +24,7 @@ type GovecServerWrapper struct {
 	golang.GeneratesFuncs
 
 	InstanceName  string
-	outputPackage string
+	OutputPackage string
 	Wrapped       golang.Service
 }
 
@@ -43,7 +47,7 @@ func (node *GovecServerWrapper) ImplementsGolangService() {}
 func newGovecServerWrapper(name string, wrapped golang.Service) (*GovecServerWrapper, error) {
 	node := &GovecServerWrapper{}
 	node.InstanceName = name
-	node.outputPackage = "govec"
+	node.OutputPackage = "govec"
 	node.Wrapped = wrapped
 	return node, nil
 }
@@ -58,7 +62,7 @@ func (node *GovecServerWrapper) genInterface(ctx ir.BuildContext) (*gocode.Servi
 	if !valid {
 		return nil, blueprint.Errorf("GoVecServerWrapper expected build context to be a ModuleBuilder, got %v", ctx)
 	}
-	i := gocode.CopyServiceInterface(fmt.Sprintf("%v_GoVecServerWrapperInterface", iface.BaseName), module_ctx.Info().Name+"/"+node.outputPackage, iface)
+	i := gocode.CopyServiceInterface(fmt.Sprintf("%v_GoVecServerWrapperInterface", iface.BaseName), module_ctx.Info().Name+"/"+node.OutputPackage, iface)
 	for name, method := range i.Methods {
 		method.AddArgument(gocode.Variable{Name: "govecctx", Type: &gocode.Slice{SliceOf: &gocode.BasicType{Name: "byte"}}})
 		method.AddRetVar(gocode.Variable{Name: "", Type: &gocode.Slice{SliceOf: &gocode.BasicType{Name: "byte"}}})
@@ -77,7 +81,7 @@ func (node *GovecServerWrapper) AddInstantiation(builder golang.NamespaceBuilder
 		return err
 	}
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_GoVecServerWrapper", iface.BaseName),
 			Arguments: []gocode.Variable{
@@ -101,7 +105,7 @@ func (node *GovecServerWrapper) GenerateFuncs(builder golang.ModuleBuilder) erro
 		return err
 	}
 
-	return generateServerHandler(builder, wrapped_iface, impl_iface, node.outputPackage)
+	return generateServerHandler(builder, wrapped_iface, impl_iface, node.OutputPackage)
 }
 
 // Implements service.ServiceNode
@@ -116,7 +120,7 @@ func (node *GovecServerWrapper) AddInterfaces(builder golang.ModuleBuilder) erro
 		return err
 	}
 
-	err = generateClientSideInterfaces(builder, iface, node.outputPackage)
+	err = generateClientSideInterfaces(builder, iface, node.OutputPackage)
 	if err != nil {
 		return err
 	}
@@ -143,7 +147,7 @@ func generateServerHandler(builder golang.ModuleBuilder, wrapped *gocode.Service
 
 	slog.Info(fmt.Sprintf("Generating %v/%v", server.Package.PackageName, impl.Name))
 	outputFile := filepath.Join(server.Package.Path, impl.Name+".go")
-	return gogen.ExecuteTemplateToFile("GoVector", serverTemplate, server, outputFile)
+	return serverTemplate.ExecuteToFile(builder, server, outputFile)
 }
 
 type serverArgs struct {
@@ -173,10 +177,10 @@ func generateClientSideInterfaces(builder golang.ModuleBuilder, iface *gocode.Se
 	slog.Info(fmt.Sprintf("Generating %v/%v", server.Package.PackageName, iface.Name))
 
 	outputFile := filepath.Join(server.Package.Path, iface.Name+".go")
-	return gogen.ExecuteTemplateToFile("GoVector", clientInterfaceTemplate, server, outputFile)
+	return clientInterfaceTemplate.ExecuteToFile(builder, server, outputFile)
 }
 
-var serverTemplate = `// Blueprint: Auto-generated by GoVector Plugin
+var serverTemplate = gogen.RegisterTemplate("govector", "server", `// Blueprint: Auto-generated by GoVector Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -212,9 +216,9 @@ func (handler *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.C
 	return
 }
 {{end}}
-`
+`)
 
-var clientInterfaceTemplate = `// Blueprint: Auto-generated by GoVector plugin
+var clientInterfaceTemplate = gogen.RegisterTemplate("govector", "clientInterface", `// Blueprint: Auto-generated by GoVector plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -224,4 +228,4 @@ type {{.IfaceName}} interface {
 	{{Signature $f}}
 	{{end}}
 }
-`
+`)
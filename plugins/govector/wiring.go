@@ -80,7 +80,7 @@ func Instrument(spec wiring.WiringSpec, serviceName string) {
 		slog.Error("Unable to deploy " + serviceName + " using GoVector as it is not a pointer")
 	}
 
-	clientNext := ptr.AddSrcModifier(spec, clientWrapper)
+	clientNext := ptr.AddSrcModifier(spec, clientWrapper, pointer.InProcessModifier)
 
 	spec.Define(clientWrapper, &GovecClientWrapper{}, func(ns wiring.Namespace) (ir.IRNode, error) {
 		var wrapped golang.Service
@@ -90,7 +90,7 @@ func Instrument(spec wiring.WiringSpec, serviceName string) {
 		return newGovecClientWrapper(clientWrapper, wrapped)
 	})
 
-	serverNext := ptr.AddDstModifier(spec, serverWrapper)
+	serverNext := ptr.AddDstModifier(spec, serverWrapper, pointer.InProcessModifier)
 
 	spec.Define(serverWrapper, &GovecServerWrapper{}, func(ns wiring.Namespace) (ir.IRNode, error) {
 		var wrapped golang.Service
@@ -0,0 +1,9 @@
+package grpc
+
+import (
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("grpc.Deploy", declarative.Apply(Deploy))
+}
@@ -17,9 +17,15 @@ func GenerateClient(builder golang.ModuleBuilder, service *gocode.ServiceInterfa
 		return err
 	}
 
+	methods, err := newGRPCMethods(service)
+	if err != nil {
+		return err
+	}
+
 	client := &clientArgs{
 		Package: pkg,
 		Service: service,
+		Methods: methods,
 		Name:    service.BaseName + "_GRPCClient",
 		Imports: gogen.NewImports(pkg.Name),
 	}
@@ -32,7 +38,7 @@ func GenerateClient(builder golang.ModuleBuilder, service *gocode.ServiceInterfa
 
 	slog.Info(fmt.Sprintf("Generating %v/%v.go", client.Package.PackageName, client.Name))
 	outputFile := filepath.Join(client.Package.Path, client.Name+".go")
-	return gogen.ExecuteTemplateToFile("GRPCClient", clientTemplate, client, outputFile)
+	return clientTemplate.ExecuteToFile(builder, client, outputFile)
 }
 
 /*
@@ -41,11 +47,12 @@ Arguments to the template code
 type clientArgs struct {
 	Package golang.PackageInfo
 	Service *gocode.ServiceInterface
-	Name    string         // Name of the generated client class
-	Imports *gogen.Imports // Manages imports for us
+	Methods map[string]*grpcMethod // The methods of Service, and the channels that they stream
+	Name    string                 // Name of the generated client class
+	Imports *gogen.Imports         // Manages imports for us
 }
 
-var clientTemplate = `// Blueprint: Auto-generated by GRPC Plugin
+var clientTemplate = gogen.RegisterTemplate("grpc", "client", `// Blueprint: Auto-generated by GRPC Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -77,8 +84,10 @@ func New_{{.Name}}(ctx context.Context, serverAddress string) (*{{.Name}}, error
 
 {{$service := .Service.Name -}}
 {{$receiver := .Name -}}
-{{- range $_, $f := .Service.Methods }}
+{{- range $_, $m := .Methods }}
+{{- $f := $m.Func }}
 func (client *{{$receiver}}) {{SignatureWithRetVars $f}} {
+{{- if not $m.IsStreaming}}
 	// Create and marshall the GRPC Request object
 	req := &{{$service}}_{{$f.Name}}_Request{}
 	req.marshall({{ArgVars $f}})
@@ -88,16 +97,111 @@ func (client *{{$receiver}}) {{SignatureWithRetVars $f}} {
 	defer cancel()
 
 	// Make the remote call
-	rsp, err := client.Client.{{$f.Name}}(ctx, req)
+	{{if $f.Returns}}rsp, err :={{else}}_, err ={{end}} client.Client.{{$f.Name}}(ctx, req)
 	if err == nil {
 		err = ctx.Err()
 	}
 	if err != nil {
 		return
 	}
+	{{- if $f.Returns}}
 
 	{{RetVarsEquals $f}} rsp.unmarshall()
+	{{- end}}
+	return
+{{- else}}
+	// Streams aren't subject to the request timeout; the stream is cancelled once the call completes
+	ctx, cancel := context.WithCancel(ctx)
+	{{- if $m.StreamRet}}
+	defer func() {
+		// If successful, the stream is instead cancelled once all of its responses have been received
+		if err != nil {
+			cancel()
+		}
+	}()
+	{{- else}}
+	defer cancel()
+	{{- end}}
+	{{- with $arg := $m.StreamArg}}
+
+	// The first request carries the args; each subsequent request carries an element of {{$arg.Name}}
+	stream, err := client.Client.{{$f.Name}}(ctx)
+	if err != nil {
+		return
+	}
+	// Sends each element of {{$arg.Name}} until it is closed, returning the first error.  Once a send fails,
+	// the remaining elements are discarded rather than sent, so that their producer doesn't block.
+	sendItems := func(err error) error {
+		for item := range {{$arg.Name}} {
+			if err == nil {
+				err = stream.Send(new({{$service}}_{{$f.Name}}_Request).marshallItem(item))
+			}
+		}
+		return err
+	}
+	err = stream.Send(new({{$service}}_{{$f.Name}}_Request).marshall({{$m.UnaryArgVars}}))
+	{{- if $m.StreamRet}}
+	if err != nil {
+		go sendItems(err)
+		return
+	}
+	go func() {
+		// If a send fails then so does receiving, which closes the returned stream
+		sendItems(nil)
+		stream.CloseSend()
+	}()
+	{{- else}}
+	if err = sendItems(err); err != nil {
+		return
+	}
+	{{- end}}
+	{{- else}}
+
+	stream, err := client.Client.{{$f.Name}}(ctx, new({{$service}}_{{$f.Name}}_Request).marshall({{$m.UnaryArgVars}}))
+	if err != nil {
+		return
+	}
+	{{- end}}
+	{{- if $m.StreamRet}}
+
+	// The first response carries the retvals; each subsequent response carries an element of the stream
+	{{if $m.UnaryRetVars}}rsp, err :={{else}}_, err ={{end}} stream.Recv()
+	if err != nil {
+		return
+	}
+	{{- if $m.UnaryRetVars}}
+	{{$m.UnaryRetVarsEquals}} rsp.unmarshall()
+	{{- end}}
+	items := make(chan {{NameOf $m.RetElem}})
+	go func() {
+		defer cancel()
+		defer close(items)
+		for {
+			rsp, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			select {
+			case items <- rsp.unmarshallItem():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	{{$m.StreamRetVar}} = items
+	return
+	{{- else}}
+
+	{{if $m.UnaryRetVars}}rsp, err :={{else}}_, err ={{end}} stream.CloseAndRecv()
+	if err != nil {
+		return
+	}
+	{{- if $m.UnaryRetVars}}
+	{{$m.UnaryRetVarsEquals}} rsp.unmarshall()
+	{{- end}}
 	return
+	{{- end}}
+{{- end}}
 }
 {{end}}
-`
+`)
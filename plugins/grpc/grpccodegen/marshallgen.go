@@ -58,21 +58,49 @@ func (msg *{{$method.Response.GRPCType.Name}}) unmarshall() (
 	{{- end}}
 	return
 }
+{{- with $item := $method.Request.Item}}
+
+// Client-side function to pack an element of the {{$service.Name}}.{{$method.Name}} {{$item.Name}} stream into a GRPC {{$method.Request.GRPCType.Name}} struct
+func (msg *{{$method.Request.GRPCType.Name}}) marshallItem({{$item.Name}} {{$imports.NameOf $item.SrcType}}) *{{$method.Request.GRPCType.Name}} {
+	{{$item.Marshall $imports ""}}
+	return msg
+}
+
+// Server-side function to unpack an element of the {{$service.Name}}.{{$method.Name}} {{$item.Name}} stream from a GRPC {{$method.Request.GRPCType.Name}} struct
+func (msg *{{$method.Request.GRPCType.Name}}) unmarshallItem() ({{$item.Name}} {{$imports.NameOf $item.SrcType}}) {
+	{{$item.Unmarshall $imports ""}}
+	return
+}
+{{- end}}
+{{- with $item := $method.Response.Item}}
+
+// Server-side function to pack an element of the {{$service.Name}}.{{$method.Name}} {{$item.Name}} stream into a GRPC {{$method.Response.GRPCType.Name}} struct
+func (msg *{{$method.Response.GRPCType.Name}}) marshallItem({{$item.Name}} {{$imports.NameOf $item.SrcType}}) *{{$method.Response.GRPCType.Name}} {
+	{{$item.Marshall $imports ""}}
+	return msg
+}
+
+// Client-side function to unpack an element of the {{$service.Name}}.{{$method.Name}} {{$item.Name}} stream from a GRPC {{$method.Response.GRPCType.Name}} struct
+func (msg *{{$method.Response.GRPCType.Name}}) unmarshallItem() ({{$item.Name}} {{$imports.NameOf $item.SrcType}}) {
+	{{$item.Unmarshall $imports ""}}
+	return
+}
+{{- end}}
 
 {{end -}}
 {{end -}}
 
-{{ range $t, $struct := .Structs}}
-// Utility function to pack {{$imports.Qualify $t.Package $t.Name}} into a GRPC {{$struct.GRPCType.Name}} message
-func (msg *{{$struct.GRPCType.Name}}) marshall(obj *{{$imports.Qualify $t.Package $t.Name}}) *{{$struct.GRPCType.Name}} {
+{{ range $_, $struct := .Structs}}
+// Utility function to pack {{$imports.NameOf $struct.SrcType}} into a GRPC {{$struct.GRPCType.Name}} message
+func (msg *{{$struct.GRPCType.Name}}) marshall(obj *{{$imports.NameOf $struct.SrcType}}) *{{$struct.GRPCType.Name}} {
 	{{- range $j, $field := $struct.FieldList}}
 	{{$field.Marshall $imports "obj."}}
 	{{- end}}
 	return msg
 }
 
-// Utility function to unpack {{$imports.Qualify $t.Package $t.Name}} from a GRPC {{$struct.GRPCType.Name}} message
-func (msg *{{$struct.GRPCType.Name}}) unmarshall(obj *{{$imports.Qualify $t.Package $t.Name}}) {
+// Utility function to unpack {{$imports.NameOf $struct.SrcType}} from a GRPC {{$struct.GRPCType.Name}} message
+func (msg *{{$struct.GRPCType.Name}}) unmarshall(obj *{{$imports.NameOf $struct.SrcType}}) {
 	{{- range $j, $field := $struct.FieldList}}
 	{{$field.Unmarshall $imports "obj."}}
 	{{- end}}
@@ -107,7 +135,10 @@ func (b *gRPCProtoBuilder) GenerateMarshallingCode(outputFilePath string) error
 	args.Imports = gogen.NewImports(args.PackageName)
 
 	for _, msg := range args.gRPCProtoBuilder.Messages {
-		for _, field := range msg.FieldList {
+		if msg.SrcType != nil {
+			args.Imports.AddType(msg.SrcType)
+		}
+		for _, field := range msg.Fields() {
 			args.Imports.AddType(field.SrcType)
 		}
 	}
@@ -115,115 +146,174 @@ func (b *gRPCProtoBuilder) GenerateMarshallingCode(outputFilePath string) error
 	return t.Execute(f, args)
 }
 
+// The golang expression for the value of the field within obj
+func (f *gRPCField) src(obj string) string {
+	if f.Wrapped {
+		// The field of a wrapper message holds the value of the wrapped slice or map itself
+		return "(*obj)"
+	}
+	return obj + f.Name
+}
+
 func (f *gRPCField) Marshall(imports *gogen.Imports, obj string) (string, error) {
-	switch t := f.GRPCType.(type) {
+	return marshallValue(fmt.Sprintf("msg.%s", strings.Title(f.Name)), f.src(obj), f.SrcType, f.GRPCType, 0)
+}
+
+func (f *gRPCField) Unmarshall(imports *gogen.Imports, obj string) (string, error) {
+	return unmarshallValue(imports, f.src(obj), fmt.Sprintf("msg.%s", strings.Title(f.Name)), f.SrcType, f.GRPCType, 0)
+}
+
+// Returns the golang type that protoc generates for the GRPC type t
+func grpcTypeName(t gocode.TypeName) string {
+	switch t := t.(type) {
 	case *gocode.UserType:
-		{
-			return fmt.Sprintf("msg.%s = new(%s).marshall(&%s%s)", strings.Title(f.Name), t.Name, obj, f.Name), nil
-		}
+		return "*" + t.Name
+	case *gocode.Pointer:
+		return grpcTypeName(t.PointerTo)
+	case *gocode.Slice:
+		return "[]" + grpcTypeName(t.SliceOf)
+	case *gocode.Map:
+		return fmt.Sprintf("map[%s]%s", grpcTypeName(t.KeyType), grpcTypeName(t.ValueType))
+	default:
+		return t.String()
+	}
+}
+
+// Reports whether values of srcType can be assigned to grpcType without conversion
+func isSameBasicType(srcType gocode.TypeName, grpcType gocode.TypeName) bool {
+	src, isBasic := srcType.(*gocode.BasicType)
+	return isBasic && grpcType.Equals(src)
+}
+
+/*
+Generates code that assigns src, of golang type srcType, to dst, of GRPC type grpcType.
+
+Slices and maps whose elements need converting, e.g. []Status or map[string]int, are converted
+element by element; depth is used to name the loop variables of nested slices and maps.
+*/
+func marshallValue(dst string, src string, srcType gocode.TypeName, grpcType gocode.TypeName, depth int) (string, error) {
+	switch t := grpcType.(type) {
+	case *gocode.UserType:
+		return fmt.Sprintf("%s = new(%s).marshall(&%s)", dst, t.Name, src), nil
 	case *gocode.BasicType:
-		{
-			return fmt.Sprintf("msg.%s = %s(%s%s)", strings.Title(f.Name), t.Name, obj, f.Name), nil
-		}
+		return fmt.Sprintf("%s = %s(%s)", dst, t.Name, src), nil
 	case *gocode.Pointer:
-		{
-			switch pt := t.PointerTo.(type) {
-			case *gocode.UserType:
-				return fmt.Sprintf("msg.%s = new(%s).marshall(%s%s)", strings.Title(f.Name), pt.Name, obj, f.Name), nil
-			case *gocode.BasicType:
-				return fmt.Sprintf("msg.%s = %s(*%s%s)", strings.Title(f.Name), pt.Name, obj, f.Name), nil
-			default:
-				return "", blueprint.Errorf("unsupported pointer type %v", pt)
-			}
+		switch pt := t.PointerTo.(type) {
+		case *gocode.UserType:
+			return fmt.Sprintf("if %s != nil { %s = new(%s).marshall(%s) }", src, dst, pt.Name, src), nil
+		case *gocode.BasicType:
+			return fmt.Sprintf("if %s != nil { %s = %s(*%s) }", src, dst, pt.Name, src), nil
+		default:
+			return "", blueprint.Errorf("unsupported pointer type %v", pt)
+		}
+	case *gocode.Slice:
+		srcSlice, isSlice := srcType.(*gocode.Slice)
+		if !isSlice {
+			return "", blueprint.Errorf("unsupported slice type %v", srcType)
+		}
+		if isSameBasicType(srcSlice.SliceOf, t.SliceOf) {
+			return fmt.Sprintf("%s = %s", dst, src), nil
+		}
+		v, x := fmt.Sprintf("v%d", depth), fmt.Sprintf("x%d", depth)
+		elem, err := marshallValue(x, v, srcSlice.SliceOf, t.SliceOf, depth+1)
+		if err != nil {
+			return "", err
 		}
+		return fmt.Sprintf(`
+	%s = make(%s, 0, len(%s))
+	for _, %s := range %s {
+		var %s %s
+		%s
+		%s = append(%s, %s)
+	}`, dst, grpcTypeName(t), src, v, src, x, grpcTypeName(t.SliceOf), elem, dst, dst, x), nil
 	case *gocode.Map:
-		{
-			switch vt := t.ValueType.(type) {
-			case *gocode.UserType:
-				{
-					return fmt.Sprintf(`
-    msg.%s = make(map[%s]*%s)
-	for k, v := range %s%s {
-		msg.%s[k] = new(%s).marshall(&v)
-	}`, strings.Title(f.Name), t.KeyType, vt.Name, obj, f.Name, strings.Title(f.Name), vt.Name), nil
-				}
-			case *gocode.BasicType:
-				return fmt.Sprintf("msg.%s = %s%s", strings.Title(f.Name), obj, f.Name), nil
-			default:
-				return "", blueprint.Errorf("unsupported/unimplemented map value type %v", vt)
-			}
+		srcMap, isMap := srcType.(*gocode.Map)
+		if !isMap {
+			return "", blueprint.Errorf("unsupported map type %v", srcType)
 		}
-	case *gocode.Slice:
-		{
-			switch st := t.SliceOf.(type) {
-			case *gocode.UserType:
-				return fmt.Sprintf(
-					"for _, v := range %s%s { msg.%s = append(msg.%s, new(%s).marshall(&v)) }",
-					obj, f.Name, strings.Title(f.Name), strings.Title(f.Name), st.Name), nil
-			case *gocode.BasicType:
-				return fmt.Sprintf("msg.%s = %s%s", strings.Title(f.Name), obj, f.Name), nil
-			default:
-				return "", blueprint.Errorf("unsupported/unimplemented slice type %v", st)
-			}
+		if isSameBasicType(srcMap.KeyType, t.KeyType) && isSameBasicType(srcMap.ValueType, t.ValueType) {
+			return fmt.Sprintf("%s = %s", dst, src), nil
+		}
+		k, v, x := fmt.Sprintf("k%d", depth), fmt.Sprintf("v%d", depth), fmt.Sprintf("x%d", depth)
+		elem, err := marshallValue(x, v, srcMap.ValueType, t.ValueType, depth+1)
+		if err != nil {
+			return "", err
 		}
+		return fmt.Sprintf(`
+	%s = make(%s, len(%s))
+	for %s, %s := range %s {
+		var %s %s
+		%s
+		%s[%s(%s)] = %s
+	}`, dst, grpcTypeName(t), src, k, v, src, x, grpcTypeName(t.ValueType), elem, dst, grpcTypeName(t.KeyType), k, x), nil
 	}
-	return "", nil
+	return "", blueprint.Errorf("unsupported type %v", grpcType)
 }
 
-func (f *gRPCField) Unmarshall(imports *gogen.Imports, obj string) (string, error) {
-	switch t := f.GRPCType.(type) {
+// Generates code that assigns src, of GRPC type grpcType, to dst, of golang type srcType.  The inverse of [marshallValue].
+func unmarshallValue(imports *gogen.Imports, dst string, src string, srcType gocode.TypeName, grpcType gocode.TypeName, depth int) (string, error) {
+	switch t := grpcType.(type) {
 	case *gocode.UserType:
-		{
-			return fmt.Sprintf("msg.%s.unmarshall(&%s%s)", strings.Title(f.Name), obj, f.Name), nil
-		}
+		return fmt.Sprintf("if %s != nil { %s.unmarshall(&%s) }", src, src, dst), nil
 	case *gocode.BasicType:
-		{
-			return fmt.Sprintf("%s%s = %v(msg.%s)", obj, f.Name, f.SrcType, strings.Title(f.Name)), nil
-		}
+		return fmt.Sprintf("%s = %s(%s)", dst, imports.NameOf(srcType), src), nil
 	case *gocode.Pointer:
-		{
-			switch pt := t.PointerTo.(type) {
-			case *gocode.UserType:
-				return fmt.Sprintf("msg.%s.unmarshall(%s%s)", strings.Title(f.Name), obj, f.Name), nil
-			case *gocode.BasicType:
-				return fmt.Sprintf("%s%s = &%v(msg.%s)", obj, f.Name, f.SrcType, strings.Title(f.Name)), nil
-			default:
-				return "", blueprint.Errorf("unsupported pointer type %v", pt)
-			}
+		srcPointer, isPointer := srcType.(*gocode.Pointer)
+		if !isPointer {
+			return "", blueprint.Errorf("unsupported pointer type %v", srcType)
 		}
-	case *gocode.Map:
-		{
-			switch t.ValueType.(type) {
-			case *gocode.UserType:
-				{
-					return fmt.Sprintf(`
-    %s%s = make(%s)
-	for k, v := range msg.%s {
-		objv := %s%s[k]
-		v.unmarshall(&objv)
-	}`, obj, f.Name, imports.NameOf(f.SrcType), strings.Title(f.Name), obj, f.Name), nil
-				}
-			case *gocode.BasicType:
-				return fmt.Sprintf("msg.%s = %s%s", strings.Title(f.Name), obj, f.Name), nil
-			default:
-				return "", blueprint.Errorf("unsupported map value type %v", t)
-			}
+		switch pt := t.PointerTo.(type) {
+		case *gocode.UserType:
+			return fmt.Sprintf(`
+	if %s != nil {
+		%s = new(%s)
+		%s.unmarshall(%s)
+	}`, src, dst, imports.NameOf(srcPointer.PointerTo), src, dst), nil
+		case *gocode.BasicType:
+			return fmt.Sprintf(`
+	%s = new(%s)
+	*%s = %s(%s)`, dst, imports.NameOf(srcPointer.PointerTo), dst, imports.NameOf(srcPointer.PointerTo), src), nil
+		default:
+			return "", blueprint.Errorf("unsupported pointer type %v", pt)
 		}
 	case *gocode.Slice:
-		{
-			switch st := t.SliceOf.(type) {
-			case *gocode.UserType:
-				return fmt.Sprintf(`
-	%s%s = make(%s, len(msg.%s))
-	for i, v := range msg.%s {
-		v.unmarshall(&%s%s[i])
-	}`, obj, f.Name, imports.NameOf(f.SrcType), strings.Title(f.Name), strings.Title(f.Name), obj, f.Name), nil
-			case *gocode.BasicType:
-				return fmt.Sprintf("%s%s = msg.%s", obj, f.Name, strings.Title(f.Name)), nil
-			default:
-				return "", blueprint.Errorf("unsupported/unimplemented slice type %v", st)
-			}
+		srcSlice, isSlice := srcType.(*gocode.Slice)
+		if !isSlice {
+			return "", blueprint.Errorf("unsupported slice type %v", srcType)
+		}
+		if isSameBasicType(srcSlice.SliceOf, t.SliceOf) {
+			return fmt.Sprintf("%s = %s", dst, src), nil
+		}
+		i, v := fmt.Sprintf("i%d", depth), fmt.Sprintf("v%d", depth)
+		elem, err := unmarshallValue(imports, fmt.Sprintf("%s[%s]", dst, i), v, srcSlice.SliceOf, t.SliceOf, depth+1)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(`
+	%s = make(%s, len(%s))
+	for %s, %s := range %s {
+		%s
+	}`, dst, imports.NameOf(srcType), src, i, v, src, elem), nil
+	case *gocode.Map:
+		srcMap, isMap := srcType.(*gocode.Map)
+		if !isMap {
+			return "", blueprint.Errorf("unsupported map type %v", srcType)
+		}
+		if isSameBasicType(srcMap.KeyType, t.KeyType) && isSameBasicType(srcMap.ValueType, t.ValueType) {
+			return fmt.Sprintf("%s = %s", dst, src), nil
+		}
+		k, v, x := fmt.Sprintf("k%d", depth), fmt.Sprintf("v%d", depth), fmt.Sprintf("x%d", depth)
+		elem, err := unmarshallValue(imports, x, v, srcMap.ValueType, t.ValueType, depth+1)
+		if err != nil {
+			return "", err
 		}
+		return fmt.Sprintf(`
+	%s = make(%s, len(%s))
+	for %s, %s := range %s {
+		var %s %s
+		%s
+		%s[%s(%s)] = %s
+	}`, dst, imports.NameOf(srcType), src, k, v, src, x, imports.NameOf(srcMap.ValueType), elem, dst, imports.NameOf(srcMap.KeyType), k, x), nil
 	}
-	return "", nil
+	return "", blueprint.Errorf("unsupported type %v", grpcType)
 }
@@ -2,6 +2,8 @@ package grpccodegen
 
 import (
 	"fmt"
+	"go/constant"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -27,10 +29,26 @@ func GenerateGRPCProto(builder golang.ModuleBuilder, service *gocode.ServiceInte
 		return nil
 	}
 
+	outputFilename, err := WriteGRPCProto(builder, service, outputPackage)
+	if err != nil {
+		return err
+	}
+
+	// Compile the proto file
+	return CompileProtoFile(outputFilename)
+}
+
+/*
+Writes the GRPC .proto file for the provided service interface, and the code that converts between
+golang and GRPC types, to outputPackage, without compiling the proto file.
+
+Returns the name of the .proto file.  Most plugins should use [GenerateGRPCProto] instead.
+*/
+func WriteGRPCProto(builder golang.ModuleBuilder, service *gocode.ServiceInterface, outputPackage string) (string, error) {
 	// Parse the current output code to get definitions that may have been generated by other plugins
 	modules := workflowspec.Get().Derive().Modules
 	if err := modules.AddWorkspace(builder.Workspace().Info().Path); err != nil {
-		return err
+		return "", err
 	}
 
 	// Construct and validate the GRPC proto builder for the service
@@ -43,33 +61,27 @@ func GenerateGRPCProto(builder golang.ModuleBuilder, service *gocode.ServiceInte
 
 	err := pb.AddService(service)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Filename munging
 	outputDir := filepath.Join(builder.Info().Path, filepath.Join(splits...))
 	err = os.MkdirAll(outputDir, 0755)
 	if err != nil {
-		return blueprint.Errorf("unable to create grpc output dir %v due to %v", outputDir, err.Error())
+		return "", blueprint.Errorf("unable to create grpc output dir %v due to %v", outputDir, err.Error())
 	}
 
 	// Write the proto file
 	outputFilename := filepath.Join(outputDir, service.BaseName+".proto")
 	err = pb.WriteProtoFile(outputFilename)
 	if err != nil {
-		return err
-	}
-
-	// Compile the proto file
-	err = CompileProtoFile(outputFilename)
-	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Generate the marshalling code
 	slog.Info(fmt.Sprintf("Generating %v/%v_conversions.go", pb.PackageName, service.BaseName))
 	marshallFile := filepath.Join(outputDir, service.BaseName+"_conversions.go")
-	return pb.GenerateMarshallingCode(marshallFile)
+	return outputFilename, pb.GenerateMarshallingCode(marshallFile)
 }
 
 func rel(path string) string {
@@ -110,13 +122,29 @@ type (
 		GRPCType  gocode.TypeName // The GRPC type in golang
 		Name      string
 		Position  int
+		Wrapped   bool // True if the field holds the value of the named slice or map that its message wraps
 	}
 
 	gRPCMessageDecl struct {
 		Builder   *gRPCProtoBuilder
 		Name      string
 		GRPCType  *gocode.UserType // The GRPC-generated type for this message
+		SrcType   gocode.TypeName  // The golang struct, or instantiation of a generic struct, that this message corresponds to; nil for request and response messages
 		FieldList []*gRPCField
+		Item      *gRPCField // For the requests and responses of streaming RPCs, the field that carries an element of the streamed channel
+	}
+
+	// An enum declared for a named integer type with constants
+	gRPCEnumDecl struct {
+		Name       string
+		SrcType    *gocode.UserType // The golang named type that this enum corresponds to
+		Values     []*gRPCEnumValue // The values of the enum; the first value is always zero, as proto3 requires
+		AllowAlias bool             // True if more than one value has the same number
+	}
+
+	gRPCEnumValue struct {
+		Name   string
+		Number int64
 	}
 
 	gRPCMethodDecl struct {
@@ -140,7 +168,8 @@ type (
 		PackageName string // Fully qualified package
 		Services    map[string]*gRPCServiceDecl
 		Messages    map[string]*gRPCMessageDecl
-		Structs     map[gocode.UserType]*gRPCMessageDecl // Mapping from golang struct to the corresponding message
+		Structs     map[string]*gRPCMessageDecl // Messages that correspond to golang structs or wrap named slices and maps, keyed by message name
+		Enums       map[string]*gRPCEnumDecl    // Enums that correspond to named integer types, keyed by enum name
 	}
 )
 
@@ -150,7 +179,8 @@ func newProtoBuilder(code *goparser.ParsedModuleSet, name string) *gRPCProtoBuil
 	b.Code = code
 	b.Services = make(map[string]*gRPCServiceDecl)
 	b.Messages = make(map[string]*gRPCMessageDecl)
-	b.Structs = make(map[gocode.UserType]*gRPCMessageDecl)
+	b.Structs = make(map[string]*gRPCMessageDecl)
+	b.Enums = make(map[string]*gRPCEnumDecl)
 	return b
 }
 
@@ -158,9 +188,20 @@ var protoFileTemplate = `syntax="proto3";
 option go_package="./;{{ .Package }}";
 package {{ .Package }};
 
+{{ range $k, $enum := .Enums }}
+enum {{$enum.Name}} {
+    {{- if $enum.AllowAlias}}
+    option allow_alias = true;
+    {{- end}}
+    {{- range $_, $value := $enum.Values}}
+    {{$value.Name}} = {{$value.Number}};
+    {{- end}}
+}
+{{ end -}}
+
 {{ range $k, $msg := .Messages }}
 message {{$msg.Name}} {
-    {{- range $k, $field := $msg.FieldList}}
+    {{- range $k, $field := $msg.Fields}}
     {{$field.ProtoType}} {{$field.Name}} = {{$field.Position}};
     {{- end}}
 }
@@ -169,7 +210,7 @@ message {{$msg.Name}} {
 {{ range $k, $service := .Services }}
 service {{$service.Name}} {
     {{- range $k, $method := $service.Methods}}
-    rpc {{$method.Name}} ({{$method.Request.Name}}) returns ({{$method.Response.Name}}) {}
+    rpc {{$method.Name}} ({{if $method.Request.Item}}stream {{end}}{{$method.Request.Name}}) returns ({{if $method.Response.Item}}stream {{end}}{{$method.Response.Name}}) {}
     {{- end}}
 }
 {{ end }}
@@ -209,6 +250,14 @@ func (b *gRPCProtoBuilder) newService(name string) *gRPCServiceDecl {
 	return s
 }
 
+// Returns all of the fields of the message, including the Item field of streaming messages
+func (msg *gRPCMessageDecl) Fields() []*gRPCField {
+	if msg.Item == nil {
+		return msg.FieldList
+	}
+	return append(append([]*gRPCField{}, msg.FieldList...), msg.Item)
+}
+
 func (b *gRPCServiceDecl) newMethod(name string) *gRPCMethodDecl {
 	m := &gRPCMethodDecl{}
 	m.Service = b
@@ -219,27 +268,40 @@ func (b *gRPCServiceDecl) newMethod(name string) *gRPCMethodDecl {
 	return m
 }
 
-func (b *gRPCProtoBuilder) makeFieldList(vars []gocode.Variable) ([]*gRPCField, error) {
+// Makes the fields of a request or response message for vars.  If streamIndex is a valid index, then
+// vars[streamIndex] is a streamed channel, whose field is returned separately as the item field.
+func (b *gRPCProtoBuilder) makeFieldList(vars []gocode.Variable, streamIndex int) ([]*gRPCField, *gRPCField, error) {
 	var fieldList []*gRPCField
+	var item *gRPCField
 	for i, arg := range vars {
-		protoType, grpcType, err := b.getGRPCType(arg.Type)
+		srcType := arg.Type
+		if i == streamIndex {
+			srcType, _ = gocode.ChanElem(arg.Type)
+		}
+		srcType = b.unalias(srcType)
+		protoType, grpcType, err := b.getGRPCType(srcType)
 		if err != nil {
-			return nil, blueprint.Errorf("cannot serialize %v of type %v for GRPC due to %v", arg.Name, arg.Type, err.Error())
+			return nil, nil, blueprint.Errorf("cannot serialize %v of type %v for GRPC due to %v", arg.Name, arg.Type, err.Error())
 		}
 
 		name := arg.Name
 		if name == "" {
 			name = fmt.Sprintf("ret%v", i)
 		}
-		fieldList = append(fieldList, &gRPCField{
-			SrcType:   arg.Type,
+		field := &gRPCField{
+			SrcType:   srcType,
 			ProtoType: protoType,
 			GRPCType:  grpcType,
 			Name:      name,
 			Position:  i + 1,
-		})
+		}
+		if i == streamIndex {
+			item = field
+		} else {
+			fieldList = append(fieldList, field)
+		}
 	}
-	return fieldList, nil
+	return fieldList, item, nil
 }
 
 /*
@@ -250,30 +312,68 @@ Adds a service declaration for the provided golang service interface.
 For arguments and return values on methods in the interface, corresponding GRPC message objects
 are needed.  The ProtoBuilder will consult the parsed code to find the definitions of arguments
 and return values.
+
+Methods with channel arguments or return values are declared as streaming RPCs; see [grpcMethod].
 */
 func (b *gRPCProtoBuilder) AddService(iface *gocode.ServiceInterface) error {
 	serviceDecl := b.newService(iface.Name) // TODO: (not implemented yet) possibility of name collisions
-	for _, method := range iface.Methods {
-		argList, err := b.makeFieldList(method.Arguments)
+	for _, f := range iface.Methods {
+		method, err := newGRPCMethod(iface.Name, f)
+		if err != nil {
+			return err
+		}
+
+		argList, argItem, err := b.makeFieldList(method.Arguments, method.argIndex)
 		if err != nil {
 			return err
 		}
 
-		retList, err := b.makeFieldList(method.Returns)
+		retList, retItem, err := b.makeFieldList(method.Returns, method.retIndex)
 		if err != nil {
 			return err
 		}
 
 		methodDecl := serviceDecl.newMethod(method.Name)
 		methodDecl.Request.FieldList = argList
+		methodDecl.Request.Item = argItem
 		methodDecl.Response.FieldList = retList
+		methodDecl.Response.Item = retItem
 	}
 	return nil
 }
 
 func (b *gRPCProtoBuilder) GetOrAddMessage(t *gocode.UserType) (*gRPCMessageDecl, error) {
+	return b.getOrAddMessage(t, t, nil)
+}
+
+/*
+Gets or adds the message for an instantiation of a generic struct, e.g. Page[Item].
+
+Each instantiation gets its own message, e.g. model.Page[model.Item] and model.Page[model.User] are
+declared as messages Model_Page_Model_Item and Model_Page_Model_User (prefixed by the service name), whose
+fields have the struct's type params substituted with the instantiation's type arguments.  See
+[gocode.IdentifierName].
+*/
+func (b *gRPCProtoBuilder) GetOrAddGenericMessage(t *gocode.GenericType) (*gRPCMessageDecl, error) {
+	base, isUserType := t.BaseType.(*gocode.UserType)
+	if !isUserType {
+		return nil, blueprint.Errorf("GRPC cannot serialize %v because %v is not a struct", t, t.BaseType)
+	}
+	return b.getOrAddMessage(t, base, t.TypeArgs)
+}
+
+// Gets or adds the message for srcType, which is either the struct t or an instantiation of t with typeArgs
+func (b *gRPCProtoBuilder) getOrAddMessage(srcType gocode.TypeName, t *gocode.UserType, typeArgs []gocode.TypeName) (*gRPCMessageDecl, error) {
 	// Message might already exist
-	if msgDecl, exists := b.Structs[*t]; exists {
+	typeName, err := gocode.IdentifierName(srcType)
+	if err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("%v_%v", b.Name, typeName)
+	if msgDecl, exists := b.Structs[name]; exists {
+		if !msgDecl.SrcType.Equals(srcType) {
+			return nil, blueprint.Errorf("GRPC cannot declare messages for both %v and %v because both would be named %v", msgDecl.SrcType, srcType, name)
+		}
 		return msgDecl, nil
 	}
 
@@ -285,32 +385,37 @@ func (b *gRPCProtoBuilder) GetOrAddMessage(t *gocode.UserType) (*gRPCMessageDecl
 	struc, hasStruct := pkg.Structs[t.Name]
 	if !hasStruct {
 		// It's possible that the type does exist but it wasn't declared as a struct, e.g. it is
-		// an enum or a type alias. Non-struct types are not-yet-implemented
+		// a generic slice type, which is not-yet-implemented.
 		if _, hasTypeDef := pkg.DeclaredTypes[t.Name]; hasTypeDef {
 			return nil, blueprint.Errorf("expected %v to be a struct but it is an unsupported type", t.String())
 		} else {
 			return nil, blueprint.Errorf("could not find %v within %v", t.Name, t.Package)
 		}
 	}
+	if len(struc.TypeParams) != len(typeArgs) {
+		return nil, blueprint.Errorf("%v has %v type params but is used with %v type arguments in %v", t, len(struc.TypeParams), len(typeArgs), srcType)
+	}
 
 	// Create the message
-	msg := b.newMessage(fmt.Sprintf("%v_%v", b.Name, t.Name))
-	b.Structs[*t] = msg
+	msg := b.newMessage(name)
+	msg.SrcType = srcType
+	b.Structs[name] = msg
 	for _, field := range struc.FieldsList {
-		// We ignore promoted and anonymous struct / interface extensions
-		if _, isNamed := struc.Fields[field.Name]; !isNamed {
-			// TODO (not implemented yet): support promoted and anonymous, handle interfaces and promoted struct fields
+		// Embedded structs are serialized like a field named after the struct, since that is how Go names them.
+		// Other embedded types, such as interfaces, are ignored.
+		fieldType := b.unalias(field.InstantiatedType(typeArgs))
+		if field.Embedded && !b.Code.IsStruct(fieldType) {
 			continue
 		}
 
 		// Gets the type name of this field, possibly internally creating the GRPC message if it's a struct
-		fieldProto, fieldGRPC, err := b.getGRPCType(field.Type)
+		fieldProto, fieldGRPC, err := b.getGRPCType(fieldType)
 		if err != nil {
 			return nil, err
 		}
 
 		msg.FieldList = append(msg.FieldList, &gRPCField{
-			SrcType:   field.Type,
+			SrcType:   fieldType,
 			ProtoType: fieldProto,
 			GRPCType:  fieldGRPC,
 			Name:      field.Name,
@@ -321,6 +426,183 @@ func (b *gRPCProtoBuilder) GetOrAddMessage(t *gocode.UserType) (*gRPCMessageDecl
 	return msg, nil
 }
 
+// Returns the named type declaration of t if t is a named type that is neither a struct nor an interface,
+// or nil otherwise
+func (b *gRPCProtoBuilder) findNamedType(t *gocode.UserType) (*goparser.ParsedNamedType, error) {
+	pkg, err := b.Code.GetPackage(t.Package)
+	if err != nil {
+		return nil, blueprint.Errorf("could not find package %v for type %v due to: %v", t.Package, t, err)
+	}
+	return pkg.NamedTypes[t.Name], nil
+}
+
+// Replaces aliases within t with the types that they alias.  Generated code can use either, but
+// the marshalling code needs to know the actual slices and maps that it converts.
+func (b *gRPCProtoBuilder) unalias(t gocode.TypeName) gocode.TypeName {
+	switch arg := t.(type) {
+	case *gocode.UserType:
+		if named, _ := b.findNamedType(arg); named != nil && named.IsAlias && named.Underlying != nil {
+			return b.unalias(named.Underlying)
+		}
+	case *gocode.Pointer:
+		return &gocode.Pointer{PointerTo: b.unalias(arg.PointerTo)}
+	case *gocode.Slice:
+		return &gocode.Slice{SliceOf: b.unalias(arg.SliceOf)}
+	case *gocode.Map:
+		return &gocode.Map{KeyType: b.unalias(arg.KeyType), ValueType: b.unalias(arg.ValueType)}
+	}
+	return t
+}
+
+/*
+Returns the proto and golang GRPC types for the named type t, which is neither a struct nor an interface:
+  - aliases are serialized like the type that they alias
+  - named integer types with constants are declared as enums; see [gRPCProtoBuilder.getOrAddEnum]
+  - other named basic types are serialized like their underlying type, e.g. type Name string is a proto string
+  - named slices and maps are wrapped in a message with a single field, Value, which holds the slice or map.
+    This also means that they can be nested, e.g. []Tags, which proto does not allow for unnamed slices.
+*/
+func (b *gRPCProtoBuilder) getNamedGRPCType(t *gocode.UserType, named *goparser.ParsedNamedType) (string, gocode.TypeName, error) {
+	if named.Underlying == nil {
+		return "", nil, blueprint.Errorf("unable to resolve the type that %v is declared as", t)
+	}
+	if named.IsAlias {
+		return b.getGRPCType(b.unalias(t))
+	}
+	switch underlying := named.Underlying.(type) {
+	case *gocode.BasicType:
+		{
+			enum, err := b.getOrAddEnum(t, named, underlying)
+			if err != nil {
+				return "", nil, err
+			}
+			if enum != nil {
+				return enum.Name, &gocode.BasicType{Name: enum.Name}, nil
+			}
+			return b.getGRPCType(underlying)
+		}
+	case *gocode.Slice, *gocode.Map:
+		{
+			msg, err := b.getOrAddWrapper(t, named)
+			if err != nil {
+				return "", nil, err
+			}
+			return msg.Name, msg.GRPCType, nil
+		}
+	default:
+		return "", nil, blueprint.Errorf("GRPC cannot serialize %v, which is declared as %v", t, named.Underlying)
+	}
+}
+
+// Integer types that are declared as enums when they have constants.  Enums are int32 in proto, so
+// other integer types are only declared as enums if their values are typically small.
+var enumBasicTypes = map[string]struct{}{
+	"int": {}, "int8": {}, "int16": {}, "int32": {}, "uint8": {}, "uint16": {}, "byte": {}, "rune": {},
+}
+
+/*
+Gets or adds the enum for the named integer type t, which has the underlying type basic.  Returns nil
+if t should not be declared as an enum, because it has no constants, or because it is not an integer
+type, or because some of its constants could not be evaluated or are outside the range of an int32;
+in the latter cases, a warning is logged, since t is then serialized like its underlying type.
+
+The enum is named after t's package and name, e.g. Svc_model_Status, and its values are named after
+t's constants, in declaration order.  Proto3 requires the first value
+of an enum to be zero, so the first constant whose value is zero goes first, and if there is no such
+constant, the enum has an extra value, UNSPECIFIED, that is zero.  Proto enums are open, so values of t
+that aren't constants are still serialized.
+*/
+func (b *gRPCProtoBuilder) getOrAddEnum(t *gocode.UserType, named *goparser.ParsedNamedType, basic *gocode.BasicType) (*gRPCEnumDecl, error) {
+	typeName, err := gocode.IdentifierName(t)
+	if err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("%v_%v", b.Name, typeName)
+	if enum, exists := b.Enums[name]; exists {
+		if !enum.SrcType.Equals(t) {
+			return nil, blueprint.Errorf("GRPC cannot declare enums for both %v and %v because both would be named %v", enum.SrcType, t, name)
+		}
+		return enum, nil
+	}
+
+	if len(named.Consts) == 0 {
+		return nil, nil
+	}
+	if _, isEnumType := enumBasicTypes[basic.Name]; !isEnumType {
+		if strings.Contains(basic.Name, "int") {
+			slog.Warn(fmt.Sprintf("GRPC will not declare an enum for %v because its type %v can exceed an int32; it is serialized as a %v", t, basic.Name, basic.Name))
+		}
+		return nil, nil
+	}
+
+	enum := &gRPCEnumDecl{Name: name, SrcType: t}
+	numbers := make(map[int64]struct{})
+	hasZero := false
+	for _, c := range named.Consts {
+		if c.Value == nil {
+			slog.Warn(fmt.Sprintf("GRPC will not declare an enum for %v because the value of %v could not be evaluated; it is serialized as a %v", t, c.Name, basic.Name))
+			return nil, nil
+		}
+		number, isExact := constant.Int64Val(constant.ToInt(c.Value))
+		if !isExact || number < math.MinInt32 || number > math.MaxInt32 {
+			slog.Warn(fmt.Sprintf("GRPC will not declare an enum for %v because the value of %v is not an int32; it is serialized as a %v", t, c.Name, basic.Name))
+			return nil, nil
+		}
+		value := &gRPCEnumValue{Name: name + "_" + c.Name, Number: number}
+		if number == 0 && !hasZero {
+			enum.Values = append([]*gRPCEnumValue{value}, enum.Values...)
+			hasZero = true
+		} else {
+			enum.Values = append(enum.Values, value)
+		}
+		if _, isDuplicate := numbers[number]; isDuplicate {
+			enum.AllowAlias = true
+		}
+		numbers[number] = struct{}{}
+	}
+	if !hasZero {
+		enum.Values = append([]*gRPCEnumValue{{Name: name + "_UNSPECIFIED", Number: 0}}, enum.Values...)
+	}
+	b.Enums[name] = enum
+	return enum, nil
+}
+
+// Gets or adds the message that wraps the named slice or map t.  Like struct messages, the message is
+// named after t's package and name, e.g. Svc_model_Tags.
+func (b *gRPCProtoBuilder) getOrAddWrapper(t *gocode.UserType, named *goparser.ParsedNamedType) (*gRPCMessageDecl, error) {
+	typeName, err := gocode.IdentifierName(t)
+	if err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("%v_%v", b.Name, typeName)
+	if msgDecl, exists := b.Structs[name]; exists {
+		if !msgDecl.SrcType.Equals(t) {
+			return nil, blueprint.Errorf("GRPC cannot declare messages for both %v and %v because both would be named %v", msgDecl.SrcType, t, name)
+		}
+		return msgDecl, nil
+	}
+
+	// Create the message before getting the type of its field, since the slice or map can contain itself
+	msg := b.newMessage(name)
+	msg.SrcType = t
+	b.Structs[name] = msg
+
+	valueType := b.unalias(named.Underlying)
+	valueProto, valueGRPC, err := b.getGRPCType(valueType)
+	if err != nil {
+		return nil, blueprint.Errorf("cannot serialize %v for GRPC due to %v", t, err.Error())
+	}
+	msg.FieldList = append(msg.FieldList, &gRPCField{
+		SrcType:   valueType,
+		ProtoType: valueProto,
+		GRPCType:  valueGRPC,
+		Name:      "Value",
+		Position:  1,
+		Wrapped:   true,
+	})
+	return msg, nil
+}
+
 var basicToGrpc = map[string]string{
 	"bool":   "bool",
 	"string": "string",
@@ -341,23 +623,16 @@ var grpcToBasic = map[string]string{
 	"float64": "double",
 }
 
-var acceptableMapKeys map[string]struct{}
+var acceptableMapKeys = map[string]struct{}{
+	"int32": {}, "int64": {}, "uint32": {}, "uint64": {}, "sint32": {}, "sint64": {},
+	"fixed32": {}, "fixed64": {}, "sfixed32": {}, "sfixed64": {}, "bool": {}, "string": {},
+}
 
 func getMapKeyType(t gocode.TypeName) (string, gocode.TypeName, bool) {
-	if acceptableMapKeys == nil {
-		keys := []string{
-			"int32", "int64", "uint32", "uint64", "sint32", "sint64",
-			"fixed32", "fixed64", "sfixed32", "sfixed64", "bool", "string",
-		}
-		acceptableMapKeys = make(map[string]struct{})
-		for _, key := range keys {
-			acceptableMapKeys[key] = struct{}{}
-		}
-	}
 	if basic, isBasic := t.(*gocode.BasicType); isBasic {
 		if grpcType, hasGrpcType := basicToGrpc[basic.Name]; hasGrpcType {
 			if _, isValid := acceptableMapKeys[grpcType]; isValid {
-				return grpcType, t, true
+				return grpcType, &gocode.BasicType{Name: grpcToBasic[grpcType]}, true
 			}
 		}
 	}
@@ -370,12 +645,27 @@ func (b *gRPCProtoBuilder) getGRPCType(t gocode.TypeName) (string, gocode.TypeNa
 	switch arg := t.(type) {
 	case *gocode.UserType:
 		{
+			named, err := b.findNamedType(arg)
+			if err != nil {
+				return "", nil, err
+			}
+			if named != nil {
+				return b.getNamedGRPCType(arg, named)
+			}
 			msg, err := b.GetOrAddMessage(arg)
 			if err != nil {
 				return "", nil, err
 			}
 			return msg.Name, msg.GRPCType, nil
 		}
+	case *gocode.GenericType:
+		{
+			msg, err := b.GetOrAddGenericMessage(arg)
+			if err != nil {
+				return "", nil, err
+			}
+			return msg.Name, msg.GRPCType, nil
+		}
 	case *gocode.BasicType:
 		{
 			if grpcType, hasGrpcType := basicToGrpc[arg.Name]; hasGrpcType {
@@ -394,7 +684,14 @@ func (b *gRPCProtoBuilder) getGRPCType(t gocode.TypeName) (string, gocode.TypeNa
 		}
 	case *gocode.Map:
 		{
-			keyProto, keyGRPC, isValidKey := getMapKeyType(arg.KeyType)
+			// Named keys are serialized like their underlying type, since proto map keys cannot be enums
+			keyType := arg.KeyType
+			if key, isUserType := keyType.(*gocode.UserType); isUserType {
+				if named, _ := b.findNamedType(key); named != nil && named.Underlying != nil {
+					keyType = named.Underlying
+				}
+			}
+			keyProto, keyGRPC, isValidKey := getMapKeyType(keyType)
 			if !isValidKey {
 				return "", nil, blueprint.Errorf("GRPC cannot use %v as a map key", arg.KeyType)
 			}
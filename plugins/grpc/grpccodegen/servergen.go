@@ -21,9 +21,15 @@ func GenerateServerHandler(builder golang.ModuleBuilder, service *gocode.Service
 		return err
 	}
 
+	methods, err := newGRPCMethods(service)
+	if err != nil {
+		return err
+	}
+
 	server := &serverArgs{
 		Package: pkg,
 		Service: service,
+		Methods: methods,
 		Name:    service.BaseName + "_GRPCServerHandler",
 		Imports: gogen.NewImports(pkg.Name),
 	}
@@ -35,7 +41,7 @@ func GenerateServerHandler(builder golang.ModuleBuilder, service *gocode.Service
 
 	slog.Info(fmt.Sprintf("Generating %v/%v_GRPCServer.go", server.Package.PackageName, service.Name))
 	outputFile := filepath.Join(server.Package.Path, service.Name+"_GRPCServer.go")
-	return gogen.ExecuteTemplateToFile("GRPCServer", serverTemplate, server, outputFile)
+	return serverTemplate.ExecuteToFile(builder, server, outputFile)
 }
 
 /*
@@ -44,11 +50,12 @@ Arguments to the template code
 type serverArgs struct {
 	Package golang.PackageInfo
 	Service *gocode.ServiceInterface
-	Name    string         // Name of the generated wrapper class
-	Imports *gogen.Imports // Manages imports for us
+	Methods map[string]*grpcMethod // The methods of Service, and the channels that they stream
+	Name    string                 // Name of the generated wrapper class
+	Imports *gogen.Imports         // Manages imports for us
 }
 
-var serverTemplate = `// Blueprint: Auto-generated by GRPC Plugin
+var serverTemplate = gogen.RegisterTemplate("grpc", "server", `// Blueprint: Auto-generated by GRPC Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -88,10 +95,14 @@ func (handler *{{.Name}}) Run(ctx context.Context) error {
 
 {{$service := .Service.Name -}}
 {{$receiver := .Name -}}
-{{ range $_, $f := .Service.Methods }}
+{{ range $_, $m := .Methods }}
+{{- $f := $m.Func -}}
+{{- if not $m.IsStreaming}}
 func (handler *{{$receiver}}) {{$f.Name -}}
 		(ctx context.Context, req *{{$service}}_{{$f.Name}}_Request) (*{{$service}}_{{$f.Name}}_Response, error) {
+	{{- if $f.Arguments}}
 	{{ArgVarsEquals $f}} req.unmarshall()
+	{{- end}}
 	{{RetVars $f "err"}} := handler.Service.{{$f.Name}}({{ArgVars $f "ctx"}})
 	if err != nil {
 		return nil, err
@@ -101,5 +112,57 @@ func (handler *{{$receiver}}) {{$f.Name -}}
 	rsp.marshall({{RetVars $f}})
 	return rsp, nil
 }
+{{- else}}
+func (handler *{{$receiver}}) {{$f.Name -}}
+		({{if not $m.StreamArg}}req *{{$service}}_{{$f.Name}}_Request, {{end}}stream {{$service}}_{{$f.Name}}Server) error {
+	ctx := stream.Context()
+	{{- with $arg := $m.StreamArg}}
+
+	// The first request carries the args; each subsequent request carries an element of {{$arg.Name}}
+	{{if $m.UnaryArgVars}}req{{else}}_{{end}}, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	{{$arg.Name}} := make(chan {{NameOf $m.ArgElem}})
+	go func() {
+		defer close({{$arg.Name}})
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			select {
+			case {{$arg.Name}} <- req.unmarshallItem():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	{{- end}}
+	{{- if $m.UnaryArgVars}}
+
+	{{$m.UnaryArgVarsEquals}} req.unmarshall()
+	{{- end}}
+
+	{{RetVars $f "err"}} {{HasNewReturnVars $f}} handler.Service.{{$f.Name}}({{ArgVars $f "ctx"}})
+	if err != nil {
+		return err
+	}
+	{{- if $m.StreamRet}}
+
+	// The first response carries the retvals; each subsequent response carries an element of the stream.
+	// Once a send fails, the remaining elements are discarded rather than sent, so that their producer doesn't block.
+	err = stream.Send(new({{$service}}_{{$f.Name}}_Response).marshall({{$m.UnaryRetVars}}))
+	for item := range {{$m.StreamRetVar}} {
+		if err == nil {
+			err = stream.Send(new({{$service}}_{{$f.Name}}_Response).marshallItem(item))
+		}
+	}
+	return err
+	{{- else}}
+	return stream.SendAndClose(new({{$service}}_{{$f.Name}}_Response).marshall({{$m.UnaryRetVars}}))
+	{{- end}}
+}
+{{- end}}
 {{end}}
-`
+`)
@@ -0,0 +1,122 @@
+package grpccodegen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/plugins/golang/gocode"
+)
+
+/*
+Service methods with channel arguments or retvals are mapped to streaming RPCs:
+  - A method with a chan T or <-chan T argument is a client-streaming RPC.  The first request
+    carries the method's other arguments, and each subsequent request carries one element of the channel.
+  - A method with a <-chan T retval is a server-streaming RPC.  The first response carries the
+    method's other retvals, and each subsequent response carries one element of the channel.
+  - A method with both is a bidirectional-streaming RPC.
+
+A method can stream at most one channel argument and one channel retval.  The streamed channels are
+closed when the stream ends, including if the stream fails part-way through.
+*/
+type grpcMethod struct {
+	gocode.Func
+	StreamArg *gocode.Variable // The channel argument that is streamed from client to server, or nil
+	StreamRet *gocode.Variable // The channel retval that is streamed from server to client, or nil
+	ArgElem   gocode.TypeName  // The element type of StreamArg
+	RetElem   gocode.TypeName  // The element type of StreamRet
+	argIndex  int              // The index of StreamArg within the method's arguments, or -1
+	retIndex  int              // The index of StreamRet within the method's retvals, or -1
+}
+
+func newGRPCMethod(service string, f gocode.Func) (*grpcMethod, error) {
+	m := &grpcMethod{Func: f, argIndex: -1, retIndex: -1}
+	for i, arg := range f.Arguments {
+		elem, isChan := gocode.ChanElem(arg.Type)
+		if !isChan {
+			continue
+		}
+		if _, isSendChan := arg.Type.(*gocode.SendChan); isSendChan {
+			return nil, blueprint.Errorf("GRPC cannot stream the send-only channel argument %v of %v.%v", arg.Name, service, f.Name)
+		}
+		if m.StreamArg != nil {
+			return nil, blueprint.Errorf("GRPC can only stream one channel argument of %v.%v, but %v and %v are both channels", service, f.Name, m.StreamArg.Name, arg.Name)
+		}
+		m.StreamArg, m.ArgElem, m.argIndex = &f.Arguments[i], elem, i
+	}
+	for i, ret := range f.Returns {
+		elem, isChan := gocode.ChanElem(ret.Type)
+		if !isChan {
+			continue
+		}
+		if _, isReceiveChan := ret.Type.(*gocode.ReceiveChan); !isReceiveChan {
+			return nil, blueprint.Errorf("GRPC can only stream receive-only channel retvals of %v.%v, but got %v", service, f.Name, ret.Type)
+		}
+		if m.StreamRet != nil {
+			return nil, blueprint.Errorf("GRPC can only stream one channel retval of %v.%v", service, f.Name)
+		}
+		m.StreamRet, m.RetElem, m.retIndex = &f.Returns[i], elem, i
+	}
+	return m, nil
+}
+
+// Returns the methods of service, keyed by name
+func newGRPCMethods(service *gocode.ServiceInterface) (map[string]*grpcMethod, error) {
+	methods := make(map[string]*grpcMethod)
+	for name, f := range service.Methods {
+		m, err := newGRPCMethod(service.Name, f)
+		if err != nil {
+			return nil, err
+		}
+		methods[name] = m
+	}
+	return methods, nil
+}
+
+// Reports whether the method is a streaming RPC
+func (m *grpcMethod) IsStreaming() bool {
+	return m.StreamArg != nil || m.StreamRet != nil
+}
+
+// The name of the variable holding StreamRet in generated code
+func (m *grpcMethod) StreamRetVar() string {
+	return fmt.Sprintf("ret%v", m.retIndex)
+}
+
+// The names of the arguments that aren't streamed, e.g. for the first request of a client-streaming RPC
+func (m *grpcMethod) UnaryArgVars() string {
+	var vars []string
+	for i, arg := range m.Arguments {
+		if i != m.argIndex {
+			vars = append(vars, arg.Name)
+		}
+	}
+	return strings.Join(vars, ", ")
+}
+
+// Declares the arguments that aren't streamed, or the empty string if there are none
+func (m *grpcMethod) UnaryArgVarsEquals() string {
+	if vars := m.UnaryArgVars(); vars != "" {
+		return vars + " :="
+	}
+	return ""
+}
+
+// The names of the retvals that aren't streamed, e.g. for the first response of a server-streaming RPC
+func (m *grpcMethod) UnaryRetVars() string {
+	var vars []string
+	for i := range m.Returns {
+		if i != m.retIndex {
+			vars = append(vars, fmt.Sprintf("ret%v", i))
+		}
+	}
+	return strings.Join(vars, ", ")
+}
+
+// Assigns the retvals that aren't streamed, or the empty string if there are none
+func (m *grpcMethod) UnaryRetVarsEquals() string {
+	if vars := m.UnaryRetVars(); vars != "" {
+		return vars + " ="
+	}
+	return ""
+}
@@ -13,6 +13,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*golangClient]()
+}
+
 /*
 IRNode representing a client to a Golang server.
 This node does not introduce any new runtime interfaces or types that can be used by other IRNodes
@@ -25,14 +29,14 @@ type golangClient struct {
 	InstanceName string
 	ServerAddr   *address.Address[*golangServer]
 
-	outputPackage string
+	OutputPackage string
 }
 
 func newGolangClient(name string, addr *address.Address[*golangServer]) (*golangClient, error) {
 	node := &golangClient{}
 	node.InstanceName = name
 	node.ServerAddr = addr
-	node.outputPackage = "grpc"
+	node.OutputPackage = "grpc"
 
 	return node, nil
 }
@@ -80,13 +84,13 @@ func (node *golangClient) GenerateFuncs(builder golang.ModuleBuilder) error {
 	}
 
 	// Generate the .proto files
-	err = grpccodegen.GenerateGRPCProto(builder, iface, node.outputPackage)
+	err = grpccodegen.GenerateGRPCProto(builder, iface, node.OutputPackage)
 	if err != nil {
 		return err
 	}
 
 	// Generate the RPC client
-	err = grpccodegen.GenerateClient(builder, iface, node.outputPackage)
+	err = grpccodegen.GenerateClient(builder, iface, node.OutputPackage)
 	if err != nil {
 		return err
 	}
@@ -107,7 +111,7 @@ func (node *golangClient) AddInstantiation(builder golang.NamespaceBuilder) erro
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_GRPCClient", iface.BaseName),
 			Arguments: []gocode.Variable{
@@ -12,6 +12,11 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*golangServer]()
+	ir.RegisterJSONType[*address.Address[*golangServer]]()
+}
+
 /*
 IRNode representing a Golang GPRC server.
 This node does not introduce any new runtime interfaces or types that can be used by other IRNodes
@@ -26,7 +31,7 @@ type golangServer struct {
 	Bind         *address.BindConfig
 	Wrapped      golang.Service
 
-	outputPackage string
+	OutputPackage string
 }
 
 // Represents a service that is exposed over GRPC
@@ -47,7 +52,7 @@ func newGolangServer(name string, service golang.Service) (*golangServer, error)
 	node := &golangServer{}
 	node.InstanceName = name
 	node.Wrapped = service
-	node.outputPackage = "grpc"
+	node.OutputPackage = "grpc"
 	return node, nil
 }
 
@@ -73,13 +78,13 @@ func (node *golangServer) GenerateFuncs(builder golang.ModuleBuilder) error {
 	}
 
 	// Generate the .proto files
-	err = grpccodegen.GenerateGRPCProto(builder, iface, node.outputPackage)
+	err = grpccodegen.GenerateGRPCProto(builder, iface, node.OutputPackage)
 	if err != nil {
 		return err
 	}
 
 	// Generate the RPC server handler
-	err = grpccodegen.GenerateServerHandler(builder, iface, node.outputPackage)
+	err = grpccodegen.GenerateServerHandler(builder, iface, node.OutputPackage)
 	if err != nil {
 		return err
 	}
@@ -99,7 +104,7 @@ func (node *golangServer) AddInstantiation(builder golang.NamespaceBuilder) erro
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_GRPCServerHandler", iface.BaseName),
 			Arguments: []gocode.Variable{
@@ -41,6 +41,18 @@
 // arguments into protobuf structs and vice versa.  This is implemented within
 // the [grpccodegen] package.
 //
+// Service methods with channel arguments or return values are generated as streaming RPCs.  A
+// <-chan T return value is streamed from server to client, and a chan T or <-chan T argument is
+// streamed from client to server; a method with both is a bidirectional-streaming RPC.  A method
+// can stream at most one channel argument and one channel return value.  A streamed argument is
+// only delivered while the service method is running, and the streams are cancelled if the
+// caller's context is cancelled, so services should stop sending on channels once ctx is done.
+//
+// Arguments and return values can use named types that are not structs.  A named integer type
+// with declared constants, e.g. type Status int, is generated as a proto enum; named slices and
+// maps, e.g. type Tags []string, are wrapped in a message; and aliases and other named types
+// are serialized like the types that they are declared as.
+//
 // To use this plugin requires the protocol buffers and grpc compilers are installed
 // on the machine that is compiling the Blueprint wiring spec.  Installation instructions
 // can be found on the [gRPC Quick Start].
@@ -93,7 +105,7 @@ func Deploy(spec wiring.WiringSpec, serviceName string) {
 	//
 	// The client-side modifier creates a gRPC client and dials the server address.
 	// It assumes the next src modifier node will be a golangServer address.
-	clientNext := ptr.AddSrcModifier(spec, grpcClient)
+	clientNext := ptr.AddSrcModifier(spec, grpcClient, pointer.RPCModifier)
 	spec.Define(grpcClient, &golangClient{}, func(namespace wiring.Namespace) (ir.IRNode, error) {
 		addr, err := address.Dial[*golangServer](namespace, clientNext)
 		if err != nil {
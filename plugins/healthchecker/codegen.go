@@ -29,7 +29,7 @@ func generateServerHandler(builder golang.ModuleBuilder, iface *gocode.ServiceIn
 
 	slog.Info(fmt.Sprintf("Generating %v/%v", server.Package.PackageName, iface.Name))
 	outputFile := filepath.Join(server.Package.Path, iface.Name+".go")
-	return gogen.ExecuteTemplateToFile("HealthChecker", serverTemplate, server, outputFile)
+	return serverTemplate.ExecuteToFile(builder, server, outputFile)
 }
 
 type serverArgs struct {
@@ -58,10 +58,10 @@ func generateClientSideInterfaces(builder golang.ModuleBuilder, iface *gocode.Se
 
 	slog.Info(fmt.Sprintf("Generating %v/%v", server.Package.PackageName, iface.Name))
 	outputFile := filepath.Join(server.Package.Path, iface.Name+".go")
-	return gogen.ExecuteTemplateToFile("HealthChecker", clientTemplate, server, outputFile)
+	return clientTemplate.ExecuteToFile(builder, server, outputFile)
 }
 
-var serverTemplate = `// Blueprint: Auto-generated by HealthChecker Plugin
+var serverTemplate = gogen.RegisterTemplate("healthchecker", "server", `// Blueprint: Auto-generated by HealthChecker Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -92,9 +92,9 @@ func (handler *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.C
 func (handler *{{$receiver}}) Health(ctx context.Context) (string, error) {
 	return "Healthy", nil
 }
-`
+`)
 
-var clientTemplate = `// Blueprint: Auto-generated by HealthChecker plugin
+var clientTemplate = gogen.RegisterTemplate("healthchecker", "client", `// Blueprint: Auto-generated by HealthChecker plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -104,4 +104,4 @@ type {{.IfaceName}} interface {
 	{{Signature $f}}
 	{{end}}
 }
-`
+`)
@@ -0,0 +1,9 @@
+package healthchecker
+
+import (
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("healthchecker.AddHealthCheckAPI", declarative.Apply(AddHealthCheckAPI))
+}
@@ -11,6 +11,10 @@ import (
 	"github.com/blueprint-uservices/blueprint/plugins/golang/gocode"
 )
 
+func init() {
+	ir.RegisterJSONType[*HealthCheckerServerWrapper]()
+}
+
 // Blueprint IR node representing a HealthChecker
 type HealthCheckerServerWrapper struct {
 	golang.Service
@@ -20,7 +24,7 @@ type HealthCheckerServerWrapper struct {
 	InstanceName string
 	Wrapped      golang.Service
 
-	outputPackage string
+	OutputPackage string
 }
 
 func (node *HealthCheckerServerWrapper) ImplementsGolangNode() {}
@@ -38,7 +42,7 @@ func (node *HealthCheckerServerWrapper) AddInterfaces(builder golang.ModuleBuild
 	if err != nil {
 		return err
 	}
-	err = generateClientSideInterfaces(builder, iface, node.outputPackage)
+	err = generateClientSideInterfaces(builder, iface, node.OutputPackage)
 	if err != nil {
 		return err
 	}
@@ -54,7 +58,7 @@ func newHealthCheckerServerWrapper(name string, server ir.IRNode) (*HealthChecke
 	node := &HealthCheckerServerWrapper{}
 	node.InstanceName = name
 	node.Wrapped = serverNode
-	node.outputPackage = "healthcheck"
+	node.OutputPackage = "healthcheck"
 
 	return node, nil
 }
@@ -68,7 +72,7 @@ func (node *HealthCheckerServerWrapper) genInterface(ctx ir.BuildContext) (*goco
 	if !valid {
 		return nil, blueprint.Errorf("Healthchecker expected build context to be a ModuleBuilder, got %v", ctx)
 	}
-	i := gocode.CopyServiceInterface(fmt.Sprintf("%v_HealthChecker", iface.BaseName), module_ctx.Info().Name+"/"+node.outputPackage, iface)
+	i := gocode.CopyServiceInterface(fmt.Sprintf("%v_HealthChecker", iface.BaseName), module_ctx.Info().Name+"/"+node.OutputPackage, iface)
 	health_check_method := &gocode.Func{}
 	health_check_method.Name = "Health"
 	health_check_method.Returns = append(health_check_method.Returns, gocode.Variable{Type: &gocode.BasicType{Name: "string"}})
@@ -89,7 +93,7 @@ func (node *HealthCheckerServerWrapper) GenerateFuncs(builder golang.ModuleBuild
 	if err != nil {
 		return err
 	}
-	err = generateServerHandler(builder, iface, service, node.outputPackage)
+	err = generateServerHandler(builder, iface, service, node.OutputPackage)
 	if err != nil {
 		return err
 	}
@@ -108,7 +112,7 @@ func (node *HealthCheckerServerWrapper) AddInstantiation(builder golang.Namespac
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_HealthCheckHandler", iface.BaseName),
 			Arguments: []gocode.Variable{
@@ -45,7 +45,7 @@ func AddHealthCheckAPI(spec wiring.WiringSpec, serviceName string) {
 	}
 
 	// Add the server wrapper to the pointer dst
-	serverNext := ptr.AddDstModifier(spec, serverWrapper)
+	serverNext := ptr.AddDstModifier(spec, serverWrapper, pointer.InProcessModifier)
 
 	// Define the server wrapper
 	spec.Define(serverWrapper, &HealthCheckerServerWrapper{}, func(ns wiring.Namespace) (ir.IRNode, error) {
@@ -0,0 +1,9 @@
+package http
+
+import (
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("http.Deploy", declarative.Apply(Deploy))
+}
@@ -12,6 +12,10 @@ import (
 
 // This function is used by the HTTP plugin to generate the client-side HTTP service
 func GenerateClient(builder golang.ModuleBuilder, service *gocode.ServiceInterface, outputPackage string) error {
+	if err := checkNoChannels(service); err != nil {
+		return err
+	}
+
 	pkg, err := builder.CreatePackage(outputPackage)
 	if err != nil {
 		return err
@@ -30,7 +34,7 @@ func GenerateClient(builder golang.ModuleBuilder, service *gocode.ServiceInterfa
 
 	slog.Info(fmt.Sprintf("Generating %v/%v.go", client.Package.PackageName, client.Name))
 	outputFile := filepath.Join(client.Package.Path, client.Name+".go")
-	return gogen.ExecuteTemplateToFile("HTTPClient", clientTemplate, client, outputFile)
+	return clientTemplate.ExecuteToFile(builder, client, outputFile)
 }
 
 // Arguments to the template code
@@ -41,7 +45,7 @@ type clientArgs struct {
 	Imports *gogen.Imports
 }
 
-var clientTemplate = `// Blueprint: Auto-generated by the HTTP Plugin
+var clientTemplate = gogen.RegisterTemplate("http", "client", `// Blueprint: Auto-generated by the HTTP Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -122,4 +126,4 @@ func (client *{{$receiver}}) {{SignatureWithRetVars $f}} {
 	return
 }
 {{end}}
-`
+`)
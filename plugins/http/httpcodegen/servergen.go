@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
 	"github.com/blueprint-uservices/blueprint/plugins/golang"
 	"github.com/blueprint-uservices/blueprint/plugins/golang/gocode"
 	"github.com/blueprint-uservices/blueprint/plugins/golang/gogen"
@@ -14,6 +15,10 @@ import (
 This function is used by the HTTP plugin to generate the server-side HTTP service.
 */
 func GenerateServerHandler(builder golang.ModuleBuilder, service *gocode.ServiceInterface, outputPackage string) error {
+	if err := checkNoChannels(service); err != nil {
+		return err
+	}
+
 	pkg, err := builder.CreatePackage(outputPackage)
 	if err != nil {
 		return err
@@ -30,7 +35,19 @@ func GenerateServerHandler(builder golang.ModuleBuilder, service *gocode.Service
 
 	slog.Info(fmt.Sprintf("Generating %v/%v_HTTPServer.go", server.Package.PackageName, service.BaseName))
 	outputFile := filepath.Join(server.Package.Path, service.BaseName+"_HTTPServer.go")
-	return gogen.ExecuteTemplateToFile("HTTPServer", serverTemplate, server, outputFile)
+	return serverTemplate.ExecuteToFile(builder, server, outputFile)
+}
+
+// Channels can't be sent as JSON, so the HTTP plugin doesn't support asynchronous or streaming methods
+func checkNoChannels(service *gocode.ServiceInterface) error {
+	for _, f := range service.Methods {
+		for _, v := range append(append([]gocode.Variable{}, f.Arguments...), f.Returns...) {
+			if _, isChan := gocode.ChanElem(v.Type); isChan {
+				return blueprint.Errorf("HTTP cannot serialize the channel %v of %v.%v; use an RPC plugin that supports streaming, such as gRPC", v.Type, service.Name, f.Name)
+			}
+		}
+	}
+	return nil
 }
 
 /*
@@ -43,7 +60,7 @@ type serverArgs struct {
 	Imports *gogen.Imports // Manages imports for us
 }
 
-var serverTemplate = `// Blueprint: Auto-generated by HTTP Plugin
+var serverTemplate = gogen.RegisterTemplate("http", "server", `// Blueprint: Auto-generated by HTTP Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -121,4 +138,4 @@ func (handler *{{$receiver}}) {{$f.Name -}}
 	json.NewEncoder(w).Encode(response)
 }
 {{end}}
-`
+`)
@@ -12,6 +12,10 @@ import (
 	"github.com/blueprint-uservices/blueprint/plugins/http/httpcodegen"
 )
 
+func init() {
+	ir.RegisterJSONType[*GolangHttpClient]()
+}
+
 // IRNode representing a client to a Golang server.
 // This node does not introduce any new runtime interfaces or types that can be used by other IRNodes.
 type GolangHttpClient struct {
@@ -23,14 +27,14 @@ type GolangHttpClient struct {
 	InstanceName string
 	ServerAddr   *address.Address[*golangHttpServer]
 
-	outputPackage string
+	OutputPackage string
 }
 
 func newGolangHttpClient(name string, addr *address.Address[*golangHttpServer]) (*GolangHttpClient, error) {
 	node := &GolangHttpClient{}
 	node.InstanceName = name
 	node.ServerAddr = addr
-	node.outputPackage = "http"
+	node.OutputPackage = "http"
 
 	return node, nil
 }
@@ -74,7 +78,7 @@ func (node *GolangHttpClient) GenerateFuncs(builder golang.ModuleBuilder) error
 		return err
 	}
 
-	return httpcodegen.GenerateClient(builder, iface, node.outputPackage)
+	return httpcodegen.GenerateClient(builder, iface, node.OutputPackage)
 }
 
 func (node *GolangHttpClient) AddInstantiation(builder golang.NamespaceBuilder) error {
@@ -89,7 +93,7 @@ func (node *GolangHttpClient) AddInstantiation(builder golang.NamespaceBuilder)
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_HTTPClient", iface.BaseName),
 			Arguments: []gocode.Variable{
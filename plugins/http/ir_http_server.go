@@ -13,6 +13,11 @@ import (
 	"github.com/blueprint-uservices/blueprint/plugins/http/httpcodegen"
 )
 
+func init() {
+	ir.RegisterJSONType[*golangHttpServer]()
+	ir.RegisterJSONType[*address.Address[*golangHttpServer]]()
+}
+
 // IRNode representing a Golang HTTP server.
 // This node does not introduce any new runtime interfaces or types that can be used by other IRNodes.
 type golangHttpServer struct {
@@ -24,7 +29,7 @@ type golangHttpServer struct {
 	Bind         *address.BindConfig
 	Wrapped      golang.Service
 
-	outputPackage string
+	OutputPackage string
 }
 
 // Represents a service that is exposed over HTTP
@@ -50,7 +55,7 @@ func newGolangHttpServer(name string, wrapped ir.IRNode) (*golangHttpServer, err
 	node := &golangHttpServer{}
 	node.InstanceName = name
 	node.Wrapped = service
-	node.outputPackage = "http"
+	node.OutputPackage = "http"
 	return node, nil
 }
 
@@ -69,7 +74,7 @@ func (node *golangHttpServer) GenerateFuncs(builder golang.ModuleBuilder) error
 		return err
 	}
 
-	err = httpcodegen.GenerateServerHandler(builder, iface, node.outputPackage)
+	err = httpcodegen.GenerateServerHandler(builder, iface, node.OutputPackage)
 	if err != nil {
 		return err
 	}
@@ -88,7 +93,7 @@ func (node *golangHttpServer) AddInstantiation(builder golang.NamespaceBuilder)
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_HTTPServerHandler", iface.BaseName),
 			Arguments: []gocode.Variable{
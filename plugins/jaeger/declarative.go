@@ -0,0 +1,9 @@
+package jaeger
+
+import (
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("jaeger.Collector", declarative.Define(Collector))
+}
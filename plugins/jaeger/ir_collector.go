@@ -10,6 +10,11 @@ import (
 	"github.com/blueprint-uservices/blueprint/runtime/plugins/jaeger"
 )
 
+func init() {
+	ir.RegisterJSONType[*JaegerCollectorContainer]()
+	ir.RegisterJSONType[*address.Address[*JaegerCollectorContainer]]()
+}
+
 // Blueprint IR node that represents the Jaeger container
 type JaegerCollectorContainer struct {
 	docker.Container
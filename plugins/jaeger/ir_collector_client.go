@@ -12,6 +12,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*JaegerCollectorClient]()
+}
+
 // Blueprint IR node representing a client to the jaeger container
 type JaegerCollectorClient struct {
 	golang.Node
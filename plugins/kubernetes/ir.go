@@ -13,6 +13,10 @@ import (
 	"github.com/blueprint-uservices/blueprint/plugins/linux"
 )
 
+func init() {
+	ir.RegisterJSONType[*Application]()
+}
+
 // An IRNode representing a Kubernetes applicaiton deployment which is a collection of Kubernetes Pod + Service Deployment instances.
 type Application struct {
 	AppName string
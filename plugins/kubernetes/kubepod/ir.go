@@ -6,6 +6,10 @@ import (
 	"github.com/blueprint-uservices/blueprint/plugins/docker"
 )
 
+func init() {
+	ir.RegisterJSONType[*PodDeployment]()
+}
+
 // An IRNode representing a Kubernetes pod, which is simply a collection of container instances.
 type PodDeployment struct {
 	kubePodDeployment
@@ -28,7 +28,7 @@ func generateServerWrapper(builder golang.ModuleBuilder, wrapped *gocode.Service
 	slog.Info(fmt.Sprintf("Generating %v/%v", server.Package.PackageName, wrapped.BaseName+"_LatencyInjector"))
 	outputFile := filepath.Join(server.Package.Path, wrapped.BaseName+"_LatencyInjector.go")
 
-	return gogen.ExecuteTemplateToFile("LatencyInjector", serverTemplate, server, outputFile)
+	return serverTemplate.ExecuteToFile(builder, server, outputFile)
 }
 
 type serverArgs struct {
@@ -38,7 +38,7 @@ type serverArgs struct {
 	Imports *gogen.Imports
 }
 
-var serverTemplate = `// Blueprint: Auto-generated by LatencyInjector Plugin
+var serverTemplate = gogen.RegisterTemplate("latency", "server", `// Blueprint: Auto-generated by LatencyInjector Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -67,4 +67,4 @@ func (server *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.Co
 	return server.Server.{{$f.Name}}({{ArgVars $f "ctx"}})
 }
 {{end}}
-`
+`)
@@ -0,0 +1,21 @@
+package latency
+
+import (
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("latency.AddFixed", func(spec wiring.WiringSpec, step declarative.Step) ([]string, error) {
+		if err := step.CheckOptions("latency"); err != nil {
+			return nil, err
+		}
+		latency, err := step.GetDuration("latency")
+		if err != nil {
+			return nil, err
+		}
+		return declarative.Apply(func(spec wiring.WiringSpec, serviceName string) {
+			AddFixed(spec, serviceName, latency)
+		})(spec, step)
+	})
+}
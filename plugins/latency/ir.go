@@ -11,6 +11,10 @@ import (
 	"github.com/blueprint-uservices/blueprint/plugins/golang/gocode"
 )
 
+func init() {
+	ir.RegisterJSONType[*LatencyInjectorWrapper]()
+}
+
 // Blueprint IR Node representing a server side latency injector
 type LatencyInjectorWrapper struct {
 	golang.Service
@@ -19,7 +23,7 @@ type LatencyInjectorWrapper struct {
 
 	InstanceName  string
 	Wrapped       golang.Service
-	outputPackage string
+	OutputPackage string
 	LatencyValue  *ir.IRValue
 }
 
@@ -32,7 +36,7 @@ func newLatencyInjectorWrapper(name string, server ir.IRNode, latency string) (*
 	node := &LatencyInjectorWrapper{}
 	node.InstanceName = name
 	node.Wrapped = serverNode
-	node.outputPackage = "latencyinjector"
+	node.OutputPackage = "latencyinjector"
 	node.LatencyValue = &ir.IRValue{Value: latency}
 	return node, nil
 }
@@ -74,7 +78,7 @@ func (node *LatencyInjectorWrapper) GenerateFuncs(builder golang.ModuleBuilder)
 		return err
 	}
 
-	return generateServerWrapper(builder, iface, node.outputPackage)
+	return generateServerWrapper(builder, iface, node.OutputPackage)
 }
 
 // Implements golang.Instantiable
@@ -89,7 +93,7 @@ func (node *LatencyInjectorWrapper) AddInstantiation(builder golang.NamespaceBui
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_LatencyInjector", iface.BaseName),
 			Arguments: []gocode.Variable{
@@ -39,7 +39,7 @@ func AddFixed(spec wiring.WiringSpec, serviceName string, latency string) {
 		slog.Error("Unable to add a latencyinjector to " + serviceName + " as it is not a pointer")
 	}
 
-	serverNext := ptr.AddDstModifier(spec, serverWrapper)
+	serverNext := ptr.AddDstModifier(spec, serverWrapper, pointer.InProcessModifier)
 
 	spec.Define(serverWrapper, &LatencyInjectorWrapper{}, func(ns wiring.Namespace) (ir.IRNode, error) {
 		var wrapped golang.Service
@@ -0,0 +1,10 @@
+package linuxcontainer
+
+import (
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("linuxcontainer.Deploy", declarative.ApplyAndDefine(Deploy))
+	declarative.Register("linuxcontainer.CreateContainer", declarative.DefineWith(CreateContainer))
+}
@@ -3,6 +3,7 @@ package linuxcontainer
 import (
 	"fmt"
 	"path/filepath"
+	"sync"
 
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint/ioutil"
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
@@ -53,6 +54,8 @@ type (
 
 		Build *linuxgen.BuildScript
 		Run   *linuxgen.RunScript
+
+		lock *sync.Mutex // Guards the above, since processes can add their artifacts concurrently
 	}
 )
 
@@ -82,14 +85,17 @@ Process nodes that implement AddProcessArtifacts and AddProcessInstance
 can typecheck the workspace to utilize those platform-specific commands.
 */
 func (node *Container) generateArtifacts(workspace linux.ProcessWorkspace) error {
-	// Add all processes artifacts to the workspace
+	// Add all processes artifacts to the workspace.  Each process has its own
+	// subdirectory, so the processes are generated concurrently.
+	var tasks []func() error
 	for _, child := range node.Nodes {
 		if n, valid := child.(linux.ProvidesProcessArtifacts); valid {
-			if err := n.AddProcessArtifacts(workspace); err != nil {
-				return err
-			}
+			tasks = append(tasks, func() error { return n.AddProcessArtifacts(workspace) })
 		}
 	}
+	if err := ir.RunConcurrently(tasks); err != nil {
+		return err
+	}
 
 	// Collect the scripts to run the processes
 	for _, child := range node.Nodes {
@@ -116,6 +122,7 @@ func NewBasicWorkspace(name string, dir string) *filesystemWorkspace {
 		Build:    linuxgen.NewBuildScript(dir, "build.sh"),
 		Run:      linuxgen.NewRunScript(name, dir, "run.sh"),
 		ProcDirs: make(map[string]string),
+		lock:     &sync.Mutex{},
 	}
 }
 
@@ -129,6 +136,8 @@ func (workspace *filesystemWorkspace) Info() linux.ProcessWorkspaceInfo {
 // Creates a subdirectory for a process to output its artifacts.
 // Saves the metadata about the process
 func (ws *filesystemWorkspace) CreateProcessDir(name string) (string, error) {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
 	path, err := ioutil.CreateNodeDir(ws.info.Path, name)
 	ws.ProcDirs[ir.CleanName(name)] = path
 	return path, err
@@ -138,6 +147,8 @@ func (ws *filesystemWorkspace) CreateProcessDir(name string) (string, error) {
 //
 // Adds a build script provided by a process
 func (ws *filesystemWorkspace) AddBuildScript(path string) error {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
 	return ws.Build.Add(path)
 }
 
@@ -147,6 +158,8 @@ func (ws *filesystemWorkspace) AddBuildScript(path string) error {
 func (ws *filesystemWorkspace) DeclareRunCommand(name string, runfunc string, deps ...ir.IRNode) error {
 	// Generate the runfunc
 	runfunc_impl, err := linuxgen.GenerateRunFunc(name, runfunc, deps...)
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
 	ws.Run.Add(name, runfunc_impl, deps...)
 	return err
 }
@@ -100,6 +100,8 @@ func NewDockerWorkspace(name string, dir string) *dockerWorkspaceImpl {
 
 // Implements docker.ProcessWorkspace
 func (ws *dockerWorkspaceImpl) AddDockerfileCommands(procName, commands string) {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
 	ws.Dockerfile.AddCustomCommands(procName, commands)
 }
 
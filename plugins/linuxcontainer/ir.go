@@ -4,6 +4,10 @@ import (
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
 )
 
+func init() {
+	ir.RegisterJSONType[*Container]()
+}
+
 /*
 linuxcontainer.Container is a node that represents a collection of runnable linux processes.
 It can contain any number of other process.Node IRNodes.  When it's compiled, the goproc.Process
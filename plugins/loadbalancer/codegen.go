@@ -38,10 +38,10 @@ func generateLBServer(builder golang.ModuleBuilder, iface *gocode.ServiceInterfa
 	templ.Imports.AddType(&iface.UserType)
 	slog.Info(fmt.Sprintf("Generating %v/%v", templ.Package.PackageName, templ.Name))
 	outputFile := filepath.Join(templ.Package.Path, templ.Name+".go")
-	return gogen.ExecuteTemplateToFile("LoadBalancer", lbServerTemplate, templ, outputFile)
+	return lbServerTemplate.ExecuteToFile(builder, templ, outputFile)
 }
 
-var lbServerTemplate = `// Blueprint: Auto-generated by LoadBalance plugin
+var lbServerTemplate = gogen.RegisterTemplate("loadbalancer", "lbServer", `// Blueprint: Auto-generated by LoadBalance plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -83,4 +83,4 @@ func (balancer *{{$receiver}}) {{$f.Name}} ({{ArgVarsAndTypes $f "ctx context.Co
 	return client.{{$f.Name}}({{ArgVars $f "ctx"}})
 }
 {{end}}
-`
+`)
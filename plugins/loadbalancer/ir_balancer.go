@@ -14,6 +14,11 @@ import (
 	"github.com/blueprint-uservices/blueprint/plugins/workflow/workflowspec"
 )
 
+func init() {
+	ir.RegisterJSONType[*dynamicLBHandler]()
+	ir.RegisterJSONType[*dynamicLBClient]()
+}
+
 type dynamicLBNode struct {
 	golang.Service
 
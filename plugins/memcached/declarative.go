@@ -0,0 +1,9 @@
+package memcached
+
+import (
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("memcached.Container", declarative.Define(Container))
+}
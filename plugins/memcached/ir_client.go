@@ -13,6 +13,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*MemcachedGoClient]()
+}
+
 // Blueprint IR Node that represents a client to a memcached container
 type MemcachedGoClient struct {
 	golang.Service
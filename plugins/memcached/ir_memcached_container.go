@@ -11,6 +11,11 @@ import (
 	"github.com/blueprint-uservices/blueprint/runtime/plugins/memcached"
 )
 
+func init() {
+	ir.RegisterJSONType[*MemcachedContainer]()
+	ir.RegisterJSONType[*address.Address[*MemcachedContainer]]()
+}
+
 // Blueprint IR Node that represents a memcached container
 type MemcachedContainer struct {
 	backend.Cache
@@ -0,0 +1,9 @@
+package mongodb
+
+import (
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("mongodb.Container", declarative.Define(Container))
+}
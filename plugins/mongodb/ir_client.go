@@ -13,6 +13,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*MongoDBGoClient]()
+}
+
 // Blueprint IR Node that represents the generated client for the mongodb container
 type MongoDBGoClient struct {
 	golang.Service
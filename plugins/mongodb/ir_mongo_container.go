@@ -11,6 +11,11 @@ import (
 	"github.com/blueprint-uservices/blueprint/runtime/plugins/mongodb"
 )
 
+func init() {
+	ir.RegisterJSONType[*MongoDBContainer]()
+	ir.RegisterJSONType[*address.Address[*MongoDBContainer]]()
+}
+
 // Blueprint IR Node that represents the server side docker container
 type MongoDBContainer struct {
 	docker.Container
@@ -0,0 +1,9 @@
+package mysql
+
+import (
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("mysql.Container", declarative.Define(Container))
+}
@@ -13,6 +13,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*MySQLDBGoClient]()
+}
+
 // Blueprint IR Node that represents the generated client for the mysql container
 type MySQLDBGoClient struct {
 	golang.Service
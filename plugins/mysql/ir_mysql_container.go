@@ -11,6 +11,11 @@ import (
 	"github.com/blueprint-uservices/blueprint/runtime/plugins/mysql"
 )
 
+func init() {
+	ir.RegisterJSONType[*MySQLDBContainer]()
+	ir.RegisterJSONType[*address.Address[*MySQLDBContainer]]()
+}
+
 // Blueprint IR Node that represents the server side docker container
 type MySQLDBContainer struct {
 	backend.RelDB
@@ -21,7 +26,7 @@ type MySQLDBContainer struct {
 	BindAddr     *address.BindConfig
 	Iface        *goparser.ParsedInterface
 
-	password string
+	Password string
 }
 
 // MySQL interface exposed by the docker container.
@@ -47,7 +52,7 @@ func newMySQLDBContainer(name, root_password string) (*MySQLDBContainer, error)
 	cntr := &MySQLDBContainer{
 		InstanceName: name,
 		Iface:        spec.Iface,
-		password:     root_password,
+		Password:     root_password,
 	}
 	return cntr, nil
 }
@@ -81,5 +86,5 @@ func (m *MySQLDBContainer) AddContainerInstance(target docker.ContainerWorkspace
 		return err
 	}
 
-	return target.SetEnvironmentVariable(m.InstanceName, "MYSQL_ROOT_PASSWORD", m.password)
+	return target.SetEnvironmentVariable(m.InstanceName, "MYSQL_ROOT_PASSWORD", m.Password)
 }
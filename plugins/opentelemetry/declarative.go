@@ -0,0 +1,21 @@
+package opentelemetry
+
+import (
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("opentelemetry.Instrument", func(spec wiring.WiringSpec, step declarative.Step) ([]string, error) {
+		if err := step.CheckOptions("collector"); err != nil {
+			return nil, err
+		}
+		collector, err := step.GetString("collector")
+		if err != nil {
+			return nil, err
+		}
+		return declarative.Apply(func(spec wiring.WiringSpec, serviceName string) {
+			Instrument(spec, serviceName, collector)
+		})(spec, step)
+	})
+}
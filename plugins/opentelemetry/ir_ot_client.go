@@ -13,13 +13,17 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*OpenTelemetryClientWrapper]()
+}
+
 // Blueprint IR Node that wraps the client-side of a service to generate ot compatible logs
 type OpenTelemetryClientWrapper struct {
 	golang.Service
 	golang.GeneratesFuncs
 
 	WrapperName    string
-	outputPackage  string
+	OutputPackage  string
 	Wrapped        golang.Service
 	Collector      OpenTelemetryCollectorInterface
 	GenClientSpans bool
@@ -30,7 +34,7 @@ func newOpenTelemetryClientWrapper(name string, server golang.Service, collector
 	node.WrapperName = name
 	node.Wrapped = server
 	node.Collector = collector
-	node.outputPackage = "ot"
+	node.OutputPackage = "ot"
 	node.GenClientSpans = genClientSpans
 	return node, nil
 }
@@ -52,7 +56,7 @@ func (node *OpenTelemetryClientWrapper) genInterface(ctx ir.BuildContext) (*goco
 	if !valid {
 		return nil, blueprint.Errorf("OTClientWrapper expected build context to be a ModuleBuiler, got %v", ctx)
 	}
-	i := gocode.CopyServiceInterface(fmt.Sprintf("%v_OTClientWrapperInterface", iface.BaseName), module_ctx.Info().Name+"/"+node.outputPackage, iface)
+	i := gocode.CopyServiceInterface(fmt.Sprintf("%v_OTClientWrapperInterface", iface.BaseName), module_ctx.Info().Name+"/"+node.OutputPackage, iface)
 	for name, method := range i.Methods {
 		method.Arguments = method.Arguments[:len(method.Arguments)-1]
 		i.Methods[name] = method
@@ -93,7 +97,7 @@ func (node *OpenTelemetryClientWrapper) GenerateFuncs(builder golang.ModuleBuild
 		return nil
 	}
 
-	return generateClientHandler(builder, wrapped_iface, impl_iface, coll_iface, node.outputPackage, node.GenClientSpans)
+	return generateClientHandler(builder, wrapped_iface, impl_iface, coll_iface, node.OutputPackage, node.GenClientSpans)
 }
 
 // Part of code generation compilation pass; provides instantiation snippet
@@ -114,7 +118,7 @@ func (node *OpenTelemetryClientWrapper) AddInstantiation(builder golang.Namespac
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_OTClientWrapper", iface.BaseName),
 			Arguments: []gocode.Variable{
@@ -153,7 +157,7 @@ func generateClientHandler(builder golang.ModuleBuilder, wrapped *gocode.Service
 
 	slog.Info(fmt.Sprintf("Generating %v/%v", server.Package.PackageName, impl.Name))
 	outputFile := filepath.Join(server.Package.Path, impl.Name+".go")
-	return gogen.ExecuteTemplateToFile("OTClientWrapper", clientSideTemplate, server, outputFile)
+	return clientSideTemplate.ExecuteToFile(builder, server, outputFile)
 }
 
 type clientArgs struct {
@@ -168,7 +172,7 @@ type clientArgs struct {
 	GenSpans        bool
 }
 
-var clientSideTemplate = `// Blueprint: Auto-generated by OT Plugin
+var clientSideTemplate = gogen.RegisterTemplate("opentelemetry", "clientSide", `// Blueprint: Auto-generated by OT Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -212,4 +216,4 @@ func (handler *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.C
 	return
 }
 {{end}}
-`
+`)
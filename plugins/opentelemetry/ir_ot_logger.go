@@ -11,6 +11,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*OTTraceLogger]()
+}
+
 // Blueprint IR Node that represents a process-level OT trace logger
 type OTTraceLogger struct {
 	golang.Node
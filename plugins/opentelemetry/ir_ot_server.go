@@ -14,6 +14,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*OpenTelemetryServerWrapper]()
+}
+
 // Blueprint IR Node that wraps the server-side of a service to generate ot compatible logs
 type OpenTelemetryServerWrapper struct {
 	golang.Service
@@ -21,7 +25,7 @@ type OpenTelemetryServerWrapper struct {
 	golang.GeneratesFuncs
 
 	WrapperName   string
-	outputPackage string
+	OutputPackage string
 	Wrapped       golang.Service
 	Collector     OpenTelemetryCollectorInterface
 }
@@ -41,7 +45,7 @@ func newOpenTelemetryServerWrapper(name string, server ir.IRNode, collector ir.I
 	node.WrapperName = name
 	node.Wrapped = serverNode
 	node.Collector = collectorClient
-	node.outputPackage = "ot"
+	node.OutputPackage = "ot"
 	return node, nil
 }
 
@@ -62,7 +66,7 @@ func (node *OpenTelemetryServerWrapper) genInterface(ctx ir.BuildContext) (*goco
 	if !valid {
 		return nil, blueprint.Errorf("OTServerWrapper expected build context to be a ModuleBuilder, got %v", ctx)
 	}
-	i := gocode.CopyServiceInterface(fmt.Sprintf("%v_OTServerWrapperInterface", iface.BaseName), module_ctx.Info().Name+"/"+node.outputPackage, iface)
+	i := gocode.CopyServiceInterface(fmt.Sprintf("%v_OTServerWrapperInterface", iface.BaseName), module_ctx.Info().Name+"/"+node.OutputPackage, iface)
 	for name, method := range i.Methods {
 		method.AddArgument(gocode.Variable{Name: "traceCtx", Type: &gocode.BasicType{Name: "string"}})
 		i.Methods[name] = method
@@ -90,7 +94,7 @@ func (node *OpenTelemetryServerWrapper) AddInterfaces(builder golang.ModuleBuild
 		return nil
 	}
 
-	err = generateClientSideInterfaces(builder, iface, node.outputPackage)
+	err = generateClientSideInterfaces(builder, iface, node.OutputPackage)
 	if err != nil {
 		return err
 	}
@@ -121,7 +125,7 @@ func (node *OpenTelemetryServerWrapper) GenerateFuncs(builder golang.ModuleBuild
 		return nil
 	}
 
-	return generateServerHandler(builder, wrapped_iface, impl_iface, coll_iface, node.outputPackage)
+	return generateServerHandler(builder, wrapped_iface, impl_iface, coll_iface, node.OutputPackage)
 }
 
 // Part of code generation compilation pass; provides instantiation snippet
@@ -142,7 +146,7 @@ func (node *OpenTelemetryServerWrapper) AddInstantiation(builder golang.Namespac
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_OTServerWrapper", iface.BaseName),
 			Arguments: []gocode.Variable{
@@ -190,14 +194,14 @@ func generateServerHandler(builder golang.ModuleBuilder, wrapped *gocode.Service
 
 	slog.Info(fmt.Sprintf("Generating %v/%v", server.Package.PackageName, "env.sh"))
 	outputFile := filepath.Join(server.Package.Path, "env.sh")
-	err = gogen.ExecuteTemplateToFile("OTServerWrapper", envTemplate, env, outputFile)
+	err = envTemplate.ExecuteToFile(builder, env, outputFile)
 	if err != nil {
 		return err
 	}
 
 	slog.Info(fmt.Sprintf("Generating %v/%v", server.Package.PackageName, impl.Name))
 	outputFile = filepath.Join(server.Package.Path, impl.Name+".go")
-	return gogen.ExecuteTemplateToFile("OTServerWrapper", serverTemplate, server, outputFile)
+	return serverTemplate.ExecuteToFile(builder, server, outputFile)
 }
 
 func generateClientSideInterfaces(builder golang.ModuleBuilder, iface *gocode.ServiceInterface, outputPackage string) error {
@@ -216,10 +220,10 @@ func generateClientSideInterfaces(builder golang.ModuleBuilder, iface *gocode.Se
 	server.Imports.AddPackages("context")
 	slog.Info(fmt.Sprintf("Generating %v/%v", server.Package.PackageName, iface.Name))
 	outputFile := filepath.Join(server.Package.Path, iface.Name+".go")
-	return gogen.ExecuteTemplateToFile("OTServerWrapper", clientTemplate, server, outputFile)
+	return clientTemplate.ExecuteToFile(builder, server, outputFile)
 }
 
-var serverTemplate = `// Blueprint: Auto-generated by XTrace Plugin
+var serverTemplate = gogen.RegisterTemplate("opentelemetry", "server", `// Blueprint: Auto-generated by XTrace Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -263,9 +267,9 @@ func (handler *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.C
 	return
 }
 {{end}}
-`
+`)
 
-var clientTemplate = `// Blueprint: Auto-generated by OT plugin
+var clientTemplate = gogen.RegisterTemplate("opentelemetry", "client", `// Blueprint: Auto-generated by OT plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -275,14 +279,14 @@ type {{.IfaceName}} interface {
 	{{Signature $f}}
 	{{end}}
 }
-`
+`)
 
 type envArgs struct {
 	Name string
 }
 
-var envTemplate = `#!/bin/bash 
+var envTemplate = gogen.RegisterTemplate("opentelemetry", "env", `#!/bin/bash 
 # Auto-generated by OT plugin
 
 export OTEL_SERVICE_NAME="{{.Name}}"
-`
+`)
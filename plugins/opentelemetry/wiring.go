@@ -74,7 +74,7 @@ func instrument(spec wiring.WiringSpec, serviceName string, collectorName string
 	}
 
 	// Add the client wrapper to the pointer src
-	clientNext := ptr.AddSrcModifier(spec, clientWrapper)
+	clientNext := ptr.AddSrcModifier(spec, clientWrapper, pointer.InProcessModifier)
 
 	// Define the client wrapper
 	spec.Define(clientWrapper, &OpenTelemetryClientWrapper{}, func(namespace wiring.Namespace) (ir.IRNode, error) {
@@ -94,7 +94,7 @@ func instrument(spec wiring.WiringSpec, serviceName string, collectorName string
 	})
 
 	// Add the server wrapper to the pointer dst
-	serverNext := ptr.AddDstModifier(spec, serverWrapper)
+	serverNext := ptr.AddDstModifier(spec, serverWrapper, pointer.InProcessModifier)
 
 	// Define the server wrapper
 	spec.Define(serverWrapper, &OpenTelemetryServerWrapper{}, func(namespace wiring.Namespace) (ir.IRNode, error) {
@@ -0,0 +1,308 @@
+// Package callgraph provides an analysis pass that statically extracts the method-level call graph
+// between an application's workflow services.
+//
+// Blueprint knows which services depend on which from the arguments of their constructors.  The call
+// graph goes further, and records which methods of each service call which methods of its dependencies.
+// It is computed by parsing the workflow spec's code: the constructor of each service is inspected to
+// find which fields of the implementing struct hold which dependencies, then the body of each service
+// method is searched for calls through those fields.  Calls made by helper methods on the same struct are
+// attributed to the service methods that call the helpers.
+//
+// The analysis is syntactic and best effort.  Calls through local variables, or through fields that
+// aren't assigned directly from a constructor argument, aren't found.
+package callgraph
+
+import (
+	"fmt"
+	"go/ast"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+	"github.com/blueprint-uservices/blueprint/plugins/golang/goparser"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow/workflowspec"
+)
+
+// A method of a service, or of a dependency such as a backend
+type Method struct {
+	Service string `json:"service"` // The instance name of the service or dependency
+	Method  string `json:"method"`
+}
+
+// A call from a method of a workflow service to a method of one of its dependencies
+type Call struct {
+	Caller   Method `json:"caller"`
+	Callee   Method `json:"callee"`
+	Field    string `json:"field"`              // The field of the caller's implementation that holds the dependency
+	Position string `json:"position"`           // Where the call is made, as file:line within the caller's module
+	Indirect bool   `json:"indirect,omitempty"` // True if the call is made by a helper method that Caller calls
+}
+
+// The method-level call graph of an application
+type CallGraph struct {
+	Methods []Method `json:"methods"` // The methods of every workflow service in the application
+	Calls   []Call   `json:"calls"`
+}
+
+func (m Method) String() string {
+	return m.Service + "." + m.Method
+}
+
+func (c Call) String() string {
+	via := ""
+	if c.Indirect {
+		via = " indirectly"
+	}
+	return fmt.Sprintf("%v -> %v%s via %s (%s)", c.Caller, c.Callee, via, c.Field, c.Position)
+}
+
+func (g *CallGraph) String() string {
+	var b strings.Builder
+	for _, call := range g.Calls {
+		b.WriteString(call.String())
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Returns the calls made by the specified method
+func (g *CallGraph) Callees(service, method string) []Call {
+	var calls []Call
+	for _, call := range g.Calls {
+		if call.Caller.Service == service && call.Caller.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Returns every method that can be reached, directly or transitively, by calling the specified method.
+// This is the set of methods that a single call to the method can fan out to.
+func (g *CallGraph) Reachable(service, method string) []Method {
+	visited := make(map[Method]struct{})
+	var reachable []Method
+	var visit func(m Method)
+	visit = func(m Method) {
+		for _, call := range g.Callees(m.Service, m.Method) {
+			if _, seen := visited[call.Callee]; seen {
+				continue
+			}
+			visited[call.Callee] = struct{}{}
+			reachable = append(reachable, call.Callee)
+			visit(call.Callee)
+		}
+	}
+	visit(Method{Service: service, Method: method})
+	sortMethods(reachable)
+	return reachable
+}
+
+// Extracts the call graph of the workflow services in app
+func Extract(app *ir.ApplicationNode) *CallGraph {
+	g := &CallGraph{}
+	seen := make(map[string]struct{})
+	for _, handler := range workflow.FilterWorkflowNodes(app.GetAllIRNodes()) {
+		if _, exists := seen[handler.InstanceName]; exists || handler.ServiceInfo == nil {
+			continue
+		}
+		seen[handler.InstanceName] = struct{}{}
+
+		for name := range handler.ServiceInfo.Iface.Methods {
+			g.Methods = append(g.Methods, Method{Service: handler.InstanceName, Method: name})
+		}
+
+		// Name the dependencies after the services that they are clients of
+		deps := make([]string, len(handler.Args))
+		for i, arg := range handler.Args {
+			if arg == nil {
+				continue
+			}
+			if name, isWorkflow := workflow.ServiceName(arg); isWorkflow {
+				deps[i] = name
+			} else if _, isValue := arg.(*ir.IRValue); !isValue {
+				deps[i] = arg.Name()
+			}
+		}
+		g.Calls = append(g.Calls, extractCalls(handler.InstanceName, handler.ServiceInfo, deps)...)
+	}
+
+	sortMethods(g.Methods)
+	sort.SliceStable(g.Calls, func(i, j int) bool {
+		a, b := g.Calls[i], g.Calls[j]
+		if a.Caller != b.Caller {
+			return a.Caller.String() < b.Caller.String()
+		}
+		if a.Callee != b.Callee {
+			return a.Callee.String() < b.Callee.String()
+		}
+		return a.Position < b.Position
+	})
+	return g
+}
+
+func sortMethods(methods []Method) {
+	sort.Slice(methods, func(i, j int) bool { return methods[i].String() < methods[j].String() })
+}
+
+// Finds the calls from the methods of service to its dependencies.  deps are the names of the
+// dependencies, in the order of the constructor's arguments after the context argument; the empty
+// string for arguments that aren't dependencies.
+func extractCalls(serviceName string, service *workflowspec.Service, deps []string) []Call {
+	fields := dependencyFields(service, deps)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	var calls []Call
+	for name := range service.Iface.Methods {
+		caller := Method{Service: serviceName, Method: name}
+		method, exists := service.Struct.Methods[name]
+		if !exists {
+			continue
+		}
+		visited := map[string]struct{}{name: {}}
+		calls = append(calls, methodCalls(caller, service.Struct, method, fields, visited, false)...)
+	}
+	return calls
+}
+
+// Finds the calls made by method, and by any helper methods on the same struct that method calls
+func methodCalls(caller Method, struc *goparser.ParsedStruct, method *goparser.ParsedFunc, fields map[string]string, visited map[string]struct{}, indirect bool) []Call {
+	receiver := receiverName(method)
+	if receiver == "" || method.Body == nil {
+		return nil
+	}
+
+	var calls []Call
+	ast.Inspect(method.Body, func(n ast.Node) bool {
+		call, isCall := n.(*ast.CallExpr)
+		if !isCall {
+			return true
+		}
+		sel, isSel := call.Fun.(*ast.SelectorExpr)
+		if !isSel {
+			return true
+		}
+
+		switch x := sel.X.(type) {
+		case *ast.SelectorExpr:
+			// receiver.field.Method(...)
+			if id, isIdent := x.X.(*ast.Ident); isIdent && id.Name == receiver {
+				if dep, isDep := fields[x.Sel.Name]; isDep {
+					calls = append(calls, Call{
+						Caller:   caller,
+						Callee:   Method{Service: dep, Method: sel.Sel.Name},
+						Field:    x.Sel.Name,
+						Position: position(method, call),
+						Indirect: indirect,
+					})
+				}
+			}
+		case *ast.Ident:
+			// receiver.helper(...)
+			if x.Name == receiver {
+				if helper, isMethod := struc.Methods[sel.Sel.Name]; isMethod {
+					if _, seen := visited[sel.Sel.Name]; !seen {
+						visited[sel.Sel.Name] = struct{}{}
+						calls = append(calls, methodCalls(caller, struc, helper, fields, visited, true)...)
+					}
+				}
+			}
+		}
+		return true
+	})
+	return calls
+}
+
+// Inspects the service's constructor to determine which fields of the struct hold which dependencies.
+// Returns a map from field name to dependency name.
+func dependencyFields(service *workflowspec.Service, deps []string) map[string]string {
+	ctor := service.Constructor
+	params := make(map[string]string)
+	for i, arg := range ctor.Arguments[1:] {
+		if i < len(deps) && deps[i] != "" && arg.Name != "" {
+			params[arg.Name] = deps[i]
+		}
+	}
+	fields := make(map[string]string)
+	if ctor.Body == nil || len(params) == 0 {
+		return fields
+	}
+
+	ast.Inspect(ctor.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CompositeLit:
+			// &impl{field: param} or &impl{param, ...}
+			if !isStructType(node.Type, service.Struct.Name) {
+				return true
+			}
+			for i, elt := range node.Elts {
+				if kv, isKV := elt.(*ast.KeyValueExpr); isKV {
+					if key, isIdent := kv.Key.(*ast.Ident); isIdent {
+						if dep, isDep := paramValue(kv.Value, params); isDep {
+							fields[key.Name] = dep
+						}
+					}
+				} else if i < len(service.Struct.FieldsList) {
+					if dep, isDep := paramValue(elt, params); isDep {
+						fields[service.Struct.FieldsList[i].Name] = dep
+					}
+				}
+			}
+		case *ast.AssignStmt:
+			// s.field = param
+			if len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, lhs := range node.Lhs {
+				if sel, isSel := lhs.(*ast.SelectorExpr); isSel {
+					if dep, isDep := paramValue(node.Rhs[i], params); isDep {
+						fields[sel.Sel.Name] = dep
+					}
+				}
+			}
+		}
+		return true
+	})
+	return fields
+}
+
+func isStructType(expr ast.Expr, name string) bool {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name == name
+	case *ast.SelectorExpr:
+		return t.Sel.Name == name
+	}
+	return false
+}
+
+// If expr is one of the constructor's dependency parameters, returns the dependency's name
+func paramValue(expr ast.Expr, params map[string]string) (string, bool) {
+	if id, isIdent := expr.(*ast.Ident); isIdent {
+		dep, isDep := params[id.Name]
+		return dep, isDep
+	}
+	return "", false
+}
+
+func receiverName(method *goparser.ParsedFunc) string {
+	if method.Receiver == nil || len(method.Receiver.List) == 0 || len(method.Receiver.List[0].Names) == 0 {
+		return ""
+	}
+	return method.Receiver.List[0].Names[0].Name
+}
+
+func position(method *goparser.ParsedFunc, node ast.Node) string {
+	mod := method.File.Package.Module
+	if mod.Fset == nil {
+		return method.File.Name
+	}
+	pos := mod.Fset.Position(node.Pos())
+	if rel, err := filepath.Rel(mod.SrcDir, pos.Filename); err == nil {
+		pos.Filename = filepath.ToSlash(rel)
+	}
+	return fmt.Sprintf("%s:%d", pos.Filename, pos.Line)
+}
@@ -0,0 +1,56 @@
+package callgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"golang.org/x/exp/slog"
+)
+
+// An analysis pass that extracts the call graph of the application's workflow services.
+// After the pass runs, the call graph is available in Graph.
+type CallGraphPass struct {
+	OutputFile string // If not empty, the call graph is written to this file as JSON
+	Graph      *CallGraph
+}
+
+// Returns a pass that extracts the call graph and, if outputFile is not empty, writes it to outputFile as JSON.
+func NewCallGraphPass(outputFile string) *CallGraphPass {
+	return &CallGraphPass{OutputFile: outputFile}
+}
+
+// Implements analysis.IRAnalysisPass
+func (p *CallGraphPass) Analyze(spec wiring.WiringSpec, app *ir.ApplicationNode) (bool, error) {
+	p.Graph = Extract(app)
+	slog.Info(fmt.Sprintf("[%v] Found %v calls between %v workflow service methods", p.Name(), len(p.Graph.Calls), len(p.Graph.Methods)))
+	for _, call := range p.Graph.Calls {
+		slog.Info(fmt.Sprintf("[%v] %v", p.Name(), call))
+	}
+
+	if p.OutputFile != "" {
+		data, err := json.MarshalIndent(p.Graph, "", "  ")
+		if err != nil {
+			return false, err
+		}
+		if err := os.MkdirAll(filepath.Dir(p.OutputFile), 0755); err != nil {
+			return false, blueprint.Errorf("unable to create directory for %v due to %v", p.OutputFile, err.Error())
+		}
+		if err := os.WriteFile(p.OutputFile, data, 0644); err != nil {
+			return false, blueprint.Errorf("unable to write call graph to %v due to %v", p.OutputFile, err.Error())
+		}
+	}
+	return false, nil
+}
+
+// Implements analysis.IRAnalysisPass
+func (p *CallGraphPass) Name() string {
+	return "CallGraphPass"
+}
+
+// Implements analysis.IRAnalysisPass
+func (p *CallGraphPass) ImplementsAnalysisPass() {}
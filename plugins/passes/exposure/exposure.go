@@ -0,0 +1,250 @@
+// Package exposure provides an analysis pass that flags parts of an application that are exposed
+// without protection, so that a deployment can be reviewed before it is published.
+//
+// Two kinds of exposure are reported:
+//   - A server whose address is reachable application-wide and bound to all interfaces, with no
+//     protection wrapper between the server and the service it serves.  Whether a server is protected
+//     is determined from the server-side modifiers of the service's pointer; the node types that count
+//     as protection are configured on the pass.
+//   - A backend, such as a database, that is used by more services than the configured limit.
+package exposure
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/address"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/irgraph"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/pointer"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/passes/topology"
+)
+
+const (
+	UnprotectedServer = "unprotected-server" // A server reachable from outside its deployment, with no protection wrapper
+	SharedBackend     = "shared-backend"     // A backend used by more services than the configured limit
+)
+
+// Something that should be reviewed before the application is deployed
+type Finding struct {
+	Kind       string   `json:"kind"`                 // UnprotectedServer or SharedBackend
+	Subject    string   `json:"subject"`              // The server or backend
+	Address    string   `json:"address,omitempty"`    // The address that the server binds to
+	Deployment string   `json:"deployment,omitempty"` // The outermost namespace containing the server
+	DialedFrom []string `json:"dialed_from,omitempty"`
+	Wrappers   []string `json:"wrappers,omitempty"` // The server-side modifiers between the server and the service, none of which are protection
+	UsedBy     []string `json:"used_by,omitempty"`
+	Message    string   `json:"message"`
+}
+
+// The findings of the exposure analysis
+type Report struct {
+	Findings []Finding `json:"findings"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s", f.Kind, f.Message)
+}
+
+// Returns a human-readable report
+func (r *Report) String() string {
+	if len(r.Findings) == 0 {
+		return "no exposures found"
+	}
+	var b strings.Builder
+	for _, f := range r.Findings {
+		fmt.Fprintf(&b, "%s %s\n", f.Kind, f.Subject)
+		fmt.Fprintf(&b, "  %s\n", f.Message)
+		if f.Address != "" {
+			fmt.Fprintf(&b, "  address:     %s\n", f.Address)
+		}
+		if f.Deployment != "" {
+			fmt.Fprintf(&b, "  deployment:  %s\n", f.Deployment)
+		}
+		if len(f.DialedFrom) > 0 {
+			fmt.Fprintf(&b, "  dialed from: %s\n", strings.Join(f.DialedFrom, ", "))
+		}
+		if len(f.Wrappers) > 0 {
+			fmt.Fprintf(&b, "  wrappers:    %s\n", strings.Join(f.Wrappers, ", "))
+		}
+		if len(f.UsedBy) > 0 {
+			fmt.Fprintf(&b, "  used by:     %s\n", strings.Join(f.UsedBy, ", "))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Analyzes app for exposures.  protections are example nodes of the node types that protect a server,
+// in the same form as the node types passed to [wiring.WiringSpec.Define].  A backend is reported if
+// it is used by more than maxBackendUsers services.
+func Analyze(spec wiring.WiringSpec, app *ir.ApplicationNode, protections []any, maxBackendUsers int) *Report {
+	report := &Report{}
+	report.Findings = append(report.Findings, unprotectedServers(spec, app, protections)...)
+	report.Findings = append(report.Findings, sharedBackends(app, maxBackendUsers)...)
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Subject < b.Subject
+	})
+	return report
+}
+
+func unprotectedServers(spec wiring.WiringSpec, app *ir.ApplicationNode, protections []any) []Finding {
+	graph := irgraph.FromIR(app)
+	isProtection := make(map[reflect.Type]struct{})
+	for _, p := range protections {
+		isProtection[reflect.TypeOf(p)] = struct{}{}
+	}
+
+	var findings []Finding
+	binds, _, _ := address.Split(app.GetAllIRNodes())
+	seen := make(map[string]struct{})
+	for _, bind := range binds {
+		if _, exists := seen[bind.AddressName]; exists {
+			continue
+		}
+		seen[bind.AddressName] = struct{}{}
+
+		addr := address.GetAddress(spec, bind.AddressName)
+		if addr == nil || !reachableApplicationWide(spec, bind.AddressName) || !allInterfaces(bind.Hostname) {
+			continue
+		}
+
+		// The server-side modifiers inside the server are the ones that could protect it
+		wrappers, protected := serverWrappers(spec, addr.PointsTo, isProtection)
+		if protected {
+			continue
+		}
+
+		f := Finding{
+			Kind:    UnprotectedServer,
+			Subject: addr.PointsTo,
+			Address: bind.Name(),
+		}
+		servers := referencingNodes(graph, bind)
+		dialers := make(map[string]struct{})
+		for _, dial := range dialsOf(app, bind.AddressName) {
+			for _, node := range referencingNodes(graph, dial) {
+				dialers[deployment(node)] = struct{}{}
+			}
+		}
+		if len(servers) > 0 {
+			f.Deployment = deployment(servers[0])
+			delete(dialers, f.Deployment)
+		}
+		for dialer := range dialers {
+			f.DialedFrom = append(f.DialedFrom, dialer)
+		}
+		sort.Strings(f.DialedFrom)
+		f.Wrappers = wrappers
+
+		bound := bind.Hostname
+		if bound == "" {
+			bound = "0.0.0.0"
+		}
+		f.Message = fmt.Sprintf("%s is bound to %s, is reachable application-wide, and has no protection wrapper", addr.PointsTo, bound)
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// Returns the server-side modifiers that wrap the service served by server, and whether
+// any of them is a protection.  Only modifiers of pointers whose chain contains server are
+// considered.
+func serverWrappers(spec wiring.WiringSpec, server string, isProtection map[reflect.Type]struct{}) (wrappers []string, protected bool) {
+	for _, name := range pointer.GetAllPointers(spec) {
+		ptr := pointer.GetPointer(spec, name)
+		modifiers := ptr.DstModifiers()
+		// The last modifier is the pointer's destination, which may also be the server
+		for i, modifier := range modifiers[:len(modifiers)-1] {
+			if modifier != server {
+				continue
+			}
+			// Modifiers after the server are between the server and the pointer's destination
+			for _, inner := range modifiers[i+1 : len(modifiers)-1] {
+				wrappers = append(wrappers, inner)
+				if def := spec.GetDef(inner); def != nil {
+					if _, isProt := isProtection[reflect.TypeOf(def.NodeType)]; isProt {
+						protected = true
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+// Addresses are reachable application-wide unless the plugin that defined them restricted their reachability
+func reachableApplicationWide(spec wiring.WiringSpec, addressName string) bool {
+	def := spec.GetDef(addressName)
+	if def == nil {
+		return true
+	}
+	_, isApp := def.NodeType.(*ir.ApplicationNode)
+	return isApp || def.NodeType == nil
+}
+
+// Deployers bind servers to all interfaces unless a hostname has been set
+func allInterfaces(hostname string) bool {
+	return hostname == "" || hostname == "0.0.0.0" || hostname == "::"
+}
+
+func dialsOf(app *ir.ApplicationNode, addressName string) []*address.DialConfig {
+	_, dials, _ := address.Split(app.GetAllIRNodes())
+	var matching []*address.DialConfig
+	for _, dial := range dials {
+		if dial.AddressName == addressName {
+			matching = append(matching, dial)
+		}
+	}
+	return matching
+}
+
+// Returns the non-namespace, non-metadata nodes in the graph that refer to target
+func referencingNodes(graph *irgraph.Graph, target ir.IRNode) []*irgraph.Node {
+	var targetIDs []string
+	for _, node := range graph.Nodes {
+		if node.IRNode() == target {
+			targetIDs = append(targetIDs, node.ID)
+		}
+	}
+	var nodes []*irgraph.Node
+	for _, edge := range graph.Edges {
+		for _, id := range targetIDs {
+			if edge.To != id {
+				continue
+			}
+			if from := graph.Node(edge.From); from != nil && from.Kind != irgraph.KindNamespace && from.Kind != irgraph.KindMetadata {
+				nodes = append(nodes, from)
+			}
+		}
+	}
+	return nodes
+}
+
+// The outermost namespace containing node, or the node itself if it is at the application level
+func deployment(node *irgraph.Node) string {
+	return strings.SplitN(node.ID, "/", 2)[0]
+}
+
+func sharedBackends(app *ir.ApplicationNode, maxBackendUsers int) []Finding {
+	var findings []Finding
+	for _, backend := range topology.Extract(app).Backends {
+		if len(backend.UsedBy) <= maxBackendUsers {
+			continue
+		}
+		findings = append(findings, Finding{
+			Kind:       SharedBackend,
+			Subject:    backend.Name,
+			Deployment: backend.Deployment,
+			UsedBy:     backend.UsedBy,
+			Message:    fmt.Sprintf("%s %s is shared by %v services", backend.Kind, backend.Name, len(backend.UsedBy)),
+		})
+	}
+	return findings
+}
@@ -0,0 +1,90 @@
+package exposure
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"golang.org/x/exp/slog"
+)
+
+// An analysis pass that reports servers and backends that are exposed without protection.
+// After the pass runs, the report is available in Report.
+type ExposurePass struct {
+	Protections     []any  // Example nodes of the node types that protect a server, e.g. an authentication wrapper
+	MaxBackendUsers int    // Backends used by more services than this are reported
+	Strict          bool   // If true, the pass returns an error if anything is reported
+	TextFile        string // If not empty, the human-readable report is written to this file
+	JSONFile        string // If not empty, the report is written to this file as JSON
+	Report          *Report
+}
+
+// Returns a pass that reports exposures and writes the report to textFile and jsonFile.  Either file can
+// be empty, in which case it is not written.  protections are example nodes of the server-side modifiers
+// that protect a server, in the same form as the node types passed to [wiring.WiringSpec.Define].
+//
+// By default, backends used by more than one service are reported.
+func NewExposurePass(textFile string, jsonFile string, protections ...any) *ExposurePass {
+	return &ExposurePass{
+		Protections:     protections,
+		MaxBackendUsers: 1,
+		TextFile:        textFile,
+		JSONFile:        jsonFile,
+	}
+}
+
+// Implements analysis.IRAnalysisPass
+func (p *ExposurePass) Analyze(spec wiring.WiringSpec, app *ir.ApplicationNode) (bool, error) {
+	p.Report = Analyze(spec, app, p.Protections, p.MaxBackendUsers)
+	slog.Info(fmt.Sprintf("[%v] Found %v exposures", p.Name(), len(p.Report.Findings)))
+	for _, f := range p.Report.Findings {
+		slog.Warn(fmt.Sprintf("[%v] %v", p.Name(), f))
+	}
+
+	if p.TextFile != "" {
+		if err := writeFile(p.TextFile, []byte(p.Report.String()+"\n")); err != nil {
+			return false, err
+		}
+	}
+	if p.JSONFile != "" {
+		data, err := json.MarshalIndent(p.Report, "", "  ")
+		if err != nil {
+			return false, err
+		}
+		if err := writeFile(p.JSONFile, data); err != nil {
+			return false, err
+		}
+	}
+
+	if p.Strict && len(p.Report.Findings) > 0 {
+		var subjects []string
+		for _, f := range p.Report.Findings {
+			subjects = append(subjects, f.Subject)
+		}
+		return false, blueprint.Errorf("found %v exposures: %v", len(subjects), strings.Join(subjects, ", "))
+	}
+	return false, nil
+}
+
+func writeFile(filename string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return blueprint.Errorf("unable to create directory for %v due to %v", filename, err.Error())
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return blueprint.Errorf("unable to write exposure report to %v due to %v", filename, err.Error())
+	}
+	return nil
+}
+
+// Implements analysis.IRAnalysisPass
+func (p *ExposurePass) Name() string {
+	return "ExposurePass"
+}
+
+// Implements analysis.IRAnalysisPass
+func (p *ExposurePass) ImplementsAnalysisPass() {}
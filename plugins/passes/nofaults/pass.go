@@ -0,0 +1,55 @@
+// Package nofaults provides a transform pass that strips fault injectors from an application,
+// e.g. to produce a production build from a wiring spec that is also used for fault injection experiments.
+package nofaults
+
+import (
+	"log"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/analysis"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/faultinjector/delay"
+	"github.com/blueprint-uservices/blueprint/plugins/faultinjector/probabilistic"
+	"github.com/blueprint-uservices/blueprint/plugins/golang"
+)
+
+type StripFaultInjectorsPass struct {
+}
+
+func NewStripFaultInjectorsPass() analysis.IRTransformPass {
+	return &StripFaultInjectorsPass{}
+}
+
+// Implements analysis.IRTransformPass
+func (p *StripFaultInjectorsPass) Transform(spec wiring.WiringSpec, app *ir.ApplicationNode) error {
+	for _, node := range app.GetAllIRNodes() {
+		var wrapped golang.Service
+		switch injector := node.(type) {
+		case *probabilistic.ServerWrapper:
+			wrapped = injector.Wrapped
+		case *delay.RandomDelayServerWrapper:
+			wrapped = injector.Wrapped
+		default:
+			continue
+		}
+		replaced, err := analysis.ReplaceNode(app, node, wrapped)
+		if err != nil {
+			return err
+		}
+		log.Printf("[%v] Removed fault injector %v from %v references", p.Name(), node.Name(), replaced)
+	}
+	return nil
+}
+
+// Implements analysis.IRTransformPass
+func (p *StripFaultInjectorsPass) Dependencies() analysis.PassDependencies {
+	return analysis.PassDependencies{}
+}
+
+// Implements analysis.IRTransformPass
+func (p *StripFaultInjectorsPass) Name() string {
+	return "StripFaultInjectorsPass"
+}
+
+// Implements analysis.IRTransformPass
+func (p *StripFaultInjectorsPass) ImplementsTransformPass() {}
@@ -0,0 +1,67 @@
+package topology
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"golang.org/x/exp/slog"
+)
+
+// An analysis pass that reports the deployment topology of the application.
+// After the pass runs, the report is available in Report.
+type TopologyPass struct {
+	TextFile string // If not empty, the human-readable report is written to this file
+	JSONFile string // If not empty, the report is written to this file as JSON
+	Report   *Report
+}
+
+// Returns a pass that reports the deployment topology and writes the report to textFile and jsonFile.
+// Either file can be empty, in which case it is not written.
+func NewTopologyPass(textFile string, jsonFile string) *TopologyPass {
+	return &TopologyPass{TextFile: textFile, JSONFile: jsonFile}
+}
+
+// Implements analysis.IRAnalysisPass
+func (p *TopologyPass) Analyze(spec wiring.WiringSpec, app *ir.ApplicationNode) (bool, error) {
+	p.Report = Extract(app)
+	slog.Info(fmt.Sprintf("[%v] Found %v containers, %v uncontained processes, and %v backends", p.Name(), len(p.Report.Containers), len(p.Report.Processes), len(p.Report.Backends)))
+
+	if p.TextFile != "" {
+		if err := writeFile(p.TextFile, []byte(p.Report.String()+"\n")); err != nil {
+			return false, err
+		}
+	}
+	if p.JSONFile != "" {
+		data, err := json.MarshalIndent(p.Report, "", "  ")
+		if err != nil {
+			return false, err
+		}
+		if err := writeFile(p.JSONFile, data); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+func writeFile(filename string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return blueprint.Errorf("unable to create directory for %v due to %v", filename, err.Error())
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return blueprint.Errorf("unable to write topology report to %v due to %v", filename, err.Error())
+	}
+	return nil
+}
+
+// Implements analysis.IRAnalysisPass
+func (p *TopologyPass) Name() string {
+	return "TopologyPass"
+}
+
+// Implements analysis.IRAnalysisPass
+func (p *TopologyPass) ImplementsAnalysisPass() {}
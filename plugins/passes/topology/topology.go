@@ -0,0 +1,296 @@
+// Package topology provides an analysis pass that reports the deployment topology of an application:
+// which containers hold which processes, which services each process hosts, which ports the containers'
+// servers bind to, and which backends each service uses.
+//
+// Ports are normally allocated during artifact generation, when a container deployer such as
+// docker-compose calls [address.AssignPorts] on the bind addresses of each container.  The topology
+// report performs the same allocation on copies of the bind addresses, so it reports the ports that
+// will be allocated without modifying the IR.
+package topology
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/address"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+	"github.com/blueprint-uservices/blueprint/plugins/linux"
+	"github.com/blueprint-uservices/blueprint/plugins/linuxcontainer"
+	"github.com/blueprint-uservices/blueprint/plugins/mongodb"
+	"github.com/blueprint-uservices/blueprint/plugins/mysql"
+	"github.com/blueprint-uservices/blueprint/plugins/redis"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+)
+
+// The deployment topology of an application
+type Report struct {
+	Containers []Container `json:"containers"`
+	Processes  []Process   `json:"processes,omitempty"` // Processes that aren't deployed in a container
+	Backends   []Backend   `json:"backends"`
+}
+
+// A linux container and the processes it runs
+type Container struct {
+	Name       string    `json:"name"`
+	Deployment string    `json:"deployment,omitempty"` // The namespace that the container is deployed in, e.g. a docker-compose app
+	Processes  []Process `json:"processes"`
+	Ports      []Port    `json:"ports"`
+}
+
+// A process and the workflow services it hosts
+type Process struct {
+	Name     string    `json:"name"`
+	Services []Service `json:"services"`
+}
+
+// A workflow service and the backends it uses
+type Service struct {
+	Name     string   `json:"name"`
+	Backends []string `json:"backends,omitempty"`
+}
+
+// A port that a server binds to
+type Port struct {
+	Address string `json:"address"` // The name of the bind address
+	Port    uint16 `json:"port"`
+	Fixed   bool   `json:"fixed,omitempty"` // True if the port was set by the wiring spec or a plugin rather than allocated by address.AssignPorts
+}
+
+// A database or cache backend, and the services that use it
+type Backend struct {
+	Name       string   `json:"name"`
+	Kind       string   `json:"kind"` // mongodb, mysql or redis
+	Deployment string   `json:"deployment,omitempty"`
+	Port       *Port    `json:"port,omitempty"`
+	UsedBy     []string `json:"used_by"`
+}
+
+// Extracts the deployment topology of app
+func Extract(app *ir.ApplicationNode) *Report {
+	t := &extractor{
+		backends: make(map[string]*Backend),
+		users:    make(map[string]map[string]struct{}),
+	}
+	t.visit(app.Children, "")
+
+	// Services that aren't deployed in a process still use backends
+	for _, handler := range workflow.FilterWorkflowNodes(app.GetAllIRNodes()) {
+		t.service(handler.InstanceName, handler.Args)
+	}
+
+	// Services refer to backends by address until every backend's container has been found
+	report := &Report{Containers: t.containers, Processes: t.processes}
+	for i := range report.Containers {
+		t.nameBackends(report.Containers[i].Processes)
+	}
+	t.nameBackends(report.Processes)
+	for _, backend := range t.backends {
+		sort.Strings(backend.UsedBy)
+		report.Backends = append(report.Backends, *backend)
+	}
+	sort.Slice(report.Containers, func(i, j int) bool { return report.Containers[i].Name < report.Containers[j].Name })
+	sort.Slice(report.Processes, func(i, j int) bool { return report.Processes[i].Name < report.Processes[j].Name })
+	sort.Slice(report.Backends, func(i, j int) bool { return report.Backends[i].Name < report.Backends[j].Name })
+	return report
+}
+
+// Returns a human-readable report
+func (r *Report) String() string {
+	var b strings.Builder
+	for _, ctr := range r.Containers {
+		fmt.Fprintf(&b, "container %s", ctr.Name)
+		if ctr.Deployment != "" {
+			fmt.Fprintf(&b, " (deployed in %s)", ctr.Deployment)
+		}
+		b.WriteString("\n")
+		for _, port := range ctr.Ports {
+			fmt.Fprintf(&b, "  port %s\n", port)
+		}
+		for _, proc := range ctr.Processes {
+			writeProcess(&b, proc, "  ")
+		}
+	}
+	for _, proc := range r.Processes {
+		writeProcess(&b, proc, "")
+	}
+	for _, backend := range r.Backends {
+		fmt.Fprintf(&b, "backend %s (%s)", backend.Name, backend.Kind)
+		if backend.Deployment != "" {
+			fmt.Fprintf(&b, " (deployed in %s)", backend.Deployment)
+		}
+		b.WriteString("\n")
+		if backend.Port != nil {
+			fmt.Fprintf(&b, "  port %s\n", backend.Port)
+		}
+		if len(backend.UsedBy) > 0 {
+			fmt.Fprintf(&b, "  used by %s\n", strings.Join(backend.UsedBy, ", "))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func writeProcess(b *strings.Builder, proc Process, indent string) {
+	fmt.Fprintf(b, "%sprocess %s\n", indent, proc.Name)
+	for _, service := range proc.Services {
+		fmt.Fprintf(b, "%s  service %s", indent, service.Name)
+		if len(service.Backends) > 0 {
+			fmt.Fprintf(b, " uses %s", strings.Join(service.Backends, ", "))
+		}
+		b.WriteString("\n")
+	}
+}
+
+func (p Port) String() string {
+	if p.Fixed {
+		return fmt.Sprintf("%d %s (fixed)", p.Port, p.Address)
+	}
+	return fmt.Sprintf("%d %s", p.Port, p.Address)
+}
+
+type extractor struct {
+	containers []Container
+	processes  []Process
+	backends   map[string]*Backend            // Keyed by the backend's address name
+	users      map[string]map[string]struct{} // The services already recorded as using each backend
+}
+
+func (t *extractor) visit(nodes []ir.IRNode, deployment string) {
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case *linuxcontainer.Container:
+			t.containers = append(t.containers, t.container(n, deployment))
+		case linux.Process:
+			t.processes = append(t.processes, t.process(n))
+		default:
+			if kind, bind, port, isBackend := backendContainer(node); isBackend {
+				b := t.backend(bind.AddressName)
+				b.Name = node.Name()
+				b.Kind = kind
+				b.Deployment = deployment
+				b.Port = &Port{Address: bind.Name(), Port: bind.Port, Fixed: true}
+				if b.Port.Port == 0 {
+					b.Port.Port = port
+				}
+			} else if ns, isNamespace := node.(ir.HasIRChildren); isNamespace {
+				t.visit(ns.GetNodes(), node.Name())
+			}
+		}
+	}
+}
+
+func (t *extractor) container(ctr *linuxcontainer.Container, deployment string) Container {
+	c := Container{Name: ctr.Name(), Deployment: deployment, Processes: []Process{}, Ports: []Port{}}
+	for _, node := range ctr.Nodes {
+		if proc, isProcess := node.(linux.Process); isProcess {
+			c.Processes = append(c.Processes, t.process(proc))
+		}
+	}
+
+	// Allocate ports the same way container deployers do, on copies of the binds so that the IR isn't modified
+	binds, _, _ := address.Split(ctr.Edges)
+	copies := make([]*address.BindConfig, len(binds))
+	for i, bind := range binds {
+		copied := *bind
+		copies[i] = &copied
+	}
+	preassigned, _, err := address.AssignPorts(copies)
+	if err != nil {
+		// The preassigned ports collide; report them as they are and let artifact generation report the error
+		copies = binds
+	}
+	fixed := make(map[*address.BindConfig]struct{})
+	for _, bind := range preassigned {
+		fixed[bind] = struct{}{}
+	}
+	for _, bind := range copies {
+		_, isFixed := fixed[bind]
+		c.Ports = append(c.Ports, Port{Address: bind.Name(), Port: bind.Port, Fixed: isFixed})
+	}
+	sort.Slice(c.Ports, func(i, j int) bool { return c.Ports[i].Port < c.Ports[j].Port })
+	return c
+}
+
+func (t *extractor) process(proc linux.Process) Process {
+	p := Process{Name: proc.Name(), Services: []Service{}}
+	ns, hasChildren := proc.(ir.HasIRChildren)
+	if !hasChildren {
+		return p
+	}
+	for _, handler := range workflow.FilterWorkflowNodes(ns.GetNodes()) {
+		p.Services = append(p.Services, t.service(handler.InstanceName, handler.Args))
+	}
+	sort.Slice(p.Services, func(i, j int) bool { return p.Services[i].Name < p.Services[j].Name })
+	return p
+}
+
+// Records the backends used by a service, based on the clients that are passed to its constructor
+func (t *extractor) service(name string, args []ir.IRNode) Service {
+	s := Service{Name: name}
+	for _, arg := range args {
+		kind, dial, isBackend := backendClient(arg)
+		if !isBackend {
+			continue
+		}
+		b := t.backend(dial.AddressName)
+		if b.Name == "" {
+			// Named after the client until the backend's container is found
+			b.Name = arg.Name()
+			b.Kind = kind
+		}
+		if _, recorded := t.users[dial.AddressName][name]; !recorded {
+			t.users[dial.AddressName][name] = struct{}{}
+			b.UsedBy = append(b.UsedBy, name)
+		}
+		s.Backends = append(s.Backends, dial.AddressName)
+	}
+	return s
+}
+
+// Replaces the backend addresses recorded by [extractor.service] with the names of the backends
+func (t *extractor) nameBackends(processes []Process) {
+	for _, proc := range processes {
+		for _, service := range proc.Services {
+			for i, addr := range service.Backends {
+				service.Backends[i] = t.backends[addr].Name
+			}
+		}
+	}
+}
+
+func (t *extractor) backend(addressName string) *Backend {
+	b, exists := t.backends[addressName]
+	if !exists {
+		b = &Backend{UsedBy: []string{}}
+		t.backends[addressName] = b
+		t.users[addressName] = make(map[string]struct{})
+	}
+	return b
+}
+
+// If node is the container of a supported backend, returns the kind of backend, its bind address,
+// and the port that the backend's plugin binds it to.
+func backendContainer(node ir.IRNode) (kind string, bind *address.BindConfig, port uint16, isBackend bool) {
+	switch n := node.(type) {
+	case *mongodb.MongoDBContainer:
+		return "mongodb", n.BindAddr, 27017, n.BindAddr != nil
+	case *mysql.MySQLDBContainer:
+		return "mysql", n.BindAddr, 3306, n.BindAddr != nil
+	case *redis.RedisContainer:
+		return "redis", n.BindAddr, 6379, n.BindAddr != nil
+	}
+	return "", nil, 0, false
+}
+
+// If node is the client of a supported backend, returns the kind of backend and the address the client dials
+func backendClient(node ir.IRNode) (kind string, dial *address.DialConfig, isBackend bool) {
+	switch n := node.(type) {
+	case *mongodb.MongoDBGoClient:
+		return "mongodb", n.Addr, n.Addr != nil
+	case *mysql.MySQLDBGoClient:
+		return "mysql", n.Addr, n.Addr != nil
+	case *redis.RedisGoClient:
+		return "redis", n.Addr, n.Addr != nil
+	}
+	return "", nil, false
+}
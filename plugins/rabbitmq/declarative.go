@@ -0,0 +1,21 @@
+package rabbitmq
+
+import (
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("rabbitmq.Container", func(spec wiring.WiringSpec, step declarative.Step) ([]string, error) {
+		if err := step.CheckOptions("queue_name"); err != nil {
+			return nil, err
+		}
+		queue_name, err := step.GetString("queue_name")
+		if err != nil {
+			return nil, err
+		}
+		return declarative.Define(func(spec wiring.WiringSpec, name string) string {
+			return Container(spec, name, queue_name)
+		})(spec, step)
+	})
+}
@@ -13,6 +13,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*RabbitmqGoClient]()
+}
+
 // Blueprint IR Node that represents the generated client for the rabbitmq container
 type RabbitmqGoClient struct {
 	golang.Service
@@ -11,6 +11,11 @@ import (
 	"github.com/blueprint-uservices/blueprint/runtime/plugins/rabbitmq"
 )
 
+func init() {
+	ir.RegisterJSONType[*RabbitmqContainer]()
+	ir.RegisterJSONType[*address.Address[*RabbitmqContainer]]()
+}
+
 // Blueprint IR Node that represents the server side docker container
 type RabbitmqContainer struct {
 	backend.Queue
@@ -0,0 +1,9 @@
+package redis
+
+import (
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("redis.Container", declarative.Define(Container))
+}
@@ -13,6 +13,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*RedisGoClient]()
+}
+
 // Blueprint IR Node that represents a client to a redis container
 type RedisGoClient struct {
 	golang.Service
@@ -11,6 +11,11 @@ import (
 	"github.com/blueprint-uservices/blueprint/runtime/plugins/redis"
 )
 
+func init() {
+	ir.RegisterJSONType[*RedisContainer]()
+	ir.RegisterJSONType[*address.Address[*RedisContainer]]()
+}
+
 // Blueprint IR Node that represents a redis container
 type RedisContainer struct {
 	backend.Cache
@@ -27,7 +27,7 @@ func generateClient(builder golang.ModuleBuilder, wrapped *gocode.ServiceInterfa
 
 	client.Imports.AddPackages("context")
 
-	return generateClientCommon(&client, clientTemplate)
+	return generateClientCommon(builder, &client, clientTemplate)
 }
 
 func generateExpBackoffClient(builder golang.ModuleBuilder, wrapped *gocode.ServiceInterface, outputPackage string, delay string, limit string, useJitter bool) error {
@@ -48,7 +48,7 @@ func generateExpBackoffClient(builder golang.ModuleBuilder, wrapped *gocode.Serv
 
 	client.Imports.AddPackages("context", "time")
 
-	return generateClientCommon(&client, clientExponentialBackoffTemplate)
+	return generateClientCommon(builder, &client, clientExponentialBackoffTemplate)
 }
 
 func generateFixedDelayClient(builder golang.ModuleBuilder, wrapped *gocode.ServiceInterface, outputPackage string, max_tries int64, delay string) error {
@@ -68,7 +68,7 @@ func generateFixedDelayClient(builder golang.ModuleBuilder, wrapped *gocode.Serv
 
 	client.Imports.AddPackages("context", "time")
 
-	return generateClientCommon(&client, clientFixedDelayTemplate)
+	return generateClientCommon(builder, &client, clientFixedDelayTemplate)
 }
 
 func generateRateLimiterClient(builder golang.ModuleBuilder, wrapped *gocode.ServiceInterface, outputPackage string, Max int64, RetryRateLimit int64) error {
@@ -88,7 +88,7 @@ func generateRateLimiterClient(builder golang.ModuleBuilder, wrapped *gocode.Ser
 
 	client.Imports.AddPackages("context", "time")
 
-	return generateClientCommon(&client, clientRateLimiterTemplate)
+	return generateClientCommon(builder, &client, clientRateLimiterTemplate)
 }
 
 func generateTokenBucketClient(builder golang.ModuleBuilder, wrapped *gocode.ServiceInterface, outputPackage string, capacity float64, retry_cost float64, replenish_amount float64) error {
@@ -107,12 +107,17 @@ func generateTokenBucketClient(builder golang.ModuleBuilder, wrapped *gocode.Ser
 		ReplenishAmt: replenish_amount,
 	}
 
-	client.Imports.AddPackages("context", "math")
+	client.Imports.AddPackages("context")
+	for _, f := range wrapped.Methods {
+		if !client.IsStreaming(f) {
+			client.Imports.AddPackages("math")
+		}
+	}
 
-	return generateClientCommon(&client, clientTokenBucketTemplate)
+	return generateClientCommon(builder, &client, clientTokenBucketTemplate)
 }
 
-func generateClientCommon(client *clientArgs, clientTemplate string) error {
+func generateClientCommon(builder golang.ModuleBuilder, client *clientArgs, clientTemplate *gogen.Template) error {
 	clientName := client.Name
 	slog.Info(fmt.Sprintf("Generating %v/%v", client.Package.PackageName, clientName))
 	outputFile := filepath.Join(client.Package.Path, clientName+".go")
@@ -121,7 +126,7 @@ func generateClientCommon(client *clientArgs, clientTemplate string) error {
 		client.Imports.AddPackages("math/rand")
 	}
 
-	return gogen.ExecuteTemplateToFile("Retries", clientTemplate, client, outputFile)
+	return clientTemplate.ExecuteToFile(builder, client, outputFile)
 }
 
 type clientArgs struct {
@@ -139,7 +144,20 @@ type clientArgs struct {
 	Imports        *gogen.Imports
 }
 
-var clientTemplate = `// Blueprint: Auto-generated by Retries Plugin
+// Reports whether f has channel arguments or retvals.  The streams of such methods outlive the call, and
+// a channel argument is consumed by the first attempt, so such methods cannot be retried.
+func (client *clientArgs) IsStreaming(f gocode.Func) bool {
+	for _, vars := range [][]gocode.Variable{f.Arguments, f.Returns} {
+		for _, v := range vars {
+			if _, isChan := gocode.ChanElem(v.Type); isChan {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var clientTemplate = gogen.RegisterTemplate("retries", "client", `// Blueprint: Auto-generated by Retries Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -160,6 +178,10 @@ func New_{{.Name}} (ctx context.Context, client {{.Imports.NameOf .Service.UserT
 {{$receiver := .Name -}}
 {{ range $_, $f := .Service.Methods }}
 func (client *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.Context"}}) ({{RetVarsAndTypes $f "err error"}}) {
+{{- if $.IsStreaming $f}}
+	// Not retried, because the streams outlive the call and a retry can't replay them
+	return client.Client.{{$f.Name}}({{ArgVars $f "ctx"}})
+{{- else}}
 	for i := 0; i < client.MaxTries; i++ {
 		ctx = context.WithValue(ctx, "attempt_num", i+1)
 		{{RetVars $f "err"}} = client.Client.{{$f.Name}}({{ArgVars $f "ctx"}})
@@ -168,11 +190,12 @@ func (client *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.Co
 		}
 	}
 	return
+{{- end}}
 }
 {{end}}
-`
+`)
 
-var clientFixedDelayTemplate = `// Blueprint: Auto-generated by Retries Plugin
+var clientFixedDelayTemplate = gogen.RegisterTemplate("retries", "clientFixedDelay", `// Blueprint: Auto-generated by Retries Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -200,6 +223,10 @@ func New_{{.Name}} (ctx context.Context, client {{.Imports.NameOf .Service.UserT
 {{$receiver := .Name -}}
 {{ range $_, $f := .Service.Methods }}
 func (client *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.Context"}}) ({{RetVarsAndTypes $f "err error"}}) {
+{{- if $.IsStreaming $f}}
+	// Not retried, because the streams outlive the call and a retry can't replay them
+	return client.Client.{{$f.Name}}({{ArgVars $f "ctx"}})
+{{- else}}
 	for i := 0; i < client.MaxTries; i++ {
 		ctx = context.WithValue(ctx, "attempt_num", i+1)
 		{{RetVars $f "err"}} = client.Client.{{$f.Name}}({{ArgVars $f "ctx"}})
@@ -209,11 +236,12 @@ func (client *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.Co
 		time.Sleep(client.Delay)
 	}
 	return
+{{- end}}
 }
 {{end}}
-`
+`)
 
-var clientExponentialBackoffTemplate = `// Blueprint: Auto-generated by Retries Plugin
+var clientExponentialBackoffTemplate = gogen.RegisterTemplate("retries", "clientExponentialBackoff", `// Blueprint: Auto-generated by Retries Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -259,6 +287,10 @@ func (client *{{.Name}}) addJitter(d time.Duration) time.Duration {
 {{$useJitter := .UseJitter -}}
 {{ range $_, $f := .Service.Methods }}
 func (client *{{$receiver}}) {{$f.Name}}({{ArgVarsAndTypes $f "ctx context.Context"}}) ({{RetVarsAndTypes $f "err error"}}) {
+{{- if $.IsStreaming $f}}
+	// Not retried, because the streams outlive the call and a retry can't replay them
+	return client.Client.{{$f.Name}}({{ArgVars $f "ctx"}})
+{{- else}}
 	delay := client.Delay
 	i := 1
 	for {
@@ -295,11 +327,12 @@ func (client *{{$receiver}}) {{$f.Name}}({{ArgVarsAndTypes $f "ctx context.Conte
 		}
 		i += 1
 	}
+{{- end}}
 }
 {{end}}
-`
+`)
 
-var clientRateLimiterTemplate = `// Blueprint: Auto-generated by Retries Plugin
+var clientRateLimiterTemplate = gogen.RegisterTemplate("retries", "clientRateLimiter", `// Blueprint: Auto-generated by Retries Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -368,6 +401,10 @@ func (client *{{.Name}}) acquireToken() bool {
 {{$receiver := .Name -}}
 {{ range $_, $f := .Service.Methods }}
 func (client *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.Context"}}) ({{RetVarsAndTypes $f "err error"}}) {
+{{- if $.IsStreaming $f}}
+	// Not retried, because the streams outlive the call and a retry can't replay them
+	return client.Client.{{$f.Name}}({{ArgVars $f "ctx"}})
+{{- else}}
 	// First attempt - no rate limiting
 	ctx = context.WithValue(ctx, "attempt_num", 1)
 	{{RetVars $f "err"}} = client.Client.{{$f.Name}}({{ArgVars $f "ctx"}})
@@ -392,11 +429,12 @@ func (client *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.Co
 	}
 
 	return
+{{- end}}
 }
 {{end}}
-`
+`)
 
-var clientTokenBucketTemplate = `// Blueprint: Auto-generated by Retries Plugin
+var clientTokenBucketTemplate = gogen.RegisterTemplate("retries", "clientTokenBucket", `// Blueprint: Auto-generated by Retries Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -418,6 +456,10 @@ func New_{{.Name}} (ctx context.Context, client {{.Imports.NameOf .Service.UserT
 {{$node := . -}}
 {{ range $_, $f := .Service.Methods }}
 func (client *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.Context"}}) ({{RetVarsAndTypes $f "err error"}}) {
+{{- if $.IsStreaming $f}}
+	// Not retried, because the streams outlive the call and a retry can't replay them
+	return client.Client.{{$f.Name}}({{ArgVars $f "ctx"}})
+{{- else}}
 	ctx = context.WithValue(ctx, "attempt_num", 1)
 	{{RetVars $f "err"}} = client.Client.{{$f.Name}}({{ArgVars $f "ctx"}})
 	if err != nil {
@@ -436,6 +478,7 @@ func (client *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.Co
 		}
 	}
 	return
+{{- end}}
 }
 {{end}}
-`
+`)
@@ -0,0 +1,21 @@
+package retries
+
+import (
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("retries.AddRetries", func(spec wiring.WiringSpec, step declarative.Step) ([]string, error) {
+		if err := step.CheckOptions("max_retries"); err != nil {
+			return nil, err
+		}
+		max_retries, err := step.GetInt("max_retries")
+		if err != nil {
+			return nil, err
+		}
+		return declarative.Apply(func(spec wiring.WiringSpec, serviceName string) {
+			AddRetries(spec, serviceName, max_retries)
+		})(spec, step)
+	})
+}
@@ -11,6 +11,14 @@ import (
 	"github.com/blueprint-uservices/blueprint/plugins/golang/gocode"
 )
 
+func init() {
+	ir.RegisterJSONType[*RetrierClient]()
+	ir.RegisterJSONType[*RetrierFixedDelayClient]()
+	ir.RegisterJSONType[*RetrierExponentialBackoffClient]()
+	ir.RegisterJSONType[*RetrierRateLimiterClient]()
+	ir.RegisterJSONType[*RetrierTokenBucketClient]()
+}
+
 // Blueprint IR node representing a Retrier
 type RetrierClient struct {
 	golang.Service
@@ -20,7 +28,7 @@ type RetrierClient struct {
 	InstanceName string
 	Wrapped      golang.Service
 
-	outputPackage string
+	OutputPackage string
 	Max           int64
 }
 
@@ -43,7 +51,7 @@ func newRetrierClient(name string, server ir.IRNode, max_clients int64) (*Retrie
 	node := &RetrierClient{}
 	node.InstanceName = name
 	node.Wrapped = serverNode
-	node.outputPackage = "retries"
+	node.OutputPackage = "retries"
 	node.Max = max_clients
 
 	return node, nil
@@ -67,7 +75,7 @@ func (node *RetrierClient) GenerateFuncs(builder golang.ModuleBuilder) error {
 		return err
 	}
 
-	return generateClient(builder, iface, node.outputPackage, node.Max)
+	return generateClient(builder, iface, node.OutputPackage, node.Max)
 }
 
 func (node *RetrierClient) AddInstantiation(builder golang.NamespaceBuilder) error {
@@ -81,7 +89,7 @@ func (node *RetrierClient) AddInstantiation(builder golang.NamespaceBuilder) err
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_RetrierClient", iface.BaseName),
 			Arguments: []gocode.Variable{
@@ -103,7 +111,7 @@ type RetrierFixedDelayClient struct {
 	InstanceName string
 	Wrapped      golang.Service
 
-	outputPackage string
+	OutputPackage string
 	Max           int64
 	Delay         string
 }
@@ -127,7 +135,7 @@ func newRetrierFixedDelayClient(name string, server ir.IRNode, max_clients int64
 	node := &RetrierFixedDelayClient{}
 	node.InstanceName = name
 	node.Wrapped = serverNode
-	node.outputPackage = "retries"
+	node.OutputPackage = "retries"
 	node.Max = max_clients
 	node.Delay = delay
 
@@ -152,7 +160,7 @@ func (node *RetrierFixedDelayClient) GenerateFuncs(builder golang.ModuleBuilder)
 		return err
 	}
 
-	return generateFixedDelayClient(builder, iface, node.outputPackage, node.Max, node.Delay)
+	return generateFixedDelayClient(builder, iface, node.OutputPackage, node.Max, node.Delay)
 }
 
 func (node *RetrierFixedDelayClient) AddInstantiation(builder golang.NamespaceBuilder) error {
@@ -166,7 +174,7 @@ func (node *RetrierFixedDelayClient) AddInstantiation(builder golang.NamespaceBu
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_RetrierFixedDelayClient", iface.BaseName),
 			Arguments: []gocode.Variable{
@@ -188,7 +196,7 @@ type RetrierExponentialBackoffClient struct {
 	InstanceName string
 	Wrapped      golang.Service
 
-	outputPackage string
+	OutputPackage string
 	StartDelay    string
 	BackoffLimit  string
 
@@ -214,7 +222,7 @@ func newRetrierExponentialBackoffClient(name string, server ir.IRNode, delay str
 	node := &RetrierExponentialBackoffClient{}
 	node.InstanceName = name
 	node.Wrapped = serverNode
-	node.outputPackage = "retries"
+	node.OutputPackage = "retries"
 	node.StartDelay = delay
 	node.BackoffLimit = backoff_limit
 	node.UseJitter = use_jitter
@@ -239,7 +247,7 @@ func (node *RetrierExponentialBackoffClient) GenerateFuncs(builder golang.Module
 		return err
 	}
 
-	return generateExpBackoffClient(builder, iface, node.outputPackage, node.StartDelay, node.BackoffLimit, node.UseJitter)
+	return generateExpBackoffClient(builder, iface, node.OutputPackage, node.StartDelay, node.BackoffLimit, node.UseJitter)
 }
 
 func (node *RetrierExponentialBackoffClient) AddInstantiation(builder golang.NamespaceBuilder) error {
@@ -253,7 +261,7 @@ func (node *RetrierExponentialBackoffClient) AddInstantiation(builder golang.Nam
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_RetrierExpBackoffClient", iface.BaseName),
 			Arguments: []gocode.Variable{
@@ -275,7 +283,7 @@ type RetrierRateLimiterClient struct {
 	InstanceName string
 	Wrapped      golang.Service
 
-	outputPackage  string
+	OutputPackage  string
 	Max            int64
 	RetryRateLimit int64 // retried times per second
 }
@@ -299,7 +307,7 @@ func newRetrierRateLimiterClient(name string, server ir.IRNode, max_clients int6
 	node := &RetrierRateLimiterClient{}
 	node.InstanceName = name
 	node.Wrapped = serverNode
-	node.outputPackage = "retries"
+	node.OutputPackage = "retries"
 	node.Max = max_clients
 	node.RetryRateLimit = rateLimit
 
@@ -324,7 +332,7 @@ func (node *RetrierRateLimiterClient) GenerateFuncs(builder golang.ModuleBuilder
 		return err
 	}
 
-	return generateRateLimiterClient(builder, iface, node.outputPackage, node.Max, node.RetryRateLimit)
+	return generateRateLimiterClient(builder, iface, node.OutputPackage, node.Max, node.RetryRateLimit)
 }
 
 func (node *RetrierRateLimiterClient) AddInstantiation(builder golang.NamespaceBuilder) error {
@@ -338,7 +346,7 @@ func (node *RetrierRateLimiterClient) AddInstantiation(builder golang.NamespaceB
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_RetrierRateLimiterClient", iface.BaseName),
 			Arguments: []gocode.Variable{
@@ -360,7 +368,7 @@ type RetrierTokenBucketClient struct {
 	InstanceName string
 	Wrapped      golang.Service
 
-	outputPackage string
+	OutputPackage string
 	Capacity      float64
 	ReplenishAmt  float64
 	RetryCost     float64
@@ -385,7 +393,7 @@ func newRetrierTokenBucketClient(name string, server ir.IRNode, max_cap float64,
 	node := &RetrierTokenBucketClient{}
 	node.InstanceName = name
 	node.Wrapped = serverNode
-	node.outputPackage = "retries"
+	node.OutputPackage = "retries"
 	node.Capacity = max_cap
 	node.ReplenishAmt = replenish_amount
 	node.RetryCost = retry_cost
@@ -411,7 +419,7 @@ func (node *RetrierTokenBucketClient) GenerateFuncs(builder golang.ModuleBuilder
 		return err
 	}
 
-	return generateTokenBucketClient(builder, iface, node.outputPackage, node.Capacity, node.RetryCost, node.ReplenishAmt)
+	return generateTokenBucketClient(builder, iface, node.OutputPackage, node.Capacity, node.RetryCost, node.ReplenishAmt)
 }
 
 func (node *RetrierTokenBucketClient) AddInstantiation(builder golang.NamespaceBuilder) error {
@@ -425,7 +433,7 @@ func (node *RetrierTokenBucketClient) AddInstantiation(builder golang.NamespaceB
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_RetrierTokenBucketClient", iface.BaseName),
 			Arguments: []gocode.Variable{
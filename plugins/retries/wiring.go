@@ -3,6 +3,10 @@
 // The plugin wraps clients with a retrier using that retries a request until one of the two conditions is met:
 // i)  the requests returns without an error
 // ii) the number of failed tries has reached the maximum number of failures.
+//
+// Methods with channel arguments or return values are not retried, because their streams outlive the call
+// and are consumed by the first try.
+//
 // Usage:
 //
 //	import "github.com/blueprint-uservices/blueprint/plugins/retries"
@@ -23,7 +27,18 @@ import (
 	"golang.org/x/exp/slog"
 )
 
-var PROP_MAXRETRY = "Retry-Max"
+var PROP_MAXRETRY = "retries.Retry-Max"
+
+// The maximum number of retries of a retrier client, which cannot be negative
+var MaxRetriesProperty = wiring.DeclareProperty(wiring.Property[int64]{
+	Key: PROP_MAXRETRY,
+	Validate: func(max_retries int64) error {
+		if max_retries < 0 {
+			return blueprint.Errorf("the maximum number of retries cannot be negative")
+		}
+		return nil
+	},
+})
 var IRNODE_RETRIER_SUFFIX = ".client.retrier"
 var IRNODE_RETRIER_FIXED_DELAY_SUFFIX = ".client.retrierfd"
 var IRNODE_RETRIER_EXPONENTIAL_BACKOFF_SUFFIX = ".client.retriereb"
@@ -38,7 +53,7 @@ var IRNODE_RETRIER_TOKEN_BUCKET_SUFFIX = ".client.retriertb"
 //	AddRetries(spec, "my_service", 10)
 func AddRetries(spec wiring.WiringSpec, serviceName string, max_retries int64) {
 	clientWrapper := serviceName + IRNODE_RETRIER_SUFFIX
-	spec.AddProperty(clientWrapper, PROP_MAXRETRY, max_retries)
+	MaxRetriesProperty.Set(spec, clientWrapper, max_retries)
 
 	ptr := pointer.GetPointer(spec, serviceName)
 	if ptr == nil {
@@ -46,7 +61,7 @@ func AddRetries(spec wiring.WiringSpec, serviceName string, max_retries int64) {
 		return
 	}
 
-	clientNext := ptr.AddSrcModifier(spec, clientWrapper)
+	clientNext := ptr.AddSrcModifier(spec, clientWrapper, pointer.InProcessModifier)
 
 	spec.Define(clientWrapper, &RetrierClient{Max: max_retries}, func(ns wiring.Namespace) (ir.IRNode, error) {
 		var wrapped golang.Service
@@ -91,7 +106,7 @@ func AddRetriesWithTimeouts(spec wiring.WiringSpec, serviceName string, max_retr
 func AddRetriesWithFixedDelay(spec wiring.WiringSpec, serviceName string, max_retries int64, delay string) {
 	clientWrapper := serviceName + IRNODE_RETRIER_FIXED_DELAY_SUFFIX
 
-	spec.AddProperty(clientWrapper, PROP_MAXRETRY, max_retries)
+	MaxRetriesProperty.Set(spec, clientWrapper, max_retries)
 
 	ptr := pointer.GetPointer(spec, serviceName)
 	if ptr == nil {
@@ -99,7 +114,7 @@ func AddRetriesWithFixedDelay(spec wiring.WiringSpec, serviceName string, max_re
 		return
 	}
 
-	clientNext := ptr.AddSrcModifier(spec, clientWrapper)
+	clientNext := ptr.AddSrcModifier(spec, clientWrapper, pointer.InProcessModifier)
 
 	spec.Define(clientWrapper, &RetrierExponentialBackoffClient{}, func(ns wiring.Namespace) (ir.IRNode, error) {
 		var wrapped golang.Service
@@ -131,7 +146,7 @@ func AddRetriesWithExponentialBackoff(spec wiring.WiringSpec, serviceName string
 		return
 	}
 
-	clientNext := ptr.AddSrcModifier(spec, clientWrapper)
+	clientNext := ptr.AddSrcModifier(spec, clientWrapper, pointer.InProcessModifier)
 
 	spec.Define(clientWrapper, &RetrierExponentialBackoffClient{}, func(ns wiring.Namespace) (ir.IRNode, error) {
 		var wrapped golang.Service
@@ -154,7 +169,7 @@ func AddRetriesWithExponentialBackoff(spec wiring.WiringSpec, serviceName string
 func AddRetriesRetryRateLimit(spec wiring.WiringSpec, serviceName string, max_retries int64, retry_rate_limit int64) {
 	clientWrapper := serviceName + IRNODE_RETRIER_RATE_LIMITED_SUFFIX
 
-	spec.AddProperty(clientWrapper, PROP_MAXRETRY, max_retries)
+	MaxRetriesProperty.Set(spec, clientWrapper, max_retries)
 
 	ptr := pointer.GetPointer(spec, serviceName)
 	if ptr == nil {
@@ -162,7 +177,7 @@ func AddRetriesRetryRateLimit(spec wiring.WiringSpec, serviceName string, max_re
 		return
 	}
 
-	clientNext := ptr.AddSrcModifier(spec, clientWrapper)
+	clientNext := ptr.AddSrcModifier(spec, clientWrapper, pointer.InProcessModifier)
 
 	spec.Define(clientWrapper, &RetrierRateLimiterClient{Max: max_retries, RetryRateLimit: retry_rate_limit}, func(ns wiring.Namespace) (ir.IRNode, error) {
 		var wrapped golang.Service
@@ -193,7 +208,7 @@ func AddRetriesTokenBucket(spec wiring.WiringSpec, serviceName string, max_capac
 		return
 	}
 
-	clientNext := ptr.AddSrcModifier(spec, clientWrapper)
+	clientNext := ptr.AddSrcModifier(spec, clientWrapper, pointer.InProcessModifier)
 
 	spec.Define(clientWrapper, &RetrierTokenBucketClient{}, func(ns wiring.Namespace) (ir.IRNode, error) {
 		var wrapped golang.Service
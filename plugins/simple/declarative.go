@@ -0,0 +1,12 @@
+package simple
+
+import (
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("simple.NoSQLDB", declarative.Define(NoSQLDB))
+	declarative.Register("simple.RelationalDB", declarative.Define(RelationalDB))
+	declarative.Register("simple.Queue", declarative.Define(Queue))
+	declarative.Register("simple.Cache", declarative.Define(Cache))
+}
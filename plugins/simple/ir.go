@@ -11,6 +11,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*SimpleBackend]()
+}
+
 // The SimpleBackend IR node represents a service or backend implementation that is wholly
 // defined in Blueprint's runtime module.  Examples include SimpleCache, SimpleNoSQLDB, etc.
 //
@@ -0,0 +1,9 @@
+package thrift
+
+import (
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("thrift.Deploy", declarative.Apply(Deploy))
+}
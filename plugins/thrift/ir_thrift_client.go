@@ -13,6 +13,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*golangThriftClient]()
+}
+
 // IRNode representing a client to a Golang server.
 // This node does not introduce any new runtime interfaces or types that can be used by other IRNodes
 // Thrift code generation happens during the ModuleBuilder GeneratesFuncs pass
@@ -24,14 +28,14 @@ type golangThriftClient struct {
 
 	InstanceName  string
 	ServerAddr    *address.Address[*golangThriftServer]
-	outputPackage string
+	OutputPackage string
 }
 
 func newGolangThriftClient(name string, addr *address.Address[*golangThriftServer]) (*golangThriftClient, error) {
 	node := &golangThriftClient{}
 	node.InstanceName = name
 	node.ServerAddr = addr
-	node.outputPackage = "thrift"
+	node.OutputPackage = "thrift"
 
 	return node, nil
 }
@@ -75,12 +79,12 @@ func (node *golangThriftClient) GenerateFuncs(builder golang.ModuleBuilder) erro
 	}
 
 	// Generate the .thrift files
-	err = thriftcodegen.GenerateThrift(builder, iface, node.outputPackage)
+	err = thriftcodegen.GenerateThrift(builder, iface, node.OutputPackage)
 	if err != nil {
 		return err
 	}
 
-	err = thriftcodegen.GenerateClient(builder, iface, node.outputPackage)
+	err = thriftcodegen.GenerateClient(builder, iface, node.OutputPackage)
 	if err != nil {
 		return err
 	}
@@ -99,7 +103,7 @@ func (node *golangThriftClient) AddInstantiation(builder golang.NamespaceBuilder
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_ThriftClient", iface.BaseName),
 			Arguments: []gocode.Variable{
@@ -12,6 +12,11 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*golangThriftServer]()
+	ir.RegisterJSONType[*address.Address[*golangThriftServer]]()
+}
+
 // IRNode representing a Golang thrift server.
 // This node does not introduce any new runtime interfaces or types that can be used by other IRNodes.
 // Thrift code generation happens during the ModuleBuilder GeneratesFuncs pass
@@ -24,7 +29,7 @@ type golangThriftServer struct {
 	Bind         *address.BindConfig
 	Wrapped      golang.Service
 
-	outputPackage string
+	OutputPackage string
 }
 
 type ThriftInterface struct {
@@ -44,7 +49,7 @@ func newGolangThriftServer(name string, service golang.Service) (*golangThriftSe
 	node := &golangThriftServer{}
 	node.InstanceName = name
 	node.Wrapped = service
-	node.outputPackage = "thrift"
+	node.OutputPackage = "thrift"
 	return node, nil
 }
 
@@ -67,12 +72,12 @@ func (node *golangThriftServer) GenerateFuncs(builder golang.ModuleBuilder) erro
 		return nil
 	}
 
-	err = thriftcodegen.GenerateThrift(builder, iface, node.outputPackage)
+	err = thriftcodegen.GenerateThrift(builder, iface, node.OutputPackage)
 	if err != nil {
 		return err
 	}
 
-	err = thriftcodegen.GenerateServerHandler(builder, iface, node.outputPackage)
+	err = thriftcodegen.GenerateServerHandler(builder, iface, node.OutputPackage)
 	if err != nil {
 		return err
 	}
@@ -90,7 +95,7 @@ func (node *golangThriftServer) AddInstantiation(builder golang.NamespaceBuilder
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_ThriftServerHandler", iface.BaseName),
 			Arguments: []gocode.Variable{
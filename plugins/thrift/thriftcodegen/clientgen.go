@@ -40,7 +40,7 @@ func GenerateClient(builder golang.ModuleBuilder, service *gocode.ServiceInterfa
 
 	slog.Info(fmt.Sprintf("Generating %v/%v.go", client.Package.PackageName, client.Name))
 	outputFile := filepath.Join(client.Package.Path, client.Name+".go")
-	return gogen.ExecuteTemplateToFile("ThriftClient", clientTemplate, client, outputFile)
+	return clientTemplate.ExecuteToFile(builder, client, outputFile)
 }
 
 // Arguments to the template code
@@ -62,7 +62,7 @@ func (c *clientArgs) ThriftMethodName(methodName string) string {
 	return capitalized
 }
 
-var clientTemplate = `// Blueprint: Auto-generated by Thrift Plugin
+var clientTemplate = gogen.RegisterTemplate("thrift", "client", `// Blueprint: Auto-generated by Thrift Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -136,4 +136,4 @@ func (client *{{$receiver}}) {{SignatureWithRetVars $f}} {
 	return
 }
 {{end}}
-`
\ No newline at end of file
+`)
\ No newline at end of file
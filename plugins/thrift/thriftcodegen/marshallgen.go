@@ -41,6 +41,9 @@ func (b *ThriftBuilder) GenerateMarshallingCode(outputFilePath string) error {
 	args.Imports = gogen.NewImports(args.PackageName)
 
 	for _, struc := range args.ThriftBuilder.Structs {
+		if struc.SrcType != nil {
+			args.Imports.AddType(struc.SrcType)
+		}
 		for _, field := range struc.FieldList {
 			args.Imports.AddType(field.SrcType)
 		}
@@ -100,17 +103,17 @@ func unmarshall_{{$service.Name}}_{{$method.Name}}_rsp(msg *{{$pkg}}.{{$method.R
 {{end -}}
 
 {{$pkg := .ImportName}}
-{{ range $t, $struct := .GoStructs}}
-// Utility function to pack {{$imports.Qualify $t.Package $t.Name}} into a Thrift {{$struct.ThriftType.Name}} struct
-func marshall_{{$pkg}}_{{$struct.ThriftType.Name}}(msg *{{$pkg}}.{{$struct.ThriftType.Name}}, obj *{{$imports.Qualify $t.Package $t.Name}}) *{{$pkg}}.{{$struct.ThriftType.Name}} {
+{{ range $_, $struct := .GoStructs}}
+// Utility function to pack {{$imports.NameOf $struct.SrcType}} into a Thrift {{$struct.ThriftType.Name}} struct
+func marshall_{{$pkg}}_{{$struct.ThriftType.Name}}(msg *{{$pkg}}.{{$struct.ThriftType.Name}}, obj *{{$imports.NameOf $struct.SrcType}}) *{{$pkg}}.{{$struct.ThriftType.Name}} {
 	{{- range $j, $field := $struct.FieldList}}
 	{{$field.Marshall $imports "obj." $pkg}}
 	{{- end}}
 	return msg
 }
 
-// Utility function to unpack {{$imports.Qualify $t.Package $t.Name}} from a Thrift {{$struct.ThriftType.Name}} struct
-func unmarshall_{{$pkg}}_{{$struct.ThriftType.Name}}(msg *{{$pkg}}.{{$struct.ThriftType.Name}}, obj *{{$imports.Qualify $t.Package $t.Name}}) {
+// Utility function to unpack {{$imports.NameOf $struct.SrcType}} from a Thrift {{$struct.ThriftType.Name}} struct
+func unmarshall_{{$pkg}}_{{$struct.ThriftType.Name}}(msg *{{$pkg}}.{{$struct.ThriftType.Name}}, obj *{{$imports.NameOf $struct.SrcType}}) {
 	{{- range $j, $field := $struct.FieldList}}
 	{{$field.Unmarshall $imports "obj." $pkg}}
 	{{- end}}
@@ -37,7 +37,7 @@ func GenerateServerHandler(builder golang.ModuleBuilder, service *gocode.Service
 	slog.Info(fmt.Sprintf("Generating %v/%v_ThriftServer.go", server.Package.PackageName, service.Name))
 	outputFile := filepath.Join(server.Package.Path, service.Name+
 		"_ThriftServer.go")
-	return gogen.ExecuteTemplateToFile("ThriftServer", serverTemplate, server, outputFile)
+	return serverTemplate.ExecuteToFile(builder, server, outputFile)
 }
 
 // Arguments to the template code
@@ -59,7 +59,7 @@ func (s *serverArgs) ThriftMethodName(methodName string) string {
 	return capitalized
 }
 
-var serverTemplate = `// Blueprint: Auto-generated by Thrift Plugin
+var serverTemplate = gogen.RegisterTemplate("thrift", "server", `// Blueprint: Auto-generated by Thrift Plugin
 
 package {{.Package.ShortName}}
 
@@ -117,4 +117,4 @@ func (handler *{{$receiver}}) {{$.ThriftMethodName $f.Name -}}(ctx context.Conte
 	return rsp, nil
 }
 {{end}}
-`
\ No newline at end of file
+`)
\ No newline at end of file
@@ -23,9 +23,22 @@ func GenerateThrift(builder golang.ModuleBuilder, service *gocode.ServiceInterfa
 		return nil
 	}
 
+	outputFilename, err := WriteThrift(builder, service, outputPackage)
+	if err != nil {
+		return err
+	}
+
+	return CompileThriftFile(outputFilename)
+}
+
+// Writes the .thrift file for the provided service interface, and the code that converts between golang
+// and Thrift types, to outputPackage, without compiling the .thrift file.
+//
+// Returns the name of the .thrift file.  Most plugins should use [GenerateThrift] instead.
+func WriteThrift(builder golang.ModuleBuilder, service *gocode.ServiceInterface, outputPackage string) (string, error) {
 	modules := workflowspec.Get().Derive().Modules
 	if err := modules.AddWorkspace(builder.Workspace().Info().Path); err != nil {
-		return err
+		return "", err
 	}
 
 	tf := NewThriftBuilder(modules)
@@ -39,29 +52,24 @@ func GenerateThrift(builder golang.ModuleBuilder, service *gocode.ServiceInterfa
 
 	err := tf.AddService(service)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	outputDir := filepath.Join(builder.Info().Path, filepath.Join(splits...))
 	err = os.MkdirAll(outputDir, 0755)
 	if err != nil {
-		return blueprint.Errorf("unable to create thrift output dir %v due to %v", outputDir, err.Error())
+		return "", blueprint.Errorf("unable to create thrift output dir %v due to %v", outputDir, err.Error())
 	}
 
 	outputFilename := filepath.Join(outputDir, service.BaseName+".thrift")
 	err = tf.WriteThriftFile(outputFilename)
 	if err != nil {
-		return err
-	}
-
-	err = CompileThriftFile(outputFilename)
-	if err != nil {
-		return err
+		return "", err
 	}
 
 	slog.Info(fmt.Sprintf("Generating %v/%v_conversions.go", tf.PackageName, service.BaseName))
 	marshallFile := filepath.Join(outputDir, service.BaseName+"_conversions.go")
-	return tf.GenerateMarshallingCode(marshallFile)
+	return outputFilename, tf.GenerateMarshallingCode(marshallFile)
 }
 
 func rel(path string) string {
@@ -106,6 +114,7 @@ type ThriftStructDecl struct {
 	Builder    *ThriftBuilder
 	Name       string
 	ThriftType *gocode.UserType
+	SrcType    gocode.TypeName // The golang struct, or instantiation of a generic struct, that this struct corresponds to; nil for request and response structs
 	FieldList  []*ThriftField
 }
 
@@ -131,7 +140,7 @@ type ThriftBuilder struct {
 	InternalPkg string
 	Services    map[string]*ThriftServiceDecl
 	Structs     map[string]*ThriftStructDecl
-	GoStructs   map[gocode.UserType]*ThriftStructDecl
+	GoStructs   map[string]*ThriftStructDecl // Structs that correspond to golang structs, keyed by struct name
 }
 
 func NewThriftBuilder(code *goparser.ParsedModuleSet) *ThriftBuilder {
@@ -139,7 +148,7 @@ func NewThriftBuilder(code *goparser.ParsedModuleSet) *ThriftBuilder {
 	t.Code = code
 	t.Services = make(map[string]*ThriftServiceDecl)
 	t.Structs = make(map[string]*ThriftStructDecl)
-	t.GoStructs = make(map[gocode.UserType]*ThriftStructDecl)
+	t.GoStructs = make(map[string]*ThriftStructDecl)
 	return t
 }
 
@@ -239,7 +248,32 @@ func (b *ThriftBuilder) AddService(iface *gocode.ServiceInterface) error {
 }
 
 func (b *ThriftBuilder) GetOrAddMessage(t *gocode.UserType) (*ThriftStructDecl, error) {
-	if structDecl, exists := b.GoStructs[*t]; exists {
+	return b.getOrAddMessage(t, t, nil)
+}
+
+// Gets or adds the struct for an instantiation of a generic struct, e.g. Page[Item].
+//
+// Each instantiation gets its own struct, e.g. model.Page[model.Item] and model.Page[model.User] are
+// declared as structs Model_Page_Model_Item and Model_Page_Model_User, whose fields have the struct's
+// type params substituted with the instantiation's type arguments.  See [gocode.IdentifierName].
+func (b *ThriftBuilder) GetOrAddGenericMessage(t *gocode.GenericType) (*ThriftStructDecl, error) {
+	base, isUserType := t.BaseType.(*gocode.UserType)
+	if !isUserType {
+		return nil, blueprint.Errorf("Thrift cannot serialize %v because %v is not a struct", t, t.BaseType)
+	}
+	return b.getOrAddMessage(t, base, t.TypeArgs)
+}
+
+// Gets or adds the struct for srcType, which is either the struct t or an instantiation of t with typeArgs
+func (b *ThriftBuilder) getOrAddMessage(srcType gocode.TypeName, t *gocode.UserType, typeArgs []gocode.TypeName) (*ThriftStructDecl, error) {
+	name, err := gocode.IdentifierName(srcType)
+	if err != nil {
+		return nil, err
+	}
+	if structDecl, exists := b.GoStructs[name]; exists {
+		if !structDecl.SrcType.Equals(srcType) {
+			return nil, blueprint.Errorf("Thrift cannot declare structs for both %v and %v because both would be named %v", structDecl.SrcType, srcType, name)
+		}
 		return structDecl, nil
 	}
 
@@ -257,23 +291,28 @@ func (b *ThriftBuilder) GetOrAddMessage(t *gocode.UserType) (*ThriftStructDecl,
 			return nil, blueprint.Errorf("could not find %v within %v", t.Name, t.Package)
 		}
 	}
+	if len(struc.TypeParams) != len(typeArgs) {
+		return nil, blueprint.Errorf("%v has %v type params but is used with %v type arguments in %v", t, len(struc.TypeParams), len(typeArgs), srcType)
+	}
 
-	thrift_struct := b.newStruct(t.Name)
-	b.GoStructs[*t] = thrift_struct
+	thrift_struct := b.newStruct(name)
+	thrift_struct.SrcType = srcType
+	b.GoStructs[name] = thrift_struct
 	for _, field := range struc.FieldsList {
-		// We ignore promoted and anonymous struct / interface extensions
-		if _, isNamed := struc.Fields[field.Name]; !isNamed {
-			// TODO (not implemented yet): support promoted and anonymous, handle interfaces and promoted struct fields
+		// Embedded structs are serialized like a field named after the struct, since that is how Go names them.
+		// Other embedded types, such as interfaces, are ignored.
+		fieldType := field.InstantiatedType(typeArgs)
+		if field.Embedded && !b.Code.IsStruct(fieldType) {
 			continue
 		}
 
-		fieldThrift, fieldGoThrift, err := b.getThriftType(field.Type)
+		fieldThrift, fieldGoThrift, err := b.getThriftType(fieldType)
 		if err != nil {
 			return nil, err
 		}
 
 		thrift_struct.FieldList = append(thrift_struct.FieldList, &ThriftField{
-			SrcType:      field.Type,
+			SrcType:      fieldType,
 			ThriftType:   fieldThrift,
 			ThriftGoType: fieldGoThrift,
 			Name:         field.Name,
@@ -322,6 +361,12 @@ func (b *ThriftBuilder) getThriftType(t gocode.TypeName) (string, gocode.TypeNam
 			return "", nil, err
 		}
 		return struc.Name, struc.ThriftType, nil
+	case *gocode.GenericType:
+		struc, err := b.GetOrAddGenericMessage(arg)
+		if err != nil {
+			return "", nil, err
+		}
+		return struc.Name, struc.ThriftType, nil
 	case *gocode.BasicType:
 		if thriftType, ok := basicToThirft[arg.Name]; ok {
 			return thriftType, &gocode.BasicType{Name: thriftToBasic[thriftType]}, nil
@@ -24,11 +24,16 @@ func generateClient(builder golang.ModuleBuilder, wrapped *gocode.ServiceInterfa
 		Imports: gogen.NewImports(pkg.Name),
 	}
 
-	client.Imports.AddPackages("context", "time", "errors")
+	client.Imports.AddPackages("context", "time")
+	for _, f := range wrapped.Methods {
+		if !client.IsStreaming(f) {
+			client.Imports.AddPackages("errors")
+		}
+	}
 	slog.Info(fmt.Sprintf("Generating %v/%v", client.Package.PackageName, wrapped.BaseName+"_TimeoutClient"))
 	outputFile := filepath.Join(client.Package.Path, wrapped.BaseName+"_TimeoutClient.go")
 
-	return gogen.ExecuteTemplateToFile("Timeouts", clientTemplate, client, outputFile)
+	return clientTemplate.ExecuteToFile(builder, &client, outputFile)
 }
 
 type clientArgs struct {
@@ -38,7 +43,20 @@ type clientArgs struct {
 	Imports *gogen.Imports
 }
 
-var clientTemplate = `// Blueprint: Auto-generated by Timeouts Plugin
+// Reports whether f has channel arguments or retvals.  The streams of such methods outlive the call, so
+// they would be cut short by cancelling the call's context when it returns.
+func (client *clientArgs) IsStreaming(f gocode.Func) bool {
+	for _, vars := range [][]gocode.Variable{f.Arguments, f.Returns} {
+		for _, v := range vars {
+			if _, isChan := gocode.ChanElem(v.Type); isChan {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var clientTemplate = gogen.RegisterTemplate("timeouts", "client", `// Blueprint: Auto-generated by Timeouts Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -63,6 +81,10 @@ func New_{{.Name}} (ctx context.Context, client {{.Imports.NameOf .Service.UserT
 {{$receiver := .Name -}}
 {{ range $_, $f := .Service.Methods }}
 func (client *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.Context"}}) ({{RetVarsAndTypes $f "err error"}}) {
+{{- if $.IsStreaming $f}}
+	// Not subject to the timeout, because the streams outlive the call
+	return client.Client.{{$f.Name}}({{ArgVars $f "ctx"}})
+{{- else}}
 	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(client.Timeout))
 	defer cancel()
 	is_complete := make(chan bool)
@@ -79,6 +101,7 @@ func (client *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.Co
 	case <-is_complete:
 		return
 	}
+{{- end}}
 }
 {{end}}
-`
+`)
@@ -0,0 +1,21 @@
+package timeouts
+
+import (
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("timeouts.Add", func(spec wiring.WiringSpec, step declarative.Step) ([]string, error) {
+		if err := step.CheckOptions("timeout"); err != nil {
+			return nil, err
+		}
+		timeout, err := step.GetDuration("timeout")
+		if err != nil {
+			return nil, err
+		}
+		return declarative.Apply(func(spec wiring.WiringSpec, serviceName string) {
+			Add(spec, serviceName, timeout)
+		})(spec, step)
+	})
+}
@@ -11,6 +11,10 @@ import (
 	"github.com/blueprint-uservices/blueprint/plugins/golang/gocode"
 )
 
+func init() {
+	ir.RegisterJSONType[*TimeoutClient]()
+}
+
 // Blueprint IR node representing a Timeout node
 type TimeoutClient struct {
 	golang.Service
@@ -21,7 +25,7 @@ type TimeoutClient struct {
 	Wrapped      golang.Service
 
 	TimeoutValue  *ir.IRValue
-	outputPackage string
+	OutputPackage string
 }
 
 func newTimeoutClient(name string, server ir.IRNode, timeout string) (*TimeoutClient, error) {
@@ -33,7 +37,7 @@ func newTimeoutClient(name string, server ir.IRNode, timeout string) (*TimeoutCl
 	node := &TimeoutClient{}
 	node.InstanceName = name
 	node.Wrapped = serverNode
-	node.outputPackage = "timeouts"
+	node.OutputPackage = "timeouts"
 	node.TimeoutValue = &ir.IRValue{Value: timeout}
 	return node, nil
 }
@@ -75,7 +79,7 @@ func (node *TimeoutClient) GenerateFuncs(builder golang.ModuleBuilder) error {
 		return err
 	}
 
-	return generateClient(builder, iface, node.outputPackage)
+	return generateClient(builder, iface, node.OutputPackage)
 }
 
 // Implements golang.Instantiable
@@ -90,7 +94,7 @@ func (node *TimeoutClient) AddInstantiation(builder golang.NamespaceBuilder) err
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_TimeoutClient", iface.BaseName),
 			Arguments: []gocode.Variable{
@@ -3,12 +3,17 @@
 // The plugin configures clients with a timeout mechanism using contexts.
 // The plugin will generate a wrapper client class that will wait for a fixed amount of time (the specified timeout value) before canceling the context. Once the context is cancelled, the execution returns to the caller.
 //
+// Methods with channel arguments or return values are not subject to the timeout, because their streams
+// outlive the call; cancelling the context when the call returns would close the streams early.
+//
 // Example Usage to add a "1s" timeout to each request:
 //
 //	timeouts.Add(spec, "my_service", "1s")
 package timeouts
 
 import (
+	"time"
+
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/pointer"
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
@@ -17,7 +22,16 @@ import (
 	"golang.org/x/exp/slog"
 )
 
-var PROP_TIMEOUT = "Timeout"
+var PROP_TIMEOUT = "timeouts.Timeout"
+
+// The timeout of a timeout client, which must be a valid duration string
+var TimeoutProperty = wiring.DeclareProperty(wiring.Property[string]{
+	Key: PROP_TIMEOUT,
+	Validate: func(timeout string) error {
+		_, err := time.ParseDuration(timeout)
+		return err
+	},
+})
 var IRNODE_TIMEOUT_SUFFIX = ".client.timeout"
 
 // Adds timeouts to client calls for the specified service.
@@ -32,14 +46,14 @@ var IRNODE_TIMEOUT_SUFFIX = ".client.timeout"
 func Add(spec wiring.WiringSpec, serviceName string, timeout string) {
 	clientWrapper := serviceName + IRNODE_TIMEOUT_SUFFIX
 
-	spec.AddProperty(clientWrapper, PROP_TIMEOUT, timeout)
+	TimeoutProperty.Set(spec, clientWrapper, timeout)
 
 	ptr := pointer.GetPointer(spec, serviceName)
 	if ptr == nil {
 		slog.Error("Unable to add timeouts to " + serviceName + " as it is not a pointer")
 	}
 
-	clientNext := ptr.AddSrcModifier(spec, clientWrapper)
+	clientNext := ptr.AddSrcModifier(spec, clientWrapper, pointer.InProcessModifier)
 
 	spec.Define(clientWrapper, &TimeoutClient{}, func(ns wiring.Namespace) (ir.IRNode, error) {
 		var wrapped golang.Service
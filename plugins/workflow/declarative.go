@@ -0,0 +1,42 @@
+package workflow
+
+import (
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	// The service type is given by the type option, e.g.
+	//   type: github.com/blueprint-uservices/blueprint/examples/leaf/workflow/leaf.LeafServiceImpl
+	// The optional constructor and interface options choose the service's constructor and interface.
+	declarative.Register("workflow.Service", func(spec wiring.WiringSpec, step declarative.Step) ([]string, error) {
+		if err := step.CheckOptions("type", "constructor", "interface"); err != nil {
+			return nil, err
+		}
+		serviceType, err := step.GetString("type")
+		if err != nil {
+			return nil, err
+		}
+		var constructor, iface string
+		if _, exists := step.Options["constructor"]; exists {
+			if constructor, err = step.GetString("constructor"); err != nil {
+				return nil, err
+			}
+		}
+		if _, exists := step.Options["interface"]; exists {
+			if iface, err = step.GetString("interface"); err != nil {
+				return nil, err
+			}
+		}
+		return declarative.DefineWith(func(spec wiring.WiringSpec, serviceName string, serviceArgs ...string) string {
+			ServiceByName(spec, serviceName, serviceType, serviceArgs...)
+			if constructor != "" {
+				SetConstructor(spec, serviceName, constructor)
+			}
+			if iface != "" {
+				SetInterface(spec, serviceName, iface)
+			}
+			return serviceName
+		})(spec, step)
+	})
+}
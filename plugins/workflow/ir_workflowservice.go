@@ -12,6 +12,11 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*workflowHandler]()
+	ir.RegisterJSONType[*workflowClient]()
+}
+
 // This Node represents a Golang Workflow spec service in the Blueprint IR.
 type workflowNode struct {
 	// IR node types
@@ -39,9 +44,9 @@ type workflowHandler struct {
 	Args []ir.IRNode
 }
 
-func initWorkflowNode[ServiceType any](n *workflowNode, name string) (err error) {
+func initWorkflowNode(n *workflowNode, name string, getService func() (*workflowspec.Service, error)) (err error) {
 	n.InstanceName = name
-	n.ServiceInfo, err = workflowspec.GetService[ServiceType]()
+	n.ServiceInfo, err = getService()
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,8 @@
 package workflow
 
 import (
+	"strings"
+
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
 )
 
@@ -8,3 +10,15 @@ import (
 func FilterWorkflowNodes(nodes []ir.IRNode) []*workflowHandler {
 	return ir.Filter[*workflowHandler](nodes)
 }
+
+// Returns the name of the workflow service that node is the client or handler of, or false if node
+// is not a workflow node.
+func ServiceName(node ir.IRNode) (string, bool) {
+	switch n := node.(type) {
+	case *workflowHandler:
+		return n.InstanceName, true
+	case *workflowClient:
+		return strings.TrimSuffix(n.InstanceName, ".client"), true
+	}
+	return "", false
+}
@@ -31,6 +31,14 @@
 // The arguments provided to a service must match the arguments needed by the service's
 // constructor in the workflow spec.  If they do not match, you will see a compilation error.
 //
+// If the service has more than one constructor, or is a struct that implements more than one
+// service interface, then the constructor and interface to use must be chosen explicitly;
+// otherwise compilation fails with an error listing the candidates:
+//
+//	payment_service := workflow.Service[*payment.PaymentServiceImpl](spec, "payment_service")
+//	workflow.SetConstructor(spec, payment_service, "NewPaymentServiceImpl")
+//	workflow.SetInterface(spec, payment_service, "PaymentService")
+//
 // # Generated Artifacts
 //
 // The workflow spec service implementation will be copied into the output directory.
@@ -41,15 +49,24 @@
 package workflow
 
 import (
+	"strings"
+
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/pointer"
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
 	"github.com/blueprint-uservices/blueprint/plugins/golang/gocode"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow/workflowspec"
 )
 
 var strtype = &gocode.BasicType{Name: "string"}
 
+// The constructor of a workflow service, set with [SetConstructor]
+var ConstructorProperty = wiring.DeclareProperty(wiring.Property[string]{Key: "workflow.Constructor"})
+
+// The service interface of a workflow service, set with [SetInterface]
+var InterfaceProperty = wiring.DeclareProperty(wiring.Property[string]{Key: "workflow.Interface"})
+
 // [Service] is used by wiring specs to instantiate services from the workflow spec.
 //
 // Type parameter [ServiceType] is used to specify the type of the service.  It can be the name of an interface
@@ -66,13 +83,86 @@ var strtype = &gocode.BasicType{Name: "string"}
 //
 // After calling [Service], serviceName is an application-level golang service.  Application-level modifiers
 // can be applied to it, or it can be further deployed into e.g. a goproc, a linuxcontainer, etc.
+//
+// If the choice of the service's constructor or interface is ambiguous, use [SetConstructor] and [SetInterface].
 func Service[ServiceType any](spec wiring.WiringSpec, serviceName string, serviceArgs ...string) string {
+	return defineService(spec, serviceName, workflowspec.GetServiceWithOptions[ServiceType], serviceArgs...)
+}
+
+// [ServiceByName] is like [Service], but the type of the service is given as a string rather than as a
+// type parameter.  It is intended for wiring specs that are not written in Go, e.g. those loaded by the
+// declarative plugin.
+//
+// `serviceType` is the fully-qualified name of the service's interface or implementing struct, e.g.
+//
+//	github.com/blueprint-uservices/blueprint/examples/sockshop/workflow/payment.PaymentService
+//
+// The package of the service must be a dependency of the wiring spec's module; see
+// [workflowspec.GetServiceByName].
+func ServiceByName(spec wiring.WiringSpec, serviceName string, serviceType string, serviceArgs ...string) string {
+	pkg, name := splitTypeName(serviceType)
+	return defineService(spec, serviceName, func(opts workflowspec.ServiceOptions) (*workflowspec.Service, error) {
+		if pkg == "" {
+			return nil, blueprint.Errorf("invalid service type %v for %v; expected a fully-qualified type name such as github.com/example/workflow/leaf.LeafService", serviceType, serviceName)
+		}
+		return workflowspec.GetServiceByNameWithOptions(pkg, name, opts)
+	}, serviceArgs...)
+}
+
+// [SetConstructor] chooses the constructor of a service instantiated with [Service] or [ServiceByName].  It is
+// required if the service has more than one constructor in the workflow spec.
+//
+// `constructor` is the name of the constructor func, optionally qualified with its package, e.g.
+//
+//	workflow.SetConstructor(spec, "payment_service", "NewPaymentServiceImpl")
+func SetConstructor(spec wiring.WiringSpec, serviceName string, constructor string) {
+	ConstructorProperty.Set(spec, serviceName+".handler", constructor)
+}
+
+// [SetInterface] chooses the service interface of a service instantiated with [Service] or [ServiceByName].  It
+// is required if the service's type is a struct that implements more than one service interface.
+//
+// `iface` is the name of the interface, optionally qualified with its package, e.g.
+//
+//	workflow.SetInterface(spec, "payment_service", "PaymentService")
+func SetInterface(spec wiring.WiringSpec, serviceName string, iface string) {
+	InterfaceProperty.Set(spec, serviceName+".handler", iface)
+}
+
+// Gets the options set on the service's handler by [SetConstructor] and [SetInterface]
+func getServiceOptions(namespace wiring.Namespace, handlerName string) (opts workflowspec.ServiceOptions, err error) {
+	if opts.Constructor, err = ConstructorProperty.Get(namespace, handlerName); err != nil {
+		return
+	}
+	opts.Interface, err = InterfaceProperty.Get(namespace, handlerName)
+	return
+}
+
+// Splits a fully-qualified type name into its package and type name
+func splitTypeName(typeName string) (pkg string, name string) {
+	i := strings.LastIndex(typeName, ".")
+	if i < 0 || i < strings.LastIndex(typeName, "/") {
+		return "", typeName
+	}
+	return typeName[:i], typeName[i+1:]
+}
+
+func defineService(spec wiring.WiringSpec, serviceName string, getService func(workflowspec.ServiceOptions) (*workflowspec.Service, error), serviceArgs ...string) string {
 	// Define the service
 	handlerName := serviceName + ".handler"
+	getServiceWithOptions := func(namespace wiring.Namespace) func() (*workflowspec.Service, error) {
+		return func() (*workflowspec.Service, error) {
+			opts, err := getServiceOptions(namespace, handlerName)
+			if err != nil {
+				return nil, err
+			}
+			return getService(opts)
+		}
+	}
 	spec.Define(handlerName, &workflowHandler{}, func(namespace wiring.Namespace) (ir.IRNode, error) {
 		// Create the IR node for the handler
 		handler := &workflowHandler{}
-		if err := initWorkflowNode[ServiceType](&handler.workflowNode, serviceName); err != nil {
+		if err := initWorkflowNode(&handler.workflowNode, serviceName, getServiceWithOptions(namespace)); err != nil {
 			return nil, err
 		}
 
@@ -106,7 +196,7 @@ func Service[ServiceType any](spec wiring.WiringSpec, serviceName string, servic
 	clientNext := ptr.AddSrcModifier(spec, clientName)
 	spec.Define(clientName, &workflowClient{}, func(namespace wiring.Namespace) (ir.IRNode, error) {
 		client := &workflowClient{}
-		if err := initWorkflowNode[ServiceType](&client.workflowNode, clientName); err != nil {
+		if err := initWorkflowNode(&client.workflowNode, clientName, getServiceWithOptions(namespace)); err != nil {
 			return nil, err
 		}
 		return client, namespace.Get(clientNext, &client.Wrapped)
@@ -44,11 +44,18 @@ func Add[T any]() error {
 // using [GetServiceByName] might fail if it names a package that doesn't exist
 // in the local go cache / on the go path.
 func GetService[T any]() (*Service, error) {
+	return GetServiceWithOptions[T](ServiceOptions{})
+}
+
+// Like [GetService], but opts can choose the constructor and interface of the service, which is
+// necessary if the service has more than one constructor, or is a struct that implements more
+// than one service interface.
+func GetServiceWithOptions[T any](opts ServiceOptions) (*Service, error) {
 	modInfo, t, err := goparser.FindModule[T]()
 	if err != nil {
 		return nil, err
 	}
-	return cached.get(t.Package, t.Name, modInfo)
+	return cached.get(t.Package, t.Name, modInfo, opts)
 }
 
 // Gets a [WorkflowSpecService] for the specified type.
@@ -68,10 +75,16 @@ func GetService[T any]() (*Service, error) {
 //
 //	import _ "github.com/blueprint-uservices/blueprint/examples/sockshop/tests"
 func GetServiceByName(pkg, name string) (*Service, error) {
+	return GetServiceByNameWithOptions(pkg, name, ServiceOptions{})
+}
+
+// Like [GetServiceByName], but opts can choose the constructor and interface of the service; see
+// [GetServiceWithOptions].
+func GetServiceByNameWithOptions(pkg, name string, opts ServiceOptions) (*Service, error) {
 	modInfo, err := goparser.FindPackageModule(pkg)
 	if err != nil {
 		return nil, err
 	}
 
-	return cached.get(pkg, name, modInfo)
+	return cached.get(pkg, name, modInfo, opts)
 }
@@ -0,0 +1,109 @@
+package workflowspec
+
+import (
+	"encoding/json"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+	"github.com/blueprint-uservices/blueprint/plugins/golang/goparser"
+)
+
+// IR nodes refer to services and interfaces of the workflow spec, which are parsed from source code.  When the IR is
+// written to JSON, only the package and name of each are written; when the IR is read back, the source code is parsed
+// again, into the shared workflow spec.
+func init() {
+	ir.RegisterJSONCodec(encodeService, decodeService)
+	ir.RegisterJSONCodec(encodeInterface, decodeInterface)
+}
+
+// The package and name of a declaration in the workflow spec
+type declRef struct {
+	Package string `json:"package"`
+	Name    string `json:"name"`
+}
+
+type serviceRef struct {
+	Iface       declRef  `json:"iface"`
+	Constructor declRef  `json:"constructor"`
+	Struct      *declRef `json:"struct,omitempty"`
+}
+
+func encodeService(service *Service) (any, error) {
+	ref := serviceRef{
+		Iface:       declRef{service.Iface.File.Package.Name, service.Iface.Name},
+		Constructor: declRef{service.Constructor.File.Package.Name, service.Constructor.Name},
+	}
+	if service.Struct != nil {
+		ref.Struct = &declRef{service.Struct.File.Package.Name, service.Struct.Name}
+	}
+	return ref, nil
+}
+
+func decodeService(data json.RawMessage) (*Service, error) {
+	var ref serviceRef
+	if err := json.Unmarshal(data, &ref); err != nil {
+		return nil, err
+	}
+	service := &Service{}
+	iface, err := lookupPackage(ref.Iface.Package)
+	if err != nil {
+		return nil, err
+	}
+	if service.Iface = iface.Interfaces[ref.Iface.Name]; service.Iface == nil {
+		return nil, blueprint.Errorf("unable to find interface %v in workflow spec package %v", ref.Iface.Name, ref.Iface.Package)
+	}
+	constructor, err := lookupPackage(ref.Constructor.Package)
+	if err != nil {
+		return nil, err
+	}
+	if service.Constructor = constructor.Funcs[ref.Constructor.Name]; service.Constructor == nil {
+		return nil, blueprint.Errorf("unable to find constructor %v in workflow spec package %v", ref.Constructor.Name, ref.Constructor.Package)
+	}
+	if ref.Struct != nil {
+		struc, err := lookupPackage(ref.Struct.Package)
+		if err != nil {
+			return nil, err
+		}
+		if service.Struct = struc.Structs[ref.Struct.Name]; service.Struct == nil {
+			return nil, blueprint.Errorf("unable to find struct %v in workflow spec package %v", ref.Struct.Name, ref.Struct.Package)
+		}
+	}
+	return service, nil
+}
+
+func encodeInterface(iface *goparser.ParsedInterface) (any, error) {
+	return declRef{iface.File.Package.Name, iface.Name}, nil
+}
+
+func decodeInterface(data json.RawMessage) (*goparser.ParsedInterface, error) {
+	var ref declRef
+	if err := json.Unmarshal(data, &ref); err != nil {
+		return nil, err
+	}
+	pkg, err := lookupPackage(ref.Package)
+	if err != nil {
+		return nil, err
+	}
+	iface, exists := pkg.Interfaces[ref.Name]
+	if !exists {
+		return nil, blueprint.Errorf("unable to find interface %v in workflow spec package %v", ref.Name, ref.Package)
+	}
+	return iface, nil
+}
+
+// Parses the module containing pkg into the shared workflow spec, if it hasn't been already, and returns the package
+func lookupPackage(pkgName string) (*goparser.ParsedPackage, error) {
+	modInfo, err := goparser.FindPackageModule(pkgName)
+	if err != nil {
+		return nil, err
+	}
+	mod, err := cached.Modules.Add(modInfo)
+	if err != nil {
+		return nil, err
+	}
+	pkg, exists := mod.Packages[pkgName]
+	if !exists {
+		return nil, blueprint.Errorf("unable to find package %v in module %v", pkgName, mod.Name)
+	}
+	return pkg, nil
+}
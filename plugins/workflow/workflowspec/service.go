@@ -2,7 +2,10 @@ package workflowspec
 
 import (
 	"fmt"
+	"go/ast"
 	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
 	"github.com/blueprint-uservices/blueprint/plugins/golang"
@@ -54,7 +57,20 @@ func (s *Service) AddToWorkspace(builder golang.WorkspaceBuilder) error {
 	return nil
 }
 
-func (spec *WorkflowSpec) makeServiceFromStruct(struc *goparser.ParsedStruct) (*Service, error) {
+// Options for choosing the constructor and interface of a service, when they would otherwise be ambiguous.
+//
+// Names can be unqualified, e.g. NewLeafServiceImpl, or qualified with their package, e.g.
+// github.com/blueprint-uservices/blueprint/examples/leaf/workflow/leaf.NewLeafServiceImpl.
+type ServiceOptions struct {
+	// The name of the service's constructor.  Required if the service has more than one constructor.
+	Constructor string
+
+	// The name of the service's interface.  Required if the service is a struct that implements more
+	// than one valid service interface.
+	Interface string
+}
+
+func (spec *WorkflowSpec) makeServiceFromStruct(struc *goparser.ParsedStruct, opts ServiceOptions) (*Service, error) {
 	ifaces := spec.findInterfacesFor(struc)
 	if len(ifaces) == 0 {
 		return nil, blueprint.Errorf("unable to find service interfaces for %v", struc.Name)
@@ -78,8 +94,9 @@ func (spec *WorkflowSpec) makeServiceFromStruct(struc *goparser.ParsedStruct) (*
 		}
 		return nil, blueprint.Errorf("%v implements %v but it is not a valid service due to %v", struc.Name, ifaces[0].Name, errors[0])
 	}
-	if len(validIfaces) > 1 {
-		slog.Warn(fmt.Sprintf("Warning: struct %v implements more than one service interface; using %v", struc.Name, validIfaces[0]))
+	iface, err := chooseInterface(struc.Name, validIfaces, opts.Interface)
+	if err != nil {
+		return nil, err
 	}
 
 	// Find constructors
@@ -87,57 +104,215 @@ func (spec *WorkflowSpec) makeServiceFromStruct(struc *goparser.ParsedStruct) (*
 	if len(constructors) == 0 {
 		return nil, blueprint.Errorf("no constructors for %v could be found, ie. funcs returning (*%v, error)", struc.Name, struc.Type().String())
 	}
-	if len(constructors) > 1 {
-		slog.Warn(fmt.Sprintf("multiple constructors of struct %v found; using %v", struc.Name, constructors[0].Name))
+	constructor, err := chooseConstructor(struc.Name, constructors, opts.Constructor)
+	if err != nil {
+		return nil, err
 	}
 
 	service := &Service{
-		Iface:       validIfaces[0],
-		Constructor: constructors[0],
+		Iface:       iface,
+		Constructor: constructor,
 		Struct:      struc,
 	}
-	slog.Info(fmt.Sprintf("Located %v (%v) in package %v", struc.Name, constructors[0].Name, validIfaces[0].File.Package.Name))
+	slog.Info(fmt.Sprintf("Located %v (%v) in package %v", struc.Name, constructor.Name, iface.File.Package.Name))
 	return service, nil
 }
 
-func (spec *WorkflowSpec) makeServiceFromInterface(iface *goparser.ParsedInterface) (*Service, error) {
+func (spec *WorkflowSpec) makeServiceFromInterface(iface *goparser.ParsedInterface, opts ServiceOptions) (*Service, error) {
 	valid, err := isInterfaceAValidService(iface)
 	if !valid {
 		return nil, blueprint.Errorf("interface %v is not a valid service because %v", iface.Name, err.Error())
 	}
+	if opts.Interface != "" && !matchesName(iface.File.Package.Name, iface.Name, opts.Interface) {
+		return nil, blueprint.Errorf("%v is itself a service interface, so interface %v cannot be chosen for it", iface.Name, opts.Interface)
+	}
 	constructors := spec.findConstructorsOfIface(iface)
 	if len(constructors) == 0 {
 		return nil, blueprint.Errorf("found interface %v in %v but could not find any constructor methods", iface.Name, iface.File.Package.Name)
 	}
-	if len(constructors) > 1 {
-		slog.Warn(fmt.Sprintf("multiple constructors of interface %v found; using %v", iface.Name, constructors[0].Name))
+	constructor, err := chooseConstructor(iface.Name, constructors, opts.Constructor)
+	if err != nil {
+		return nil, err
 	}
 
-	structs := spec.findStructsFor(iface)
-	if len(structs) == 0 {
-		return nil, blueprint.Errorf("No valid struct found for interface %v", iface)
-	}
-	if len(structs) > 1 {
-		slog.Warn(fmt.Sprintf("multiple strucs implement the interface %v", iface))
+	struc, err := chooseStruct(iface, spec.findStructsFor(iface, constructor), constructor)
+	if err != nil {
+		return nil, err
 	}
 
-	// TODO: Ideally match the struct with its corresponding constructor here
-
 	service := &Service{
 		Iface:       iface,
-		Constructor: constructors[0],
-		Struct:      structs[0],
+		Constructor: constructor,
+		Struct:      struc,
 	}
-	slog.Info(fmt.Sprintf("Located %v (%v) in package %v", iface.Name, constructors[0].Name, iface.File.Package.Name))
+	slog.Info(fmt.Sprintf("Located %v (%v) in package %v", iface.Name, constructor.Name, iface.File.Package.Name))
 	return service, nil
 
 }
 
+// Chooses the constructor of service that is named by chosen, or the only constructor if chosen is empty.
+// Returns an error listing the candidates if the choice is ambiguous.
+func chooseConstructor(service string, constructors []*goparser.ParsedFunc, chosen string) (*goparser.ParsedFunc, error) {
+	var pkgs, names []string
+	for _, f := range constructors {
+		pkgs, names = append(pkgs, f.File.Package.Name), append(names, f.Name)
+	}
+	i, err := choose(pkgs, names, chosen)
+	if err != nil {
+		return nil, blueprint.Errorf("unable to choose a constructor for %v: %v; use workflow.SetConstructor to choose one", service, err.Error())
+	}
+	return constructors[i], nil
+}
+
+// Chooses the service interface of struc that is named by chosen, or the only interface if chosen is empty.
+// Returns an error listing the candidates if the choice is ambiguous.
+func chooseInterface(struc string, ifaces []*goparser.ParsedInterface, chosen string) (*goparser.ParsedInterface, error) {
+	var pkgs, names []string
+	for _, iface := range ifaces {
+		pkgs, names = append(pkgs, iface.File.Package.Name), append(names, iface.Name)
+	}
+	i, err := choose(pkgs, names, chosen)
+	if err != nil {
+		return nil, blueprint.Errorf("unable to choose a service interface for %v: %v; use workflow.SetInterface to choose one", struc, err.Error())
+	}
+	return ifaces[i], nil
+}
+
+// Chooses the struct that implements iface and that constructor returns.  If more than one struct implements
+// iface, the struct is the one that the constructor's body instantiates.
+// Returns an error listing the candidates if the choice is ambiguous.
+func chooseStruct(iface *goparser.ParsedInterface, structs []*goparser.ParsedStruct, constructor *goparser.ParsedFunc) (*goparser.ParsedStruct, error) {
+	if len(structs) == 0 {
+		return nil, blueprint.Errorf("no valid struct found for interface %v", iface.Name)
+	}
+	if len(structs) == 1 {
+		return structs[0], nil
+	}
+
+	var pkgs, names []string
+	var constructed []*goparser.ParsedStruct
+	for _, struc := range structs {
+		pkgs, names = append(pkgs, struc.File.Package.Name), append(names, struc.Name)
+		if instantiates(constructor, struc) {
+			constructed = append(constructed, struc)
+		}
+	}
+	if len(constructed) == 1 {
+		return constructed[0], nil
+	}
+	_, err := choose(pkgs, names, "")
+	return nil, blueprint.Errorf("unable to determine which struct implementing %v is returned by %v: %v", iface.Name, constructor.Name, err.Error())
+}
+
+// Returns true if the body of f instantiates struc, i.e. contains a struc{...} literal or new(struc), or if
+// f returns the result of calling a func in the same package that instantiates struc.
+func instantiates(f *goparser.ParsedFunc, struc *goparser.ParsedStruct) bool {
+	return instantiatesVisiting(f, struc, make(map[*goparser.ParsedFunc]bool))
+}
+
+func instantiatesVisiting(f *goparser.ParsedFunc, struc *goparser.ParsedStruct, visited map[*goparser.ParsedFunc]bool) bool {
+	if f.Body == nil || visited[f] {
+		return false
+	}
+	visited[f] = true
+	unindex := func(e ast.Expr) ast.Expr {
+		if index, isIndex := e.(*ast.IndexExpr); isIndex {
+			return index.X
+		} else if index, isIndex := e.(*ast.IndexListExpr); isIndex {
+			return index.X
+		}
+		return e
+	}
+	isStruc := func(e ast.Expr) bool {
+		switch t := unindex(e).(type) {
+		case *ast.Ident:
+			return t.Name == struc.Name && f.File.Package == struc.File.Package
+		case *ast.SelectorExpr:
+			pkg, isIdent := t.X.(*ast.Ident)
+			if !isIdent || t.Sel.Name != struc.Name {
+				return false
+			}
+			imported, isImported := f.File.NamedImports[pkg.Name]
+			return isImported && imported.Package == struc.File.Package.Name
+		}
+		return false
+	}
+	// Follows calls such as return newImpl(...) to funcs in the same package
+	returnsInstance := func(e ast.Expr) bool {
+		call, isCall := e.(*ast.CallExpr)
+		if !isCall {
+			return false
+		}
+		fun, isIdent := unindex(call.Fun).(*ast.Ident)
+		if !isIdent {
+			return false
+		}
+		callee, exists := f.File.Package.Funcs[fun.Name]
+		return exists && instantiatesVisiting(callee, struc, visited)
+	}
+	found := false
+	ast.Inspect(f.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CompositeLit:
+			found = found || isStruc(node.Type)
+		case *ast.CallExpr:
+			if fun, isIdent := node.Fun.(*ast.Ident); isIdent && fun.Name == "new" && len(node.Args) == 1 {
+				found = found || isStruc(node.Args[0])
+			}
+		case *ast.ReturnStmt:
+			for _, result := range node.Results {
+				found = found || returnsInstance(result)
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// Returns the index of the candidate that matches chosen, or of the only candidate if chosen is empty.
+// Candidates are given by their packages and names.
+func choose(pkgs []string, names []string, chosen string) (int, error) {
+	var matches []int
+	var candidates []string
+	for i := range names {
+		if chosen == "" || matchesName(pkgs[i], names[i], chosen) {
+			matches = append(matches, i)
+		}
+		candidates = append(candidates, pkgs[i]+"."+names[i])
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+
+	sort.Strings(candidates)
+	if len(matches) == 0 {
+		return -1, blueprint.Errorf("%v is not one of the candidates %v", chosen, strings.Join(candidates, ", "))
+	}
+	if chosen == "" {
+		return -1, blueprint.Errorf("found %v candidates %v", len(candidates), strings.Join(candidates, ", "))
+	}
+	return -1, blueprint.Errorf("%v matches more than one of the candidates %v", chosen, strings.Join(candidates, ", "))
+}
+
+// Returns true if chosen is either name or the package-qualified name
+func matchesName(pkg string, name string, chosen string) bool {
+	return chosen == name || chosen == pkg+"."+name
+}
+
 /*
-A service interface is only valid if all methods receive ctx as
-first argument and return error as final retval
+A service interface is only valid if all methods, including those of
+embedded interfaces, receive ctx as first argument and return error as
+final retval.
+
+Methods can be asynchronous or streaming by using channels: arguments
+can be chan T or <-chan T, and other retvals can be <-chan T.  Send-only
+channels are not valid arguments, and only receive-only channels are
+valid retvals.
 */
 func isInterfaceAValidService(iface *goparser.ParsedInterface) (bool, error) {
+	if len(iface.Unresolved) > 0 {
+		return false, blueprint.Errorf("%v embeds %v, whose methods could not be found", iface.Name, iface.Unresolved)
+	}
 	for _, method := range iface.Methods {
 		if len(method.Arguments) == 0 {
 			return false, blueprint.Errorf("first argument of %v.%v must be context.Context", iface.Name, method.Name)
@@ -153,18 +328,37 @@ func isInterfaceAValidService(iface *goparser.ParsedInterface) (bool, error) {
 		if !isBasic || retL.Name != "error" {
 			return false, blueprint.Errorf("last retval of %v.%v must be error", iface.Name, method.Name)
 		}
+		for _, arg := range method.Arguments[1:] {
+			if _, isSendChan := arg.Type.(*gocode.SendChan); isSendChan {
+				return false, blueprint.Errorf("argument %v of %v.%v cannot be a send-only channel %v", arg.Name, iface.Name, method.Name, arg.Type)
+			}
+		}
+		for _, ret := range method.Returns[:len(method.Returns)-1] {
+			if elem, isChan := gocode.ChanElem(ret.Type); isChan {
+				if _, isReceiveChan := ret.Type.(*gocode.ReceiveChan); !isReceiveChan {
+					return false, blueprint.Errorf("retval %v of %v.%v must be a receive-only channel, i.e. <-chan %v", ret.Type, iface.Name, method.Name, elem)
+				}
+			}
+		}
 		// TODO: could potentially validate the serializability of args here
-		// TODO: handling chans for asynchronous calls
 	}
 	return true, nil
 }
 
 /*
-For a parsed struct, finds all valid interfaces that the struct implements
+For a parsed struct, finds all valid interfaces that the struct implements.
+
+Only the struct's own module and the modules it requires are searched, since
+the struct's package could not otherwise refer to the interface.
 */
 func (spec *WorkflowSpec) findInterfacesFor(struc *goparser.ParsedStruct) []*goparser.ParsedInterface {
+	required := requiredModules(struc.File.Package.Module)
+
 	var ifaces []*goparser.ParsedInterface
 	for _, mod := range spec.Modules.Modules {
+		if !required[mod.Name] {
+			continue
+		}
 		for _, pkg := range mod.Packages {
 			for _, iface := range pkg.Interfaces {
 				if valid, _ := implements(struc, iface); valid {
@@ -177,11 +371,19 @@ func (spec *WorkflowSpec) findInterfacesFor(struc *goparser.ParsedStruct) []*gop
 }
 
 /*
-For a parsed interface, finds all valid struct that implement the provided interface
+For a parsed interface, finds all valid struct that implement the provided interface.
+
+Only the constructor's own module and the modules it requires are searched, since
+the constructor could not otherwise instantiate the struct.
 */
-func (spec *WorkflowSpec) findStructsFor(iface *goparser.ParsedInterface) []*goparser.ParsedStruct {
+func (spec *WorkflowSpec) findStructsFor(iface *goparser.ParsedInterface, constructor *goparser.ParsedFunc) []*goparser.ParsedStruct {
+	required := requiredModules(constructor.File.Package.Module)
+
 	var structs []*goparser.ParsedStruct
 	for _, mod := range spec.Modules.Modules {
+		if !required[mod.Name] {
+			continue
+		}
 		for _, pkg := range mod.Packages {
 			for _, struc := range pkg.Structs {
 				if valid, _ := implements(struc, iface); valid {
@@ -193,6 +395,17 @@ func (spec *WorkflowSpec) findStructsFor(iface *goparser.ParsedInterface) []*gop
 	return structs
 }
 
+// Returns the names of mod and of the modules that mod requires
+func requiredModules(mod *goparser.ParsedModule) map[string]bool {
+	required := map[string]bool{mod.Name: true}
+	if mod.Modfile != nil {
+		for _, req := range mod.Modfile.Require {
+			required[req.Mod.Path] = true
+		}
+	}
+	return required
+}
+
 /*
 Determines if the given struct implements the given interface
 */
@@ -64,7 +64,7 @@ func (spec *WorkflowSpec) Parse(modInfo *goparser.ModuleInfo) error {
 // service, this method will ultimately get called.
 //
 // Returns the service and a constructor
-func (spec *WorkflowSpec) get(pkgName, name string, modInfo *goparser.ModuleInfo) (*Service, error) {
+func (spec *WorkflowSpec) get(pkgName, name string, modInfo *goparser.ModuleInfo, opts ServiceOptions) (*Service, error) {
 	// Parse the module
 	mod, err := spec.Modules.Add(modInfo)
 	if err != nil {
@@ -79,10 +79,10 @@ func (spec *WorkflowSpec) get(pkgName, name string, modInfo *goparser.ModuleInfo
 
 	// Return either the interface or struct definition
 	if iface, hasIface := pkg.Interfaces[name]; hasIface {
-		return spec.makeServiceFromInterface(iface)
+		return spec.makeServiceFromInterface(iface, opts)
 	}
 	if struc, hasStruc := pkg.Structs[name]; hasStruc {
-		return spec.makeServiceFromStruct(struc)
+		return spec.makeServiceFromStruct(struc, opts)
 	}
 	return nil, blueprint.Errorf("unable to find service %v in workflow spec", name)
 }
@@ -13,6 +13,10 @@ import (
 	"github.com/blueprint-uservices/blueprint/plugins/goproc"
 )
 
+func init() {
+	ir.RegisterJSONType[*workloadGenerator]()
+}
+
 // Wraps a goproc.Process in order to control its artifact generation
 type workloadGenerator struct {
 	ir.ArtifactGenerator
@@ -0,0 +1,16 @@
+package xtrace
+
+import (
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("xtrace.Instrument", declarative.Apply(Instrument))
+
+	// Logger returns the name of the logger it defines, but the logger is part of the
+	// process, so it shouldn't be instantiated separately
+	declarative.Register("xtrace.Logger", declarative.Apply(func(spec wiring.WiringSpec, processName string) {
+		Logger(spec, processName)
+	}))
+}
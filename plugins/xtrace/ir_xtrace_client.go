@@ -12,6 +12,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*XTraceClient]()
+}
+
 // Blueprint IR Node that represents a client to the Xtrace container
 type XTraceClient struct {
 	golang.Node
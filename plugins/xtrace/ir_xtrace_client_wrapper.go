@@ -14,6 +14,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*XtraceClientWrapper]()
+}
+
 // Blueprint IR Node that wraps the client-side of a service to generate xtrace compatible logs
 type XtraceClientWrapper struct {
 	golang.Service
@@ -21,7 +25,7 @@ type XtraceClientWrapper struct {
 	golang.GeneratesFuncs
 
 	InstanceName  string
-	outputPackage string
+	OutputPackage string
 	Wrapped       golang.Service
 	XTClient      *XTraceClient
 }
@@ -50,7 +54,7 @@ func newXtraceClientWrapper(name string, wrapped ir.IRNode, xtraceClient ir.IRNo
 
 	node := &XtraceClientWrapper{}
 	node.InstanceName = name
-	node.outputPackage = "xtrace"
+	node.OutputPackage = "xtrace"
 	node.Wrapped = serverNode
 	node.XTClient = xtClient
 	return node, nil
@@ -65,7 +69,7 @@ func (node *XtraceClientWrapper) genInterface(ctx ir.BuildContext) (*gocode.Serv
 	if !valid {
 		return nil, blueprint.Errorf("XtraceClientWrapper expected build context to be a ModuleBuilder, got %v", ctx)
 	}
-	i := gocode.CopyServiceInterface(fmt.Sprintf("%v_XTraceClientWrapperInterface", iface.BaseName), module_ctx.Info().Name+"/"+node.outputPackage, iface)
+	i := gocode.CopyServiceInterface(fmt.Sprintf("%v_XTraceClientWrapperInterface", iface.BaseName), module_ctx.Info().Name+"/"+node.OutputPackage, iface)
 	for name, method := range i.Methods {
 		method.Arguments = method.Arguments[:len(method.Arguments)-1]
 		method.Returns = method.Returns[:len(method.Returns)-1]
@@ -90,7 +94,7 @@ func (node *XtraceClientWrapper) AddInstantiation(builder golang.NamespaceBuilde
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_XTraceClientWrapper", iface.BaseName),
 			Arguments: []gocode.Variable{
@@ -120,7 +124,7 @@ func (node *XtraceClientWrapper) GenerateFuncs(builder golang.ModuleBuilder) err
 		return err
 	}
 
-	return generateClientHandler(builder, wrapped_iface, impl_iface, xtrace_iface, node.outputPackage)
+	return generateClientHandler(builder, wrapped_iface, impl_iface, xtrace_iface, node.OutputPackage)
 }
 
 func (node *XtraceClientWrapper) GetInterface(ctx ir.BuildContext) (service.ServiceInterface, error) {
@@ -152,7 +156,7 @@ func generateClientHandler(builder golang.ModuleBuilder, wrapped *gocode.Service
 
 	slog.Info(fmt.Sprintf("Generating %v/%v", server.Package.PackageName, impl.Name))
 	outputFile := filepath.Join(server.Package.Path, impl.Name+".go")
-	return gogen.ExecuteTemplateToFile("XTrace", clientSideTemplate, server, outputFile)
+	return clientSideTemplate.ExecuteToFile(builder, server, outputFile)
 }
 
 type clientArgs struct {
@@ -166,7 +170,7 @@ type clientArgs struct {
 	Imports         *gogen.Imports
 }
 
-var clientSideTemplate = `// Blueprint: Auto-generated by XTrace Plugin
+var clientSideTemplate = gogen.RegisterTemplate("xtrace", "clientSide", `// Blueprint: Auto-generated by XTrace Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -208,4 +212,4 @@ func (handler *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.C
 	return
 }
 {{end}}
-`
+`)
@@ -12,6 +12,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*XTraceLogger]()
+}
+
 // Blueprint IR Node that represents a process-level xtrace logger
 type XTraceLogger struct {
 	golang.Node
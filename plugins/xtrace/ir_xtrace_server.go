@@ -10,6 +10,11 @@ import (
 	"github.com/blueprint-uservices/blueprint/runtime/plugins/xtrace"
 )
 
+func init() {
+	ir.RegisterJSONType[*XTraceServerContainer]()
+	ir.RegisterJSONType[*address.Address[*XTraceServerContainer]]()
+}
+
 // Blueprint IR Node that represents the Xtrace container
 type XTraceServerContainer struct {
 	docker.Container
@@ -14,6 +14,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*XtraceServerWrapper]()
+}
+
 // Blueprint IR Node that wraps the server-side of a service to generate xtrace compatible logs
 type XtraceServerWrapper struct {
 	golang.Service
@@ -21,7 +25,7 @@ type XtraceServerWrapper struct {
 	golang.GeneratesFuncs
 
 	InstanceName  string
-	outputPackage string
+	OutputPackage string
 	Wrapped       golang.Service
 	XTClient      *XTraceClient
 }
@@ -50,7 +54,7 @@ func newXtraceServerWrapper(name string, wrapped ir.IRNode, xtraceClient ir.IRNo
 
 	node := &XtraceServerWrapper{}
 	node.InstanceName = name
-	node.outputPackage = "xtrace"
+	node.OutputPackage = "xtrace"
 	node.Wrapped = serverNode
 	node.XTClient = xtClient
 	return node, nil
@@ -65,7 +69,7 @@ func (node *XtraceServerWrapper) genInterface(ctx ir.BuildContext) (*gocode.Serv
 	if !valid {
 		return nil, blueprint.Errorf("XtraceServerWrapper expected build context to be a ModuleBuilder, got %v", ctx)
 	}
-	i := gocode.CopyServiceInterface(fmt.Sprintf("%v_XTraceServerWrapperInterface", iface.BaseName), module_ctx.Info().Name+"/"+node.outputPackage, iface)
+	i := gocode.CopyServiceInterface(fmt.Sprintf("%v_XTraceServerWrapperInterface", iface.BaseName), module_ctx.Info().Name+"/"+node.OutputPackage, iface)
 	for name, method := range i.Methods {
 		method.AddArgument(gocode.Variable{Name: "baggage", Type: &gocode.BasicType{Name: "string"}})
 		method.AddRetVar(gocode.Variable{Name: "", Type: &gocode.BasicType{Name: "string"}})
@@ -90,7 +94,7 @@ func (node *XtraceServerWrapper) AddInstantiation(builder golang.NamespaceBuilde
 	}
 
 	constructor := &gocode.Constructor{
-		Package: builder.Module().Info().Name + "/" + node.outputPackage,
+		Package: builder.Module().Info().Name + "/" + node.OutputPackage,
 		Func: gocode.Func{
 			Name: fmt.Sprintf("New_%v_XTraceServerWrapper", iface.BaseName),
 			Arguments: []gocode.Variable{
@@ -120,7 +124,7 @@ func (node *XtraceServerWrapper) GenerateFuncs(builder golang.ModuleBuilder) err
 		return err
 	}
 
-	return generateServerHandler(builder, wrapped_iface, impl_iface, xtrace_iface, node.outputPackage)
+	return generateServerHandler(builder, wrapped_iface, impl_iface, xtrace_iface, node.OutputPackage)
 }
 
 func (node *XtraceServerWrapper) GetInterface(ctx ir.BuildContext) (service.ServiceInterface, error) {
@@ -133,7 +137,7 @@ func (node *XtraceServerWrapper) AddInterfaces(builder golang.ModuleBuilder) err
 		return err
 	}
 
-	err = generateClientSideInterfaces(builder, iface, node.outputPackage)
+	err = generateClientSideInterfaces(builder, iface, node.OutputPackage)
 	if err != nil {
 		return err
 	}
@@ -161,7 +165,7 @@ func generateServerHandler(builder golang.ModuleBuilder, wrapped *gocode.Service
 
 	slog.Info(fmt.Sprintf("Generating %v/%v", server.Package.PackageName, impl.Name))
 	outputFile := filepath.Join(server.Package.Path, impl.Name+".go")
-	return gogen.ExecuteTemplateToFile("XTrace", serverTemplate, server, outputFile)
+	return serverTemplate.ExecuteToFile(builder, server, outputFile)
 }
 
 type serverArgs struct {
@@ -191,10 +195,10 @@ func generateClientSideInterfaces(builder golang.ModuleBuilder, iface *gocode.Se
 
 	slog.Info(fmt.Sprintf("Generating %v/%v", server.Package.PackageName, iface.Name))
 	outputFile := filepath.Join(server.Package.Path, iface.Name+".go")
-	return gogen.ExecuteTemplateToFile("XTrace", clientTemplate, server, outputFile)
+	return clientTemplate.ExecuteToFile(builder, server, outputFile)
 }
 
-var serverTemplate = `// Blueprint: Auto-generated by XTrace Plugin
+var serverTemplate = gogen.RegisterTemplate("xtrace", "server", `// Blueprint: Auto-generated by XTrace Plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -241,8 +245,8 @@ func (handler *{{$receiver}}) {{$f.Name -}} ({{ArgVarsAndTypes $f "ctx context.C
 	return
 }
 {{end}}
-`
-var clientTemplate = `// Blueprint: Auto-generated by XTrace plugin
+`)
+var clientTemplate = gogen.RegisterTemplate("xtrace", "client", `// Blueprint: Auto-generated by XTrace plugin
 package {{.Package.ShortName}}
 
 {{.Imports}}
@@ -252,4 +256,4 @@ type {{.IfaceName}} interface {
 	{{Signature $f}}
 	{{end}}
 }
-`
+`)
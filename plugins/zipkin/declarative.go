@@ -0,0 +1,9 @@
+package zipkin
+
+import (
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+)
+
+func init() {
+	declarative.Register("zipkin.Collector", declarative.Define(Collector))
+}
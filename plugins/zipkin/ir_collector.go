@@ -10,6 +10,11 @@ import (
 	"github.com/blueprint-uservices/blueprint/runtime/plugins/zipkin"
 )
 
+func init() {
+	ir.RegisterJSONType[*ZipkinCollectorContainer]()
+	ir.RegisterJSONType[*address.Address[*ZipkinCollectorContainer]]()
+}
+
 // Blueprint IR node that represents the Zipkin container
 type ZipkinCollectorContainer struct {
 	docker.Container
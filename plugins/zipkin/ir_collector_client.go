@@ -12,6 +12,10 @@ import (
 	"golang.org/x/exp/slog"
 )
 
+func init() {
+	ir.RegisterJSONType[*ZipkinCollectorClient]()
+}
+
 // Blueprint IR node representing a client to the zipkin container
 type ZipkinCollectorClient struct {
 	golang.Node
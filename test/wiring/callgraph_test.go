@@ -0,0 +1,44 @@
+package wiring
+
+import (
+	"testing"
+
+	"github.com/blueprint-uservices/blueprint/plugins/grpc"
+	"github.com/blueprint-uservices/blueprint/plugins/passes/callgraph"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+	wf "github.com/blueprint-uservices/blueprint/test/workflow/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+Tests for extracting the call graph between workflow services
+*/
+
+func TestCallGraph(t *testing.T) {
+	spec := newWiringSpec("TestCallGraph")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+	grpc.Deploy(spec, leaf)
+
+	app := assertBuildSuccess(t, spec, nonleaf)
+
+	pass := callgraph.NewCallGraphPass("")
+	_, err := pass.Analyze(spec, app)
+	require.NoError(t, err)
+	g := pass.Graph
+
+	assert.Contains(t, g.Methods, callgraph.Method{Service: "nonleaf", Method: "Hello"})
+	assert.Contains(t, g.Methods, callgraph.Method{Service: "leaf", Method: "HelloInt"})
+
+	calls := g.Callees("nonleaf", "Hello")
+	require.Len(t, calls, 2)
+	assert.Equal(t, callgraph.Method{Service: "leaf", Method: "HelloInt"}, calls[0].Callee)
+	assert.Equal(t, callgraph.Method{Service: "leaf", Method: "HelloObject"}, calls[1].Callee)
+	assert.Equal(t, "leaf", calls[0].Field)
+	assert.Equal(t, "workflow/services.go:96", calls[0].Position)
+
+	assert.Empty(t, g.Callees("leaf", "HelloInt"))
+	assert.Len(t, g.Reachable("nonleaf", "Hello"), 2)
+}
@@ -0,0 +1,223 @@
+package wiring
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+	"github.com/blueprint-uservices/blueprint/plugins/golang"
+	"github.com/blueprint-uservices/blueprint/plugins/golang/gocode"
+	"github.com/blueprint-uservices/blueprint/plugins/golang/gogen"
+	"github.com/blueprint-uservices/blueprint/plugins/goproc"
+	"github.com/blueprint-uservices/blueprint/plugins/grpc"
+	"github.com/blueprint-uservices/blueprint/plugins/grpc/grpccodegen"
+	"github.com/blueprint-uservices/blueprint/plugins/retries"
+	"github.com/blueprint-uservices/blueprint/plugins/thrift/thriftcodegen"
+	"github.com/blueprint-uservices/blueprint/plugins/timeouts"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow/workflowspec"
+	wf "github.com/blueprint-uservices/blueprint/test/workflow/workflow"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+Tests for the GRPC and Thrift code that is generated for workflow services, and for the clients that wrap it.
+
+protoc and thrift aren't invoked, so these tests check the generated .proto and .thrift files and the
+conversions between golang and GRPC or Thrift types, rather than compiling them.
+*/
+
+type generatedIDL struct {
+	Proto, ProtoConversions   string
+	Thrift, ThriftConversions string
+}
+
+// Writes the GRPC and Thrift declarations of service interface T, and their conversion code
+func generateIDL[T any](t *testing.T) generatedIDL {
+	iface, module := newIDLModule[T](t)
+
+	var idl generatedIDL
+	idl.Proto, idl.ProtoConversions = writeProto(t, iface, module)
+
+	thriftFile, err := thriftcodegen.WriteThrift(module, iface, "thrift")
+	require.NoError(t, err)
+	idl.Thrift = readGenerated(t, thriftFile)
+	idl.ThriftConversions = readGenerated(t, thriftFile[:len(thriftFile)-len(".thrift")]+"_conversions.go")
+	return idl
+}
+
+// Writes only the GRPC declarations of service interface T, for services that Thrift cannot serialize
+func generateProto[T any](t *testing.T) generatedIDL {
+	iface, module := newIDLModule[T](t)
+
+	var idl generatedIDL
+	idl.Proto, idl.ProtoConversions = writeProto(t, iface, module)
+	return idl
+}
+
+func newIDLModule[T any](t *testing.T) (*gocode.ServiceInterface, golang.ModuleBuilder) {
+	service, err := workflowspec.GetService[T]()
+	require.NoError(t, err)
+
+	workspace, err := gogen.NewWorkspaceBuilder(t.TempDir())
+	require.NoError(t, err)
+	module, err := gogen.NewModuleBuilder(workspace, "example.com/generated")
+	require.NoError(t, err)
+	return service.Iface.ServiceInterface(nil), module
+}
+
+func writeProto(t *testing.T, iface *gocode.ServiceInterface, module golang.ModuleBuilder) (string, string) {
+	protoFile, err := grpccodegen.WriteGRPCProto(module, iface, "grpc")
+	require.NoError(t, err)
+	return readGenerated(t, protoFile), readGenerated(t, protoFile[:len(protoFile)-len(".proto")]+"_conversions.go")
+}
+
+func readGenerated(t *testing.T, filename string) string {
+	code, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	return string(code)
+}
+
+func TestGenericServiceCodegen(t *testing.T) {
+	idl := generateIDL[wf.TestGenericService](t)
+
+	// Each instantiation of a generic struct gets its own message, named after the package and type arguments
+	assert.Contains(t, idl.Proto, "message TestGenericService_Workflow_TestPage_Int64 {\n    repeated sint64 Items = 1;\n    sint64 Next = 2;\n}")
+	assert.Contains(t, idl.Proto, "message TestGenericService_Workflow_TestPage_Workflow_TestLeafObject {\n    repeated TestGenericService_Workflow_TestLeafObject Items = 1;\n    sint64 Next = 2;\n}")
+	assert.Contains(t, idl.Proto, "message TestGenericService_Workflow_TestPair_String_Workflow_TestLeafObject {\n    string Key = 1;\n    TestGenericService_Workflow_TestLeafObject Value = 2;\n}")
+	assert.Contains(t, idl.Proto, "TestGenericService_Workflow_TestPair_String_Workflow_TestLeafObject ret0 = 1;")
+	assert.Contains(t, idl.ProtoConversions, "func (msg *TestGenericService_Workflow_TestPage_Int64) marshall(obj *workflow.TestPage[int64]) *TestGenericService_Workflow_TestPage_Int64 {")
+	assert.Contains(t, idl.ProtoConversions, "func (msg *TestGenericService_Workflow_TestPage_Workflow_TestLeafObject) unmarshall(obj *workflow.TestPage[workflow.TestLeafObject]) {")
+	assert.Contains(t, idl.ProtoConversions, "ret0 = new(workflow.TestPair[string, workflow.TestLeafObject])")
+
+	assert.Contains(t, idl.Thrift, "struct Workflow_TestPage_Int64 {\n\t1: list<i64> Items,\n\t2: i64 Next,\n}")
+	assert.Contains(t, idl.Thrift, "struct Workflow_TestPair_String_Workflow_TestLeafObject {\n\t1: string Key,\n\t2: Workflow_TestLeafObject Value,\n}")
+	assert.Contains(t, idl.ThriftConversions, "func marshall_testgenericservice_Workflow_TestPage_Workflow_TestLeafObject(msg *testgenericservice.Workflow_TestPage_Workflow_TestLeafObject, obj *workflow.TestPage[workflow.TestLeafObject])")
+}
+
+func TestNamedTypesCodegen(t *testing.T) {
+	idl := generateProto[wf.TestTaskService](t)
+
+	// Enums are named after the package of their type, and their first value is zero
+	assert.Contains(t, idl.Proto, "enum TestTaskService_Workflow_TestStatus {\n"+
+		"    TestTaskService_Workflow_TestStatus_TestStatusPending = 0;\n"+
+		"    TestTaskService_Workflow_TestStatus_TestStatusRunning = 1;\n"+
+		"    TestTaskService_Workflow_TestStatus_TestStatusDone = 2;\n"+
+		"    TestTaskService_Workflow_TestStatus_TestStatusFailed = -1;\n}")
+
+	// Constants with the same value are aliases, including constants that refer to constants declared after them
+	assert.Contains(t, idl.Proto, "enum TestTaskService_Workflow_TestPriority {\n"+
+		"    option allow_alias = true;\n"+
+		"    TestTaskService_Workflow_TestPriority_UNSPECIFIED = 0;\n"+
+		"    TestTaskService_Workflow_TestPriority_TestPriorityDefault = 1;\n"+
+		"    TestTaskService_Workflow_TestPriority_TestPriorityLow = 1;\n"+
+		"    TestTaskService_Workflow_TestPriority_TestPriorityHigh = 2;\n"+
+		"    TestTaskService_Workflow_TestPriority_TestPriorityUrgent = 2;\n}")
+	assert.Contains(t, idl.ProtoConversions, "msg.Priority = TestTaskService_Workflow_TestPriority(priority)")
+	assert.Contains(t, idl.ProtoConversions, "priority = workflow.TestPriority(msg.Priority)")
+
+	// Named maps and slices are wrapped in messages, which can be nested
+	assert.Contains(t, idl.Proto, "message TestTaskService_Workflow_TestLabels {\n    map<string,TestTaskService_Workflow_TestTags> Value = 1;\n}")
+	assert.Contains(t, idl.Proto, "message TestTaskService_Workflow_TestTags {\n    repeated string Value = 1;\n}")
+	assert.Contains(t, idl.ProtoConversions, "msg.Labels = new(TestTaskService_Workflow_TestLabels).marshall(&labels)")
+	assert.Contains(t, idl.ProtoConversions, "x0 = new(TestTaskService_Workflow_TestTags).marshall(&v0)")
+	assert.Contains(t, idl.ProtoConversions, "if v0 != nil { v0.unmarshall(&x0) }")
+
+	// Enum map keys are serialized like their underlying type and converted back
+	assert.Contains(t, idl.Proto, "map<sint64,sint64> ret0 = 1;")
+	assert.Contains(t, idl.ProtoConversions, "msg.Ret0[int64(k0)] = x0")
+	assert.Contains(t, idl.ProtoConversions, "ret0[workflow.TestStatus(k0)] = x0")
+}
+
+func TestTimeoutsOnStreamingService(t *testing.T) {
+	spec := newWiringSpec("TestTimeoutsOnStreamingService")
+
+	feed := workflow.Service[wf.TestFeedService](spec, "feed")
+	timeouts.Add(spec, feed, "10ms")
+	grpc.Deploy(spec, feed)
+	feedProc := goproc.Deploy(spec, feed)
+	client := goproc.CreateClientProcess(spec, "feedclient", feed)
+
+	app := assertBuildSuccess(t, spec, feedProc, client)
+
+	assertIR(t, app,
+		`TestTimeoutsOnStreamingService = BlueprintApplication() {
+			feed.grpc.addr
+			feed.grpc.bind_addr = AddressConfig()
+			feed.grpc.dial_addr = AddressConfig()
+			feed.handler.visibility
+			feed_proc = GolangProcessNode(feed.grpc.bind_addr) {
+			  feed = TestFeedService()
+			  feed.grpc_server = GRPCServer(feed, feed.grpc.bind_addr)
+			  feed_proc.logger = SLogger()
+			  feed_proc.stdoutmetriccollector = StdoutMetricCollector()
+			}
+			feedclient = GolangProcessNode(feed.grpc.dial_addr) {
+			  feed.client = feed.client.timeout
+			  feed.client.timeout = TimeoutClient(feed.grpc_client)
+			  feed.grpc_client = GRPCClient(feed.grpc.dial_addr)
+			}
+		  }`)
+
+	// The streams outlive the call, so the timeout client passes streaming calls through without a deadline
+	timeoutClients := ir.Filter[*timeouts.TimeoutClient](app.GetAllIRNodes())
+	require.Len(t, timeoutClients, 1)
+
+	workspace, err := gogen.NewWorkspaceBuilder(t.TempDir())
+	require.NoError(t, err)
+	module, err := gogen.NewModuleBuilder(workspace, "example.com/generated")
+	require.NoError(t, err)
+	require.NoError(t, timeoutClients[0].GenerateFuncs(module))
+
+	code, err := os.ReadFile(filepath.Join(module.Info().Path, "timeouts", "TestFeedService_TimeoutClient.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(code), "return client.Client.Subscribe(ctx, topic)")
+	assert.Contains(t, string(code), "return client.Client.Publish(ctx, topic, objects)")
+	assert.Contains(t, string(code), "return client.Client.Format(ctx, ids)")
+	assert.NotContains(t, string(code), "WithDeadline")
+}
+
+func TestRetriesOnStreamingService(t *testing.T) {
+	spec := newWiringSpec("TestRetriesOnStreamingService")
+
+	// One service for each kind of retrier
+	var feeds []string
+	for _, name := range []string{"plain", "fixed_delay", "backoff", "rate_limited", "token_bucket"} {
+		feeds = append(feeds, workflow.Service[wf.TestFeedService](spec, name))
+	}
+	retries.AddRetries(spec, feeds[0], 3)
+	retries.AddRetriesWithFixedDelay(spec, feeds[1], 3, "10ms")
+	retries.AddRetriesWithExponentialBackoff(spec, feeds[2], "10ms", "1s", true)
+	retries.AddRetriesRetryRateLimit(spec, feeds[3], 3, 10)
+	retries.AddRetriesTokenBucket(spec, feeds[4], 10, 1, 0.05)
+
+	app := assertBuildSuccess(t, spec, feeds...)
+
+	workspace, err := gogen.NewWorkspaceBuilder(t.TempDir())
+	require.NoError(t, err)
+	module, err := gogen.NewModuleBuilder(workspace, "example.com/generated")
+	require.NoError(t, err)
+	retriers := 0
+	for _, node := range app.GetAllIRNodes() {
+		if retrier, isRetrier := node.(golang.GeneratesFuncs); isRetrier && strings.Contains(node.Name(), ".client.retrier") {
+			require.NoError(t, retrier.GenerateFuncs(module))
+			retriers++
+		}
+	}
+	require.Equal(t, 5, retriers)
+
+	// A retry can't replay the streams, so every retrier passes streaming calls straight through
+	for _, client := range []string{"RetrierClient", "RetrierFixedDelayClient", "RetrierExpBackoffClient", "RetrierRateLimiterClient", "RetrierTokenBucketClient"} {
+		code, err := os.ReadFile(filepath.Join(module.Info().Path, "retries", "TestFeedService_"+client+".go"))
+		require.NoError(t, err)
+		assert.Contains(t, string(code), "return client.Client.Subscribe(ctx, topic)", client)
+		assert.Contains(t, string(code), "return client.Client.Publish(ctx, topic, objects)", client)
+		assert.Contains(t, string(code), "return client.Client.Format(ctx, ids)", client)
+		assert.NotContains(t, string(code), "attempt_num", client)
+		assert.NotContains(t, string(code), "\"math\"", client)
+	}
+}
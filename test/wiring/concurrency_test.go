@@ -0,0 +1,95 @@
+package wiring
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+	"github.com/blueprint-uservices/blueprint/plugins/golang/goparser"
+	"github.com/blueprint-uservices/blueprint/plugins/goproc"
+	"github.com/blueprint-uservices/blueprint/plugins/http"
+	"github.com/blueprint-uservices/blueprint/plugins/linuxcontainer"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+	wf "github.com/blueprint-uservices/blueprint/test/workflow/workflow"
+
+	"github.com/stretchr/testify/require"
+)
+
+/*
+Tests that generating the artifacts of several processes at once is safe.  The processes share the
+parsed workflow modules, so these tests are most useful when run with -race.
+*/
+
+// Builds an application with several processes that can be generated concurrently
+func buildProcesses(t *testing.T, name string) (*ir.ApplicationNode, []string) {
+	spec := newWiringSpec(name)
+
+	var procs []string
+	for _, name := range []string{"leaf1", "leaf2", "leaf3"} {
+		leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, name)
+		http.Deploy(spec, leaf)
+		procs = append(procs, goproc.Deploy(spec, leaf))
+	}
+
+	return assertBuildSuccess(t, spec, procs...), procs
+}
+
+func TestGenerateProcessesConcurrently(t *testing.T) {
+	app, procs := buildProcesses(t, "TestGenerateProcessesConcurrently")
+
+	// Floating processes are generated into a default linux container
+	restoreBuilders(t)
+	linuxcontainer.RegisterAsDefaultBuilder()
+	ir.SetBuildParallelism(len(procs))
+
+	outputDir := t.TempDir()
+	require.NoError(t, app.GenerateArtifacts(outputDir))
+	for _, proc := range procs {
+		_, err := os.Stat(filepath.Join(outputDir, "linux", proc))
+		require.NoError(t, err, "missing artifacts for %v", proc)
+	}
+}
+
+func TestGenerateConcurrentlyMatchesSequentially(t *testing.T) {
+	app, procs := buildProcesses(t, "TestGenerateConcurrentlyMatchesSequentially")
+
+	restoreBuilders(t)
+	linuxcontainer.RegisterAsDefaultBuilder()
+
+	sequentialDir := t.TempDir()
+	ir.SetBuildParallelism(1)
+	require.NoError(t, app.GenerateArtifacts(sequentialDir))
+
+	concurrentDir := t.TempDir()
+	ir.SetBuildParallelism(len(procs))
+	require.NoError(t, app.GenerateArtifacts(concurrentDir))
+
+	sequential := readTree(t, sequentialDir)
+	require.NotEmpty(t, sequential)
+	require.Equal(t, sequential, readTree(t, concurrentDir))
+}
+
+func TestParseModulesConcurrently(t *testing.T) {
+	// Artifact generators share the root module set, which parses and adds modules as they are looked up
+	modules := goparser.New(nil)
+
+	ifaces := make([]*goparser.ParsedInterface, 4)
+	errs := make([]error, len(ifaces))
+	var wg sync.WaitGroup
+	for i := range ifaces {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ifaces[i], errs[i] = modules.FindInterface("github.com/blueprint-uservices/blueprint/test/workflow/workflow", "TestLeafService")
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range ifaces {
+		require.NoError(t, errs[i])
+		require.NotNil(t, ifaces[i])
+		require.Same(t, ifaces[0], ifaces[i])
+	}
+}
@@ -0,0 +1,90 @@
+package wiring
+
+import (
+	"testing"
+
+	"github.com/blueprint-uservices/blueprint/plugins/declarative"
+	"github.com/blueprint-uservices/blueprint/plugins/grpc"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+	wf "github.com/blueprint-uservices/blueprint/test/workflow/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+Tests for loading wiring specs from YAML and JSON
+*/
+
+const declarativeYAML = `
+steps:
+  - call: workflow.Service
+    name: leaf
+    options: {type: github.com/blueprint-uservices/blueprint/test/workflow/workflow.TestLeafServiceImpl}
+  - call: workflow.Service
+    name: nonleaf
+    args: [leaf]
+    options: {type: github.com/blueprint-uservices/blueprint/test/workflow/workflow.TestNonLeafService}
+  - call: grpc.Deploy
+    apply: [leaf]
+`
+
+func TestDeclarativeYAML(t *testing.T) {
+	d, err := declarative.ParseYAML([]byte(declarativeYAML))
+	require.NoError(t, err)
+
+	spec := newWiringSpec("TestDeclarative")
+	nodes, err := d.Apply(spec)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nonleaf"}, nodes)
+	app := assertBuildSuccess(t, spec, nodes...)
+
+	// The same spec written in Go
+	gospec := newWiringSpec("TestDeclarative")
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](gospec, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](gospec, "nonleaf", leaf)
+	grpc.Deploy(gospec, leaf)
+	goapp := assertBuildSuccess(t, gospec, nonleaf)
+
+	assert.Equal(t, goapp.String(), app.String())
+}
+
+func TestDeclarativeJSON(t *testing.T) {
+	d, err := declarative.ParseJSON([]byte(`{
+		"instantiate": ["leaf"],
+		"steps": [
+			{"call": "workflow.Service", "name": "leaf", "options": {"type": "github.com/blueprint-uservices/blueprint/test/workflow/workflow.TestLeafServiceImpl"}},
+			{"call": "retries.AddRetries", "apply": ["leaf"], "options": {"max_retries": 3}}
+		]
+	}`))
+	require.NoError(t, err)
+
+	spec := newWiringSpec("TestDeclarativeJSON")
+	nodes, err := d.Apply(spec)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"leaf"}, nodes)
+	assertBuildSuccess(t, spec, nodes...)
+}
+
+func TestDeclarativeErrors(t *testing.T) {
+	d, err := declarative.ParseYAML([]byte(`
+steps:
+  - call: grpc.Deploy
+    apply: [leaf]
+  - call: nosuch.Function
+`))
+	require.NoError(t, err)
+	_, err = d.Apply(newWiringSpec("TestDeclarativeErrors"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "step 2")
+
+	d, err = declarative.ParseYAML([]byte(`
+steps:
+  - call: retries.AddRetries
+    apply: [leaf]
+    options: {max_retries: lots}
+`))
+	require.NoError(t, err)
+	_, err = d.Apply(newWiringSpec("TestDeclarativeErrors"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max_retries")
+}
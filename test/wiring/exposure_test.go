@@ -0,0 +1,92 @@
+package wiring
+
+import (
+	"testing"
+
+	"github.com/blueprint-uservices/blueprint/plugins/goproc"
+	"github.com/blueprint-uservices/blueprint/plugins/grpc"
+	"github.com/blueprint-uservices/blueprint/plugins/healthchecker"
+	"github.com/blueprint-uservices/blueprint/plugins/mongodb"
+	"github.com/blueprint-uservices/blueprint/plugins/passes/exposure"
+	"github.com/blueprint-uservices/blueprint/plugins/simple"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+	"github.com/blueprint-uservices/blueprint/test/workflow/nosqldb"
+	wf "github.com/blueprint-uservices/blueprint/test/workflow/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExposureUnprotectedServers(t *testing.T) {
+	spec := newWiringSpec("TestExposureUnprotectedServers")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+
+	// The healthcheck wrapper stands in for a protection wrapper
+	healthchecker.AddHealthCheckAPI(spec, nonleaf)
+	grpc.Deploy(spec, leaf)
+	grpc.Deploy(spec, nonleaf)
+
+	app := assertBuildSuccess(t, spec, leaf, nonleaf)
+
+	{
+		pass := exposure.NewExposurePass("", "")
+		_, err := pass.Analyze(spec, app)
+		require.NoError(t, err)
+		require.Len(t, pass.Report.Findings, 2)
+
+		f := pass.Report.Findings[0]
+		assert.Equal(t, exposure.UnprotectedServer, f.Kind)
+		assert.Equal(t, "leaf.grpc_server", f.Subject)
+		assert.Equal(t, "leaf.grpc.bind_addr", f.Address)
+		assert.Empty(t, f.Wrappers)
+
+		f = pass.Report.Findings[1]
+		assert.Equal(t, "nonleaf.grpc_server", f.Subject)
+		assert.Equal(t, []string{"nonleaf.server.hc"}, f.Wrappers)
+	}
+	{
+		pass := exposure.NewExposurePass("", "", &healthchecker.HealthCheckerServerWrapper{})
+		pass.Strict = true
+		_, err := pass.Analyze(spec, app)
+		assert.Error(t, err)
+		require.Len(t, pass.Report.Findings, 1)
+		assert.Equal(t, "leaf.grpc_server", pass.Report.Findings[0].Subject)
+	}
+}
+
+func TestExposureSharedBackend(t *testing.T) {
+	spec := newWiringSpec("TestExposureSharedBackend")
+
+	// Only the database is shared; each leaf has its own in-process cache
+	db := mongodb.Container(spec, "shared_db")
+	cache1 := simple.Cache(spec, "cache1")
+	cache2 := simple.Cache(spec, "cache2")
+	leaf1 := workflow.Service[*nosqldb.TestLeafServiceImplWithDB](spec, "leaf1", cache1, db)
+	leaf2 := workflow.Service[*nosqldb.TestLeafServiceImplWithDB](spec, "leaf2", cache2, db)
+	goproc.Deploy(spec, leaf1)
+	goproc.Deploy(spec, leaf2)
+
+	app := assertBuildSuccess(t, spec, leaf1+"_proc", leaf2+"_proc")
+
+	pass := exposure.NewExposurePass("", "")
+	_, err := pass.Analyze(spec, app)
+	require.NoError(t, err)
+	require.Len(t, pass.Report.Findings, 2)
+
+	f := pass.Report.Findings[0]
+	assert.Equal(t, exposure.SharedBackend, f.Kind)
+	assert.Equal(t, "shared_db.ctr", f.Subject)
+	assert.Equal(t, []string{"leaf1", "leaf2"}, f.UsedBy)
+
+	// The database itself is also reachable from both processes without protection
+	f = pass.Report.Findings[1]
+	assert.Equal(t, exposure.UnprotectedServer, f.Kind)
+	assert.Equal(t, "shared_db.ctr", f.Subject)
+	assert.Equal(t, []string{"leaf1_proc", "leaf2_proc"}, f.DialedFrom)
+
+	pass.MaxBackendUsers = 2
+	_, err = pass.Analyze(spec, app)
+	require.NoError(t, err)
+	assert.Len(t, pass.Report.Findings, 1)
+}
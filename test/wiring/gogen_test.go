@@ -0,0 +1,124 @@
+package wiring
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+	"github.com/blueprint-uservices/blueprint/plugins/golang/gogen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generateModule(t *testing.T, templateName string, body string) (*gogen.WorkspaceBuilderImpl, string) {
+	workspace, err := gogen.NewWorkspaceBuilder(t.TempDir())
+	require.NoError(t, err)
+	moduleDir, err := workspace.CreateModule("example.com/generated", "v0.0.0")
+	require.NoError(t, err)
+	filename := filepath.Join(moduleDir, "main.go")
+	require.NoError(t, gogen.ExecuteTemplateToFile(templateName, body, nil, filename))
+	return workspace, filename
+}
+
+func TestGeneratedCodeIsFormatted(t *testing.T) {
+	workspace, filename := generateModule(t, "unformatted", "package main\nfunc main()  {\n  println( \"hello\" )\n}\n")
+	require.NoError(t, workspace.Finish())
+
+	code, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n", string(code))
+}
+
+func TestGeneratedCodeSyntaxError(t *testing.T) {
+	workspace, _ := generateModule(t, "broken", "package main\nfunc main() {\n")
+	err := workspace.Finish()
+	assert.ErrorContains(t, err, "main.go:2:15: expected '}', found 'EOF' (generated by template broken)")
+}
+
+func TestGeneratedCodeTypeCheck(t *testing.T) {
+	gogen.SetTypeCheck(true)
+	defer gogen.SetTypeCheck(false)
+
+	workspace, _ := generateModule(t, "untyped", "package main\n\nfunc main() {\n\tprintln(undefinedVar)\n}\n")
+	err := workspace.Finish()
+	assert.ErrorContains(t, err, "main.go:4:10: undefined: undefinedVar (generated by template untyped)")
+}
+
+// Imports example.com/dep in module, so that go mod tidy keeps its requirement
+func importDep(t *testing.T, module *gogen.ModuleBuilderImpl) {
+	code := "package main\n\nimport _ \"example.com/dep\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(module.ModuleDir, "main.go"), []byte(code), 0644))
+}
+
+func TestModuleVersionConflict(t *testing.T) {
+	workspace, err := gogen.NewWorkspaceBuilder(t.TempDir())
+	require.NoError(t, err)
+	a, err := gogen.NewModuleBuilder(workspace, "example.com/a")
+	require.NoError(t, err)
+	b, err := gogen.NewModuleBuilder(workspace, "example.com/b")
+	require.NoError(t, err)
+
+	importDep(t, a)
+	importDep(t, b)
+
+	require.NoError(t, a.Require("example.com/dep", "v1.2.0"))
+	require.NoError(t, b.Require("example.com/dep", "v1.3.0"))
+	require.NoError(t, a.Require("example.com/dep", "v1.1.0"))
+	require.NoError(t, workspace.Finish())
+
+	// The highest required version is used by every module
+	for _, module := range []*gogen.ModuleBuilderImpl{a, b} {
+		modfile, err := os.ReadFile(filepath.Join(module.ModuleDir, "go.mod"))
+		require.NoError(t, err)
+		assert.Contains(t, string(modfile), "require example.com/dep v1.3.0\n")
+		assert.Equal(t, 1, strings.Count(string(modfile), "example.com/dep"))
+	}
+
+	reqs := workspace.Requirements["example.com/dep"]
+	require.Len(t, reqs, 3)
+	assert.Equal(t, "v1.2.0", reqs[0].Version)
+	assert.Contains(t, reqs[0].RequiredBy, "example.com/a")
+	assert.Contains(t, reqs[1].RequiredBy, "example.com/b")
+}
+
+func TestModuleVersionPin(t *testing.T) {
+	spec := newWiringSpec("TestModuleVersionPin")
+	gogen.PinModuleVersion(spec, "example.com/dep", "v1.0.0")
+	require.NoError(t, spec.Err())
+
+	// The pins are saved in the IR, and configure the workspaces that are generated for the spec
+	app := assertBuildSuccess(t, spec, gogen.OptionsName)
+	options := ir.Filter[*gogen.Options](app.GetAllIRNodes())
+	require.Len(t, options, 1)
+	assert.Equal(t, map[string]string{"example.com/dep": "v1.0.0"}, options[0].ModulePins)
+
+	workspace, err := gogen.NewWorkspaceBuilder(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, options[0].AddToWorkspace(workspace))
+	a, err := gogen.NewModuleBuilder(workspace, "example.com/a")
+	require.NoError(t, err)
+	importDep(t, a)
+	require.NoError(t, a.Require("example.com/dep", "v1.2.0"))
+	require.NoError(t, workspace.Finish())
+
+	modfile, err := os.ReadFile(filepath.Join(a.ModuleDir, "go.mod"))
+	require.NoError(t, err)
+	assert.Contains(t, string(modfile), "require example.com/dep v1.0.0\n")
+
+	workfile, err := os.ReadFile(filepath.Join(workspace.WorkspaceDir, "go.work"))
+	require.NoError(t, err)
+	assert.Contains(t, string(workfile), "replace example.com/dep => example.com/dep v1.0.0")
+}
+
+func TestModuleVersionPinErrors(t *testing.T) {
+	spec := newWiringSpec("TestModuleVersionPinErrors")
+	gogen.PinModuleVersion(spec, "example.com/other", "latest")
+	assert.ErrorContains(t, spec.Err(), "cannot pin module example.com/other to version latest because it is not a valid semantic version")
+
+	spec = newWiringSpec("TestModuleVersionPinErrors")
+	gogen.PinModuleVersion(spec, "example.com/dep", "v1.0.0")
+	gogen.PinModuleVersion(spec, "example.com/dep", "v1.1.0")
+	assert.ErrorContains(t, spec.Err(), "cannot pin module example.com/dep to version v1.1.0 because it is already pinned to version v1.0.0")
+}
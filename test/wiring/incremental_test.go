@@ -0,0 +1,191 @@
+package wiring
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint/ioutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Incrementally generates files, keyed by slash-separated path, with the given contents to outputDir
+func generateFiles(outputDir string, files map[string]string) (*ioutil.GenerateStats, error) {
+	return ioutil.GenerateIncrementally(outputDir, func(stagingDir string) error {
+		for path, contents := range files {
+			dst := filepath.Join(stagingDir, filepath.FromSlash(path))
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(dst, []byte(contents), 0644); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Sets the modification time of the file at path to an hour ago
+func backdate(t *testing.T, path string) time.Time {
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(path, old, old))
+	return old
+}
+
+func modTime(t *testing.T, path string) time.Time {
+	info, err := os.Lstat(path)
+	require.NoError(t, err)
+	return info.ModTime()
+}
+
+func TestIncrementalUnchangedFilesKeepModTime(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "build")
+
+	stats, err := generateFiles(out, map[string]string{"a.txt": "a", "sub/b.txt": "b"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a.txt", filepath.Join("sub", "b.txt")}, stats.Written)
+	assert.FileExists(t, filepath.Join(out, ioutil.ManifestFileName))
+
+	a := filepath.Join(out, "a.txt")
+	b := filepath.Join(out, "sub", "b.txt")
+	aTime, bTime := backdate(t, a), backdate(t, b)
+
+	stats, err = generateFiles(out, map[string]string{"a.txt": "a", "sub/b.txt": "b2"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.txt"}, stats.Unchanged)
+	assert.Equal(t, []string{filepath.Join("sub", "b.txt")}, stats.Written)
+	assert.Empty(t, stats.Removed)
+
+	assert.Equal(t, aTime, modTime(t, a))
+	assert.True(t, modTime(t, b).After(bTime))
+	assert.Equal(t, map[string]string{"a.txt": "a", "sub/b.txt": "b2"}, withoutManifest(readTree(t, out)))
+}
+
+func TestIncrementalRemovesStaleFiles(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "build")
+
+	_, err := generateFiles(out, map[string]string{"a.txt": "a", "old/nested/b.txt": "b"})
+	require.NoError(t, err)
+
+	stats, err := generateFiles(out, map[string]string{"a.txt": "a"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("old", "nested", "b.txt")}, stats.Removed)
+
+	// Directories emptied by the removal are removed too
+	assert.NoDirExists(t, filepath.Join(out, "old"))
+	assert.Equal(t, map[string]string{"a.txt": "a"}, withoutManifest(readTree(t, out)))
+}
+
+func TestIncrementalKeepsUnlistedFiles(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "build")
+
+	_, err := generateFiles(out, map[string]string{"a.txt": "a", "sub/b.txt": "b"})
+	require.NoError(t, err)
+
+	// Files added to the output directory after generation aren't in the manifest
+	require.NoError(t, os.WriteFile(filepath.Join(out, "notes.txt"), []byte("mine"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(out, "sub", "notes.txt"), []byte("mine too"), 0644))
+
+	stats, err := generateFiles(out, map[string]string{"a.txt": "a"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("sub", "b.txt")}, stats.Removed)
+
+	// sub isn't empty after removing b.txt, so it is kept
+	assert.Equal(t, map[string]string{
+		"a.txt":         "a",
+		"notes.txt":     "mine",
+		"sub/notes.txt": "mine too",
+	}, withoutManifest(readTree(t, out)))
+}
+
+func TestIncrementalRefusesUnknownDirectory(t *testing.T) {
+	out := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(out, "important.txt"), []byte("keep me"), 0644))
+
+	called := false
+	_, err := ioutil.GenerateIncrementally(out, func(stagingDir string) error {
+		called = true
+		return nil
+	})
+	assert.ErrorContains(t, err, "already exists and was not generated by Blueprint")
+	assert.False(t, called)
+	assert.Equal(t, map[string]string{"important.txt": "keep me"}, readTree(t, out))
+
+	// An empty directory is fine
+	_, err = generateFiles(t.TempDir(), map[string]string{"a.txt": "a"})
+	assert.NoError(t, err)
+}
+
+func TestIncrementalSymlinksAndPermissions(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "build")
+
+	generate := func(linkTarget string, perm os.FileMode) *ioutil.GenerateStats {
+		stats, err := ioutil.GenerateIncrementally(out, func(stagingDir string) error {
+			for _, name := range []string{"a.sh", "b.sh"} {
+				if err := os.WriteFile(filepath.Join(stagingDir, name), []byte("#!/bin/sh"), 0644); err != nil {
+					return err
+				}
+			}
+			if err := os.Chmod(filepath.Join(stagingDir, "a.sh"), perm); err != nil {
+				return err
+			}
+			return os.Symlink(linkTarget, filepath.Join(stagingDir, "run.sh"))
+		})
+		require.NoError(t, err)
+		return stats
+	}
+
+	generate("a.sh", 0644)
+	a := filepath.Join(out, "a.sh")
+	aTime := backdate(t, a)
+
+	// Unchanged symlinks are left alone
+	stats := generate("a.sh", 0644)
+	assert.ElementsMatch(t, []string{"a.sh", "b.sh", "run.sh"}, stats.Unchanged)
+
+	// Changing a symlink's target or a file's permissions updates them in place
+	stats = generate("b.sh", 0755)
+	assert.Equal(t, []string{"run.sh"}, stats.Written)
+	assert.ElementsMatch(t, []string{"a.sh", "b.sh"}, stats.Unchanged)
+
+	target, err := os.Readlink(filepath.Join(out, "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, "b.sh", target)
+
+	info, err := os.Stat(a)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	assert.Equal(t, aTime, info.ModTime())
+}
+
+func TestIncrementalFailedGenerate(t *testing.T) {
+	parent := t.TempDir()
+	out := filepath.Join(parent, "build")
+
+	_, err := generateFiles(out, map[string]string{"a.txt": "a", "sub/b.txt": "b"})
+	require.NoError(t, err)
+	before := readTree(t, out)
+
+	_, err = ioutil.GenerateIncrementally(out, func(stagingDir string) error {
+		if err := os.WriteFile(filepath.Join(stagingDir, "a.txt"), []byte("changed"), 0644); err != nil {
+			return err
+		}
+		return blueprint.Errorf("generation failed")
+	})
+	assert.ErrorContains(t, err, "generation failed")
+	assert.Equal(t, before, readTree(t, out))
+
+	// The staging directory is cleaned up
+	entries, err := os.ReadDir(parent)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "build", entries[0].Name())
+}
+
+func withoutManifest(files map[string]string) map[string]string {
+	delete(files, ioutil.ManifestFileName)
+	return files
+}
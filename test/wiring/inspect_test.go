@@ -0,0 +1,116 @@
+package wiring
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint/logging"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/cmdbuilder"
+	"github.com/blueprint-uservices/blueprint/plugins/grpc"
+	"github.com/blueprint-uservices/blueprint/plugins/retries"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+	wf "github.com/blueprint-uservices/blueprint/test/workflow/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var inspectSpec = cmdbuilder.SpecOption{
+	Name:        "inspect",
+	Description: "A nonleaf service that calls a leaf service over gRPC with retries.",
+	Build: func(spec wiring.WiringSpec) ([]string, error) {
+		leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+		nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+		retries.AddRetries(spec, leaf, 3)
+		grpc.Deploy(spec, leaf)
+		return []string{nonleaf}, nil
+	},
+}
+
+// Runs an inspect subcommand on inspectSpec, returning its output
+func inspect(t *testing.T, args ...string) (string, error) {
+	newWiringSpec("TestInspect")
+	if !*compilerLogging {
+		logging.DisableCompilerLogging()
+		defer logging.EnableCompilerLogging()
+	}
+	b := &cmdbuilder.CmdBuilder{
+		Name:     "TestInspect",
+		SpecName: inspectSpec.Name,
+		Spec:     inspectSpec,
+	}
+	var out bytes.Buffer
+	err := b.Inspect(&out, args...)
+	return out.String(), err
+}
+
+func TestInspectList(t *testing.T) {
+	out, err := inspect(t, "list")
+	require.NoError(t, err)
+
+	lines := splits(out)
+	assert.Equal(t, []string{"NAME", "TYPE", "NAMESPACE"}, strings.Fields(lines[0]))
+	assert.Contains(t, lines, "leaf.client.retrier         retries.RetrierClient                (application)")
+	assert.Contains(t, lines, "leaf.grpc_client            grpc.golangClient                    (application)")
+	assert.Contains(t, lines, "nonleaf                     workflow.workflowHandler             (application)")
+}
+
+func TestInspectDescribe(t *testing.T) {
+	out, err := inspect(t, "describe", "leaf.grpc_client")
+	require.NoError(t, err)
+
+	lines := splits(out)
+	assert.Equal(t, "leaf.grpc_client", lines[0])
+	assert.Equal(t, "defined as: *grpc.golangClient", lines[1])
+	assert.Regexp(t, `^defined at: .*inspect_test.go:\d+$`, lines[2])
+	assert.Contains(t, lines, "type:      grpc.golangClient")
+	assert.Contains(t, lines, "OutputPackage = grpc")
+	assert.Contains(t, out, "depends on:\n    leaf.grpc.addr (arg ServerAddr)\n")
+	assert.Contains(t, out, "used by:\n    leaf.client.retrier (arg Wrapped)\n")
+
+	// Services are described with their pointer
+	out, err = inspect(t, "describe", "nonleaf")
+	require.NoError(t, err)
+	assert.Contains(t, splits(out), "pointer:    [nonleaf.client] -> [nonleaf.dst]")
+
+	_, err = inspect(t, "describe", "nonexistent")
+	assert.ErrorContains(t, err, "nonexistent is neither an IR node nor defined in the wiring spec")
+}
+
+func TestInspectChain(t *testing.T) {
+	out, err := inspect(t, "chain", "leaf")
+	require.NoError(t, err)
+
+	var modifiers []string
+	for _, line := range splits(out) {
+		if fields := strings.Fields(line); len(fields) > 2 && strings.HasSuffix(fields[0], ".") {
+			modifiers = append(modifiers, fields[0]+" "+fields[1]+" "+fields[2])
+		}
+	}
+	assert.Equal(t, []string{
+		"1. leaf.client *workflow.workflowClient",
+		"2. leaf.client.retrier *retries.RetrierClient",
+		"3. leaf.grpc_client *grpc.golangClient",
+		"1. leaf.grpc_server *grpc.golangServer",
+		"2. leaf.dst (alias",
+	}, modifiers)
+	assert.Contains(t, out, "[leaf.client -> leaf.client.retrier -> leaf.grpc_client] -> [leaf.grpc_server -> leaf.dst]")
+
+	_, err = inspect(t, "chain", "nonexistent")
+	assert.ErrorContains(t, err, "nonexistent is not a service with a pointer, expected one of:\n  leaf\n  nonleaf")
+}
+
+func TestInspectArgs(t *testing.T) {
+	_, err := inspect(t)
+	assert.ErrorContains(t, err, "no inspect command specified")
+
+	_, err = inspect(t, "show", "leaf")
+	assert.ErrorContains(t, err, "unknown command \"show\", expected one of:\n  chain <service>\n  describe <name>\n  list")
+
+	_, err = inspect(t, "describe")
+	assert.ErrorContains(t, err, "usage: describe <name>")
+
+	_, err = inspect(t, "list", "leaf")
+	assert.ErrorContains(t, err, "usage: list")
+}
@@ -0,0 +1,202 @@
+package wiring
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/irgraph"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/clientpool"
+	"github.com/blueprint-uservices/blueprint/plugins/cmdbuilder"
+	"github.com/blueprint-uservices/blueprint/plugins/goproc"
+	"github.com/blueprint-uservices/blueprint/plugins/grpc"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+	wf "github.com/blueprint-uservices/blueprint/test/workflow/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+Tests for exporting the IR as a graph
+*/
+
+func assertEdge(t *testing.T, g *irgraph.Graph, from, to, kind string) {
+	t.Helper()
+	for _, edge := range g.Edges {
+		if edge.From == from && edge.To == to && edge.Kind == kind {
+			return
+		}
+	}
+	t.Errorf("expected %s edge %s -> %s in graph", kind, from, to)
+}
+
+func TestGraphNoProcess(t *testing.T) {
+	spec := newWiringSpec("TestGraphNoProcess")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+	grpc.Deploy(spec, leaf)
+
+	app := assertBuildSuccess(t, spec, nonleaf)
+	g := irgraph.FromIR(app)
+
+	require.NotNil(t, g.Node("leaf"))
+	assert.Equal(t, irgraph.KindNode, g.Node("leaf").Kind)
+	assert.Equal(t, irgraph.KindConfig, g.Node("leaf.grpc.bind_addr").Kind)
+	assert.Equal(t, irgraph.KindMetadata, g.Node("leaf.grpc.addr").Kind)
+
+	assertEdge(t, g, "nonleaf", "leaf.client", irgraph.EdgeArg)
+	assertEdge(t, g, "leaf.grpc_server", "leaf", irgraph.EdgeArg)
+	assertEdge(t, g, "leaf.grpc.addr", "leaf.grpc_server", irgraph.EdgeAddress)
+}
+
+func TestGraphNamespaces(t *testing.T) {
+	spec := newWiringSpec("TestGraphNamespaces")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+	grpc.Deploy(spec, leaf)
+
+	leafproc := goproc.CreateProcess(spec, "leafproc", leaf)
+	nonleafproc := goproc.CreateProcess(spec, "nonleafproc", nonleaf)
+
+	app := assertBuildSuccess(t, spec, leafproc, nonleafproc)
+	g := irgraph.FromIR(app)
+
+	require.NotNil(t, g.Node("leafproc"))
+	assert.Equal(t, irgraph.KindNamespace, g.Node("leafproc").Kind)
+	require.NotNil(t, g.Node("leafproc/leaf"))
+	assert.Equal(t, "leafproc", g.Node("leafproc/leaf").Namespace)
+	require.NotNil(t, g.Node("nonleafproc/leaf.client"))
+
+	assertEdge(t, g, "nonleafproc/nonleaf", "nonleafproc/leaf.client", irgraph.EdgeArg)
+	assertEdge(t, g, "leafproc", "leaf.grpc.bind_addr", irgraph.EdgeArg)
+	assertEdge(t, g, "leaf.grpc.addr", "leafproc/leaf.grpc_server", irgraph.EdgeAddress)
+	for _, edge := range g.Edges {
+		if edge.From == "leafproc" {
+			assert.NotEqual(t, "leafproc", g.Node(edge.To).Namespace, "a namespace's children should not be edges of the namespace")
+		}
+	}
+
+	var dot bytes.Buffer
+	require.NoError(t, irgraph.WriteDOT(&dot, g))
+	assert.Contains(t, dot.String(), `subgraph "cluster_leafproc" {`)
+
+	var js bytes.Buffer
+	require.NoError(t, irgraph.WriteJSON(&js, g))
+	var decoded irgraph.Graph
+	require.NoError(t, json.Unmarshal(js.Bytes(), &decoded))
+	assert.Equal(t, len(g.Nodes), len(decoded.Nodes))
+	assert.Equal(t, len(g.Edges), len(decoded.Edges))
+}
+
+func TestGraphClientPool(t *testing.T) {
+	spec := newWiringSpec("TestGraphClientPool")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+	clientpool.Create(spec, leaf, 4)
+	grpc.Deploy(spec, leaf)
+
+	app := assertBuildSuccess(t, spec, nonleaf)
+	g := irgraph.FromIR(app)
+
+	// The grpc client is only reachable through the client pool
+	require.NotNil(t, g.Node("leaf.grpc_client"))
+	assertEdge(t, g, "leaf.clientpool", "leaf.grpc_client", irgraph.EdgeArg)
+	assertEdge(t, g, "leaf.grpc_client", "leaf.grpc.addr", irgraph.EdgeArg)
+}
+
+func TestGraphDiff(t *testing.T) {
+	specA := newWiringSpec("TestGraphDiff")
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](specA, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](specA, "nonleaf", leaf)
+	appA := assertBuildSuccess(t, specA, leaf, nonleaf)
+
+	specB := newWiringSpec("TestGraphDiff")
+	leaf = workflow.Service[*wf.TestLeafServiceImpl](specB, "leaf")
+	nonleaf = workflow.Service[wf.TestNonLeafService](specB, "nonleaf", leaf)
+	grpc.Deploy(specB, leaf)
+	appB := assertBuildSuccess(t, specB, leaf, nonleaf)
+
+	diff := irgraph.Diff(irgraph.FromIR(appA), irgraph.FromIR(appB))
+	assert.Empty(t, diff.Removed)
+	assert.Empty(t, diff.Moved)
+
+	var added []string
+	for _, node := range diff.Added {
+		added = append(added, node.Name)
+	}
+	assert.ElementsMatch(t, []string{"leaf.grpc.addr", "leaf.grpc.bind_addr", "leaf.grpc.dial_addr", "leaf.grpc_client", "leaf.grpc_server"}, added)
+
+	assert.True(t, irgraph.Diff(irgraph.FromIR(appB), irgraph.FromIR(appB)).Empty())
+}
+
+func TestGraphDiffDuplicateNames(t *testing.T) {
+	node := func(name, typ, namespace string) *irgraph.Node {
+		return &irgraph.Node{ID: namespace + "." + name, Name: name, Type: typ, Namespace: namespace}
+	}
+	a := &irgraph.Graph{Nodes: []*irgraph.Node{
+		node("cache", "simpleCache", "a_proc"),
+		node("client", "grpcClient", "a_proc"),
+		node("client", "grpcClient", "b_proc"),
+		node("handler", "workflowHandler", "a_proc"),
+	}}
+	b := &irgraph.Graph{Nodes: []*irgraph.Node{
+		node("cache", "memcached", "a_proc"),
+		node("client", "grpcClient", "a_proc"),
+		node("client", "grpcClient", "b_proc"),
+		node("client", "grpcClient", "c_proc"),
+		node("handler", "workflowHandler", "b_proc"),
+		node("handler", "httpHandler", "a_proc"),
+	}}
+
+	// Nodes with the same name are paired by type and namespace, then by type, then by namespace
+	diff := irgraph.Diff(a, b)
+	assert.Empty(t, diff.Removed)
+	require.Len(t, diff.Added, 2)
+	assert.Equal(t, "c_proc.client", diff.Added[0].ID)
+	assert.Equal(t, "httpHandler", diff.Added[1].Type)
+	assert.Equal(t, []irgraph.NodeMove{{Name: "handler", From: "a_proc", To: "b_proc"}}, diff.Moved)
+	require.Len(t, diff.Changed, 1)
+	assert.Equal(t, "simpleCache", diff.Changed[0].From.Type)
+	assert.Equal(t, "memcached", diff.Changed[0].To.Type)
+
+	diff = irgraph.Diff(b, a)
+	require.Len(t, diff.Removed, 2)
+	assert.Empty(t, diff.Added)
+}
+
+var diffExtraService = cmdbuilder.SpecOption{
+	Name:        "extra",
+	Description: "A leaf and nonleaf service, and another leaf service.",
+	Build: func(spec wiring.WiringSpec) ([]string, error) {
+		nodes, err := overlayBase.Build(spec)
+		return append(nodes, workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf2")), err
+	},
+}
+
+func TestSpecDiffPointers(t *testing.T) {
+	b := &cmdbuilder.CmdBuilder{
+		Name:     "TestSpecDiffPointers",
+		SpecName: overlayBase.Name,
+		Spec:     overlayBase,
+		Registry: map[string]cmdbuilder.SpecOption{overlayBase.Name: overlayBase, diffExtraService.Name: diffExtraService},
+	}
+	diff, err := b.Diff(diffExtraService.Name)
+	require.NoError(t, err)
+	require.Len(t, diff.AddedPointers, 1)
+	assert.Equal(t, "leaf2", diff.AddedPointers[0].Pointer)
+	assert.Equal(t, []string{"leaf2.client"}, diff.AddedPointers[0].Client)
+	assert.Empty(t, diff.RemovedPointers)
+	assert.Contains(t, diff.String(), "+ pointer leaf2 with client modifiers [leaf2.client]")
+
+	b.SpecName, b.Spec = diffExtraService.Name, diffExtraService
+	diff, err = b.Diff(overlayBase.Name)
+	require.NoError(t, err)
+	assert.Empty(t, diff.AddedPointers)
+	require.Len(t, diff.RemovedPointers, 1)
+	assert.Equal(t, "leaf2", diff.RemovedPointers[0].Pointer)
+	assert.Contains(t, diff.String(), "- pointer leaf2")
+}
@@ -0,0 +1,55 @@
+package wiring
+
+import (
+	"testing"
+
+	"github.com/blueprint-uservices/blueprint/plugins/grpc"
+	"github.com/blueprint-uservices/blueprint/plugins/healthchecker"
+	"github.com/blueprint-uservices/blueprint/plugins/retries"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+	wf "github.com/blueprint-uservices/blueprint/test/workflow/workflow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientModifierBeforeRPC(t *testing.T) {
+	spec := newWiringSpec("TestClientModifierBeforeRPC")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+
+	retries.AddRetries(spec, leaf, 3)
+	grpc.Deploy(spec, leaf)
+
+	assertBuildSuccess(t, spec, leaf, nonleaf)
+}
+
+func TestClientModifierAfterRPC(t *testing.T) {
+	spec := newWiringSpec("TestClientModifierAfterRPC")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+
+	grpc.Deploy(spec, leaf)
+	retries.AddRetries(spec, leaf, 3)
+
+	err := assertBuildFailure(t, spec, leaf, nonleaf)
+	assert.ErrorContains(t, err, "retries.AddRetries at ")
+	assert.ErrorContains(t, err, "adds leaf.client.retrier to leaf, but leaf.client.retrier must be added before any rpc modifier; grpc.Deploy at ")
+	assert.ErrorContains(t, err, "already added rpc modifier leaf.grpc_client to leaf")
+	assert.ErrorContains(t, err, "modifierorder_test.go:")
+}
+
+func TestServerModifierAfterRPC(t *testing.T) {
+	spec := newWiringSpec("TestServerModifierAfterRPC")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+
+	grpc.Deploy(spec, leaf)
+	healthchecker.AddHealthCheckAPI(spec, leaf)
+
+	err := assertBuildFailure(t, spec, leaf, nonleaf)
+	assert.ErrorContains(t, err, "healthchecker.AddHealthCheckAPI at ")
+	assert.ErrorContains(t, err, "adds leaf.server.hc to leaf")
+	assert.ErrorContains(t, err, "grpc.Deploy at ")
+}
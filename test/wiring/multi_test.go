@@ -0,0 +1,150 @@
+package wiring
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint/ioutil"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint/logging"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/cmdbuilder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Each spec checks that it wasn't given the wiring spec of a previously compiled spec
+var multiSpecs = []cmdbuilder.SpecOption{
+	{
+		Name: "a",
+		Build: func(spec wiring.WiringSpec) ([]string, error) {
+			return buildFreshSpec(spec, "a")
+		},
+	},
+	{
+		Name: "b",
+		Build: func(spec wiring.WiringSpec) ([]string, error) {
+			return buildFreshSpec(spec, "b")
+		},
+	},
+	{
+		Name: "fail",
+		Build: func(spec wiring.WiringSpec) ([]string, error) {
+			return nil, blueprint.Errorf("fail is broken")
+		},
+	},
+	{
+		Name: "panic",
+		Build: func(spec wiring.WiringSpec) ([]string, error) {
+			panic("panic is broken")
+		},
+	},
+}
+
+func buildFreshSpec(spec wiring.WiringSpec, name string) ([]string, error) {
+	if len(spec.Defs()) > 0 {
+		return nil, blueprint.Errorf("spec %v was given a wiring spec that already defines %v", name, spec.Defs())
+	}
+	return persistProcesses.Build(spec)
+}
+
+// Selects the specs with -w and an output directory, as if specified on the command line
+func newMultiBuilder(t *testing.T, specNames string) *cmdbuilder.CmdBuilder {
+	newWiringSpec("TestBuildEach")
+	restoreBuilders(t)
+	if !*compilerLogging {
+		logging.DisableCompilerLogging()
+		t.Cleanup(logging.EnableCompilerLogging)
+	}
+	b := cmdbuilder.NewCmdBuilder("TestBuildEach")
+	b.Add(multiSpecs...)
+	b.SpecName = specNames
+	b.OutputDir = t.TempDir()
+	require.NoError(t, b.ValidateArgs())
+	return b
+}
+
+// Returns the lines of the summary table written by BuildEach, keyed by spec name
+func summaryRows(t *testing.T, summary string) map[string]string {
+	lines := splits(strings.TrimSpace(summary))
+	require.Equal(t, []string{"SPEC", "TIME", "OUTPUT", "RESULT"}, strings.Fields(lines[0]))
+	rows := make(map[string]string)
+	for _, line := range lines[1:] {
+		rows[strings.Fields(line)[0]] = line
+	}
+	return rows
+}
+
+func TestBuildEachSelectedSpecs(t *testing.T) {
+	b := newMultiBuilder(t, "b, a")
+	graphs := t.TempDir()
+	b.DOTFile = filepath.Join(graphs, "app.dot")
+	b.JSONFile = filepath.Join(graphs, "app.json")
+	b.SaveIRFile = filepath.Join(graphs, "ir.json")
+
+	var summary bytes.Buffer
+	results, err := b.BuildEach(&summary)
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+	for i, name := range []string{"b", "a"} {
+		assert.Equal(t, name, results[i].Name)
+		assert.Equal(t, filepath.Join(b.OutputDir, name), results[i].OutputDir)
+		assert.NoError(t, results[i].Err)
+		assert.FileExists(t, filepath.Join(b.OutputDir, name, ioutil.ManifestFileName))
+	}
+
+	// Each spec's graph and IR are written to separate files
+	for _, name := range []string{"app-a.dot", "app-b.dot", "app-a.json", "app-b.json", "ir-a.json", "ir-b.json"} {
+		assert.FileExists(t, filepath.Join(graphs, name))
+	}
+	assert.NoFileExists(t, b.DOTFile)
+	assert.NoFileExists(t, b.JSONFile)
+	assert.NoFileExists(t, b.SaveIRFile)
+
+	rows := summaryRows(t, summary.String())
+	assert.Len(t, rows, 2)
+	for _, name := range []string{"a", "b"} {
+		assert.Contains(t, rows[name], filepath.Join(b.OutputDir, name))
+		assert.True(t, strings.HasSuffix(rows[name], "ok"), rows[name])
+	}
+}
+
+func TestBuildEachAll(t *testing.T) {
+	b := newMultiBuilder(t, "all")
+
+	var summary bytes.Buffer
+	results, err := b.BuildEach(&summary)
+	assert.ErrorContains(t, err, "2 of 4 wiring specs failed to compile")
+
+	// Failing specs don't prevent the specs after them from compiling
+	require.Len(t, results, 4)
+	for i, name := range []string{"a", "b", "fail", "panic"} {
+		assert.Equal(t, name, results[i].Name)
+	}
+	assert.NoError(t, results[0].Err)
+	assert.NoError(t, results[1].Err)
+	assert.ErrorContains(t, results[2].Err, "fail is broken")
+	assert.ErrorContains(t, results[3].Err, "panic while compiling TestBuildEach-panic: panic is broken")
+	assert.DirExists(t, filepath.Join(b.OutputDir, "a"))
+	assert.DirExists(t, filepath.Join(b.OutputDir, "b"))
+
+	rows := summaryRows(t, summary.String())
+	assert.Len(t, rows, 4)
+	assert.True(t, strings.HasSuffix(rows["a"], "ok"), rows["a"])
+	assert.True(t, strings.HasSuffix(rows["b"], "ok"), rows["b"])
+	assert.Contains(t, rows["fail"], "FAILED: ")
+	assert.Contains(t, rows["fail"], "fail is broken")
+	assert.Contains(t, rows["panic"], "FAILED: ")
+	assert.Contains(t, rows["panic"], "panic is broken")
+}
+
+func TestBuildEachUnknownSpec(t *testing.T) {
+	b := cmdbuilder.NewCmdBuilder("TestBuildEach")
+	b.Add(multiSpecs...)
+	b.SpecName = "a,missing"
+	b.OutputDir = t.TempDir()
+	assert.ErrorContains(t, b.ValidateArgs(), "unknown wiring spec \"missing\"")
+}
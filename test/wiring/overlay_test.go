@@ -0,0 +1,91 @@
+package wiring
+
+import (
+	"testing"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/pointer"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/cmdbuilder"
+	"github.com/blueprint-uservices/blueprint/plugins/goproc"
+	"github.com/blueprint-uservices/blueprint/plugins/grpc"
+	"github.com/blueprint-uservices/blueprint/plugins/retries"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+	wf "github.com/blueprint-uservices/blueprint/test/workflow/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var overlayBase = cmdbuilder.SpecOption{
+	Name:        "base",
+	Description: "A leaf and nonleaf service.",
+	Build: func(spec wiring.WiringSpec) ([]string, error) {
+		leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+		nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+		return []string{leaf, nonleaf}, nil
+	},
+}
+
+var overlayRetries = cmdbuilder.Overlay{
+	Name:        "retries",
+	Description: "Retries calls to each service.",
+	Apply: func(spec wiring.WiringSpec, nodes []string) ([]string, error) {
+		for _, node := range nodes {
+			retries.AddRetries(spec, node, 3)
+		}
+		return nodes, nil
+	},
+}
+
+var overlayGRPC = cmdbuilder.Overlay{
+	Name:        "grpc",
+	Description: "Deploys each service with gRPC.",
+	Apply: func(spec wiring.WiringSpec, nodes []string) ([]string, error) {
+		for _, node := range nodes {
+			grpc.Deploy(spec, node)
+		}
+		return nodes, nil
+	},
+}
+
+var overlayProcesses = cmdbuilder.Overlay{
+	Name:        "processes",
+	Description: "Deploys each service in its own process.",
+	Apply: func(spec wiring.WiringSpec, nodes []string) ([]string, error) {
+		var procs []string
+		for _, node := range nodes {
+			procs = append(procs, goproc.Deploy(spec, node))
+		}
+		return procs, nil
+	},
+}
+
+func TestOverlaysApplyInOrder(t *testing.T) {
+	spec := newWiringSpec("TestOverlaysApplyInOrder")
+
+	option := cmdbuilder.WithOverlays(overlayBase, overlayRetries, overlayGRPC, overlayProcesses)
+	assert.Equal(t, "base+retries+grpc+processes", option.Name)
+
+	nodes, err := option.Build(spec)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"leaf_proc", "nonleaf_proc"}, nodes)
+
+	assertBuildSuccess(t, spec, nodes...)
+
+	leaf := pointer.GetPointer(spec, "leaf")
+	require.NotNil(t, leaf)
+	// The workflow client is the first modifier; the overlays' modifiers follow it in order
+	assert.Equal(t, []string{"leaf.client", "leaf.client.retrier", "leaf.grpc_client"}, leaf.SrcModifiers())
+}
+
+func TestOverlaysMisordered(t *testing.T) {
+	spec := newWiringSpec("TestOverlaysMisordered")
+
+	option := cmdbuilder.WithOverlays(overlayBase, overlayGRPC, overlayRetries)
+	nodes, err := option.Build(spec)
+	require.NoError(t, err)
+
+	// Retries must be applied before gRPC
+	err = assertBuildFailure(t, spec, nodes...)
+	assert.ErrorContains(t, err, "retries.AddRetries at ")
+	assert.ErrorContains(t, err, "grpc.Deploy at ")
+}
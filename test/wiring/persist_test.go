@@ -0,0 +1,161 @@
+package wiring
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/cmdbuilder"
+	"github.com/blueprint-uservices/blueprint/plugins/golang/gogen"
+	"github.com/blueprint-uservices/blueprint/plugins/goproc"
+	"github.com/blueprint-uservices/blueprint/plugins/grpc"
+	"github.com/blueprint-uservices/blueprint/plugins/healthchecker"
+	"github.com/blueprint-uservices/blueprint/plugins/http"
+	"github.com/blueprint-uservices/blueprint/plugins/retries"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+	wf "github.com/blueprint-uservices/blueprint/test/workflow/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistIRRoundTrip(t *testing.T) {
+	spec := newWiringSpec("TestPersistIRRoundTrip")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+	retries.AddRetries(spec, leaf, 3)
+	healthchecker.AddHealthCheckAPI(spec, leaf)
+	grpc.Deploy(spec, leaf)
+
+	app := assertBuildSuccess(t, spec, leaf, nonleaf)
+
+	var saved bytes.Buffer
+	require.NoError(t, ir.WriteJSON(&saved, app))
+
+	loaded, err := ir.ReadJSON(bytes.NewReader(saved.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, app.String(), loaded.String())
+
+	// Writing the loaded IR produces the same document
+	var resaved bytes.Buffer
+	require.NoError(t, ir.WriteJSON(&resaved, loaded))
+	assert.Equal(t, saved.String(), resaved.String())
+
+	// Nodes shared between namespaces are shared after loading
+	nodes := make(map[string]ir.IRNode)
+	for _, node := range loaded.GetAllIRNodes() {
+		if existing, seen := nodes[node.Name()]; seen {
+			assert.Same(t, existing, node, node.Name())
+		}
+		nodes[node.Name()] = node
+	}
+	assert.Contains(t, nodes, "leaf.grpc_server")
+	assert.Contains(t, nodes, "leaf.client.retrier")
+}
+
+func TestPersistIRSchema(t *testing.T) {
+	spec := newWiringSpec("TestPersistIRSchema")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+	grpc.Deploy(spec, leaf)
+	leafProc := goproc.Deploy(spec, leaf)
+	nonleafProc := goproc.Deploy(spec, nonleaf)
+	gogen.OverrideTemplate(spec, "timeouts", "client", `{{.Name}}`)
+
+	app := assertBuildSuccess(t, spec, leafProc, nonleafProc)
+
+	doc, err := ir.EncodeJSON(app)
+	require.NoError(t, err)
+	assert.Equal(t, ir.JSONVersion, doc.Version)
+
+	nodes := make(map[string]ir.JSONNode)
+	for _, node := range doc.Nodes {
+		assert.NotEmpty(t, node.Kind)
+		assert.NotEmpty(t, node.Name)
+		nodes[node.Name] = node
+	}
+	assert.Equal(t, doc.Root, nodes["TestPersistIRSchema"].ID)
+
+	// A process's children are its nodes, which are also its edges
+	proc := nodes["leaf_proc"]
+	assert.Equal(t, "*github.com/blueprint-uservices/blueprint/plugins/goproc.Process", proc.Kind)
+	assert.Equal(t, "leaf_proc", proc.Properties["ProcName"])
+	assert.NotEmpty(t, proc.Children)
+	assert.Len(t, proc.Edges["Nodes"], len(proc.Children))
+	assert.Contains(t, proc.Children, nodes[gogen.OptionsName].ID)
+	assert.Equal(t, nodes["leaf_proc.logger"].ID, proc.Edges["Logger"])
+
+	// Nodes are written from their exported fields
+	server := nodes["leaf.grpc_server"]
+	assert.Equal(t, "grpc", server.Properties["OutputPackage"])
+	assert.Equal(t, nodes["leaf.grpc.bind_addr"].ID, server.Edges["Bind"])
+	assert.Equal(t, map[string]any{"timeouts/client": "{{.Name}}"}, nodes[gogen.OptionsName].Properties["TemplateOverrides"])
+
+	var saved bytes.Buffer
+	require.NoError(t, ir.WriteJSON(&saved, app))
+	loaded, err := ir.ReadJSON(bytes.NewReader(saved.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, app.String(), loaded.String())
+}
+
+var persistProcesses = cmdbuilder.SpecOption{
+	Name:        "processes",
+	Description: "A leaf and nonleaf service in separate processes.",
+	Build: func(spec wiring.WiringSpec) ([]string, error) {
+		leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+		nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+		retries.AddRetries(spec, leaf, 3)
+		http.Deploy(spec, leaf)
+		return []string{goproc.Deploy(spec, leaf), goproc.Deploy(spec, nonleaf)}, nil
+	},
+}
+
+func TestPersistIRGenerateArtifacts(t *testing.T) {
+	newWiringSpec("TestPersistIRGenerateArtifacts")
+	restoreBuilders(t)
+	resetBuilders := ir.SaveDefaultNamespaces()
+	dir := t.TempDir()
+	irFile := filepath.Join(dir, "ir.json")
+
+	// Compile the spec, saving its IR
+	built := &cmdbuilder.CmdBuilder{
+		Name:       "TestPersistIRGenerateArtifacts",
+		SpecName:   persistProcesses.Name,
+		Spec:       persistProcesses,
+		Env:        true,
+		Port:       12345,
+		OutputDir:  filepath.Join(dir, "built"),
+		SaveIRFile: irFile,
+	}
+	require.NoError(t, built.Build())
+
+	// Regenerate the artifacts from the saved IR, without the default builders that Build registered
+	resetBuilders()
+	loaded := &cmdbuilder.CmdBuilder{
+		Name:      "TestPersistIRGenerateArtifacts",
+		Env:       true,
+		Port:      12345,
+		OutputDir: filepath.Join(dir, "loaded"),
+	}
+	require.NoError(t, loaded.BuildFromIR(irFile))
+
+	artifacts := readTree(t, built.OutputDir)
+	assert.Contains(t, artifacts, ".local.env")
+	assert.Contains(t, artifacts, "linux/leaf_proc/leaf_proc/main.go")
+	assert.Equal(t, artifacts, readTree(t, loaded.OutputDir))
+}
+
+func TestPersistIRVersion(t *testing.T) {
+	_, err := ir.ReadJSON(strings.NewReader(`{"version": 2, "application": "app"}`))
+	assert.ErrorContains(t, err, "only version 1 is supported")
+
+	_, err = ir.ReadJSON(strings.NewReader(`{"version": 1, "application": "app", "root": 1, "nodes": [
+		{"id": 1, "kind": "*github.com/blueprint-uservices/blueprint/blueprint/pkg/ir.ApplicationNode", "edges": {"Children": [2]}},
+		{"id": 2, "kind": "*example.com/unknown.Node"}
+	]}`))
+	assert.ErrorContains(t, err, "type example.com/unknown.Node is not registered")
+}
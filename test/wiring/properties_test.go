@@ -0,0 +1,92 @@
+package wiring
+
+import (
+	"testing"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/retries"
+	"github.com/blueprint-uservices/blueprint/plugins/timeouts"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+	wf "github.com/blueprint-uservices/blueprint/test/workflow/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testLabels = wiring.DeclareProperty(wiring.Property[string]{Key: "wiring.TestLabels", Multi: true})
+var testWeight = wiring.DeclareProperty(wiring.Property[int]{Key: "wiring.TestWeight", Default: 1})
+
+func TestInvalidTimeout(t *testing.T) {
+	spec := newWiringSpec("TestInvalidTimeout")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+	timeouts.Add(spec, leaf, "1 second")
+
+	err := assertBuildFailure(t, spec, leaf, nonleaf)
+	assert.ErrorContains(t, err, "properties_test.go:")
+	assert.ErrorContains(t, err, "invalid timeouts.Timeout property 1 second of leaf.client.timeout")
+}
+
+func TestValidTimeout(t *testing.T) {
+	spec := newWiringSpec("TestValidTimeout")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+	timeouts.Add(spec, leaf, "1s")
+
+	assertBuildSuccess(t, spec, leaf, nonleaf)
+
+	timeout, err := timeouts.TimeoutProperty.Get(spec, "leaf.client.timeout")
+	require.NoError(t, err)
+	assert.Equal(t, "1s", timeout)
+}
+
+func TestNegativeRetries(t *testing.T) {
+	spec := newWiringSpec("TestNegativeRetries")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+	retries.AddRetries(spec, leaf, -1)
+
+	err := assertBuildFailure(t, spec, leaf, nonleaf)
+	assert.ErrorContains(t, err, "the maximum number of retries cannot be negative")
+}
+
+func TestTypedProperties(t *testing.T) {
+	spec := newWiringSpec("TestTypedProperties")
+
+	weight, err := testWeight.Get(spec, "node")
+	require.NoError(t, err)
+	assert.Equal(t, 1, weight)
+
+	testWeight.Set(spec, "node", 5)
+	testLabels.Add(spec, "node", "a")
+	testLabels.Add(spec, "node", "b")
+
+	weight, err = testWeight.Get(spec, "node")
+	require.NoError(t, err)
+	assert.Equal(t, 5, weight)
+
+	labels, err := testLabels.GetAll(spec, "node")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, labels)
+
+	// A single-valued property can't be added to twice
+	testWeight.Add(spec, "node", 6)
+	assert.ErrorContains(t, spec.Err(), "wiring.TestWeight property of node is single-valued, but already has value 5")
+}
+
+func TestUntypedPropertiesAreValidated(t *testing.T) {
+	spec := newWiringSpec("TestUntypedPropertiesAreValidated")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	spec.SetProperty(leaf, testWeight.Key, "heavy")
+	spec.AddProperty(leaf, testLabels.Key, "a")
+
+	_, err := testWeight.Get(spec, leaf)
+	assert.ErrorContains(t, err, "wiring.TestWeight property of leaf has value heavy of type string, but expected int")
+
+	report := spec.Validate(leaf)
+	require.Len(t, report.Filter(wiring.IssueError), 1)
+	assert.Contains(t, report.Filter(wiring.IssueError)[0].Message, "wiring.TestWeight property of leaf has value heavy of type string, but expected int")
+}
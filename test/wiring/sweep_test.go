@@ -0,0 +1,146 @@
+package wiring
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/blueprint/logging"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/cmdbuilder"
+	"github.com/blueprint-uservices/blueprint/plugins/goproc"
+	"github.com/blueprint-uservices/blueprint/plugins/http"
+	"github.com/blueprint-uservices/blueprint/plugins/retries"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+	wf "github.com/blueprint-uservices/blueprint/test/workflow/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var sweepSpec = cmdbuilder.SweepSpec{
+	Name: "leaf",
+	Build: func(spec wiring.WiringSpec, params cmdbuilder.Params) ([]string, error) {
+		leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+		nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+		retries.AddRetries(spec, leaf, int64(params["retries"].(int)))
+		return []string{leaf, nonleaf}, nil
+	},
+}
+
+func TestSweepVariants(t *testing.T) {
+	variants, err := cmdbuilder.Sweep(sweepSpec,
+		cmdbuilder.Parameter{Name: "retries", Values: []any{0, 3}},
+		cmdbuilder.Parameter{Name: "timeout", Values: []any{100 * time.Millisecond, time.Second}},
+	)
+	require.NoError(t, err)
+
+	var names []string
+	for _, variant := range variants {
+		names = append(names, variant.Spec.Name)
+	}
+	assert.Equal(t, []string{
+		"leaf_retries-0_timeout-100ms",
+		"leaf_retries-0_timeout-1s",
+		"leaf_retries-3_timeout-100ms",
+		"leaf_retries-3_timeout-1s",
+	}, names)
+	assert.Equal(t, cmdbuilder.Params{"retries": 3, "timeout": 100 * time.Millisecond}, variants[2].Params)
+
+	spec := newWiringSpec("TestSweepVariants")
+	nodes, err := variants[2].Spec.Build(spec)
+	require.NoError(t, err)
+	assertBuildSuccess(t, spec, nodes...)
+
+	var maxRetries int64
+	require.NoError(t, spec.GetProperty("leaf.client.retrier", retries.PROP_MAXRETRY, &maxRetries))
+	assert.Equal(t, int64(3), maxRetries)
+}
+
+func TestSweepInvalidGrid(t *testing.T) {
+	_, err := cmdbuilder.Sweep(sweepSpec, cmdbuilder.Parameter{Name: "retries"})
+	assert.ErrorContains(t, err, "no values for parameter retries")
+
+	_, err = cmdbuilder.Sweep(sweepSpec,
+		cmdbuilder.Parameter{Name: "retries", Values: []any{1}},
+		cmdbuilder.Parameter{Name: "retries", Values: []any{2}},
+	)
+	assert.ErrorContains(t, err, "parameter retries more than once")
+}
+
+// Fails to build the variant with a timeout of more than 500ms
+var sweepProcesses = cmdbuilder.SweepSpec{
+	Name: "processes",
+	Build: func(spec wiring.WiringSpec, params cmdbuilder.Params) ([]string, error) {
+		if timeout := params["timeout"].(time.Duration); timeout > 500*time.Millisecond {
+			return nil, blueprint.Errorf("timeout %v is too long", timeout)
+		}
+		leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+		nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+		retries.AddRetries(spec, leaf, int64(params["retries"].(int)))
+		http.Deploy(spec, leaf)
+		return []string{goproc.Deploy(spec, leaf), goproc.Deploy(spec, nonleaf)}, nil
+	},
+}
+
+func TestBuildSweep(t *testing.T) {
+	newWiringSpec("TestBuildSweep")
+	restoreBuilders(t)
+	if !*compilerLogging {
+		logging.DisableCompilerLogging()
+		t.Cleanup(logging.EnableCompilerLogging)
+	}
+	b := cmdbuilder.NewCmdBuilder("TestBuildSweep")
+	b.OutputDir = t.TempDir()
+
+	var summary bytes.Buffer
+	results, err := b.BuildSweep(&summary, sweepProcesses,
+		cmdbuilder.Parameter{Name: "retries", Values: []any{0, 3}},
+		cmdbuilder.Parameter{Name: "timeout", Values: []any{100 * time.Millisecond, time.Second}},
+	)
+	assert.ErrorContains(t, err, "2 of 4 wiring specs failed to compile")
+	require.Len(t, results, 4)
+
+	data, err := os.ReadFile(filepath.Join(b.OutputDir, cmdbuilder.SweepManifestFile))
+	require.NoError(t, err)
+	var manifest map[string]any
+	require.NoError(t, json.Unmarshal(data, &manifest))
+
+	assert.Equal(t, "TestBuildSweep", manifest["application"])
+	assert.Equal(t, "processes", manifest["spec"])
+	assert.Equal(t, []any{
+		map[string]any{"name": "retries", "values": []any{0.0, 3.0}},
+		map[string]any{"name": "timeout", "values": []any{"100ms", "1s"}},
+	}, manifest["parameters"])
+
+	variants := manifest["variants"].([]any)
+	require.Len(t, variants, 4)
+	for i, expected := range []struct {
+		name    string
+		retries float64
+		timeout string
+		fails   bool
+	}{
+		{"processes_retries-0_timeout-100ms", 0, "100ms", false},
+		{"processes_retries-0_timeout-1s", 0, "1s", true},
+		{"processes_retries-3_timeout-100ms", 3, "100ms", false},
+		{"processes_retries-3_timeout-1s", 3, "1s", true},
+	} {
+		variant := variants[i].(map[string]any)
+		assert.Equal(t, expected.name, variant["name"])
+		assert.Equal(t, expected.name, variant["output_dir"])
+		assert.Equal(t, map[string]any{"retries": expected.retries, "timeout": expected.timeout}, variant["params"])
+		assert.Equal(t, filepath.Join(b.OutputDir, expected.name), results[i].OutputDir)
+
+		if expected.fails {
+			assert.Contains(t, variant["error"], "timeout 1s is too long")
+			assert.NoDirExists(t, filepath.Join(b.OutputDir, expected.name))
+		} else {
+			assert.NotContains(t, variant, "error")
+			assert.FileExists(t, filepath.Join(b.OutputDir, expected.name, "linux", "leaf_proc", "leaf_proc", "main.go"))
+		}
+	}
+}
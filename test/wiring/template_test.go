@@ -0,0 +1,81 @@
+package wiring
+
+import (
+	"testing"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+	"github.com/blueprint-uservices/blueprint/plugins/golang/gogen"
+	"github.com/blueprint-uservices/blueprint/plugins/goproc"
+	_ "github.com/blueprint-uservices/blueprint/plugins/retries"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+	wf "github.com/blueprint-uservices/blueprint/test/workflow/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTemplate = gogen.RegisterTemplate("testplugin", "greeting", `hello {{.Name}}`)
+
+// Creates a module in a new workspace that is configured with options
+func newModuleWithOptions(t *testing.T, options ...*gogen.Options) *gogen.ModuleBuilderImpl {
+	workspace, err := gogen.NewWorkspaceBuilder(t.TempDir())
+	require.NoError(t, err)
+	for _, opts := range options {
+		require.NoError(t, opts.AddToWorkspace(workspace))
+	}
+	module, err := gogen.NewModuleBuilder(workspace, "example.com/generated")
+	require.NoError(t, err)
+	return module
+}
+
+func TestOverrideTemplate(t *testing.T) {
+	spec := newWiringSpec("TestOverrideTemplate")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	proc := goproc.Deploy(spec, leaf)
+
+	// Overrides can include the plugin's template, and use the template helper functions
+	gogen.OverrideTemplate(spec, "testplugin", "greeting", `{{template "default" .}}, {{Title .Name}}!`)
+	require.NoError(t, spec.Err())
+
+	// The overrides are saved in the IR of each golang process
+	app := assertBuildSuccess(t, spec, proc)
+	assertIR(t, app,
+		`TestOverrideTemplate = BlueprintApplication() {
+			leaf.handler.visibility
+			leaf_proc = GolangProcessNode() {
+			  gogen.options = GolangOptions(testplugin/greeting)
+			  leaf = TestLeafService()
+			  leaf_proc.logger = SLogger()
+			  leaf_proc.stdoutmetriccollector = StdoutMetricCollector()
+			}
+		  }`)
+	options := ir.Filter[*gogen.Options](app.GetAllIRNodes())
+	require.Len(t, options, 1)
+
+	args := struct{ Name string }{"world"}
+
+	code, err := testTemplate.Execute(newModuleWithOptions(t, options[0]), args)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world, World!", code)
+
+	// Workspaces generated for other specs don't use the override
+	code, err = testTemplate.Execute(newModuleWithOptions(t), args)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", code)
+
+	spec = newWiringSpec("TestOverrideTemplate2")
+	leaf = workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	app = assertBuildSuccess(t, spec, goproc.Deploy(spec, leaf))
+	assert.Empty(t, ir.Filter[*gogen.Options](app.GetAllIRNodes()))
+}
+
+func TestOverrideTemplateErrors(t *testing.T) {
+	spec := newWiringSpec("TestOverrideUnknownTemplate")
+	gogen.OverrideTemplate(spec, "retries", "server", `{{.Name}}`)
+	assert.ErrorContains(t, spec.Err(), "cannot override template server of plugin retries because it does not exist")
+	assert.ErrorContains(t, spec.Err(), "retries client")
+
+	spec = newWiringSpec("TestOverrideInvalidTemplate")
+	gogen.OverrideTemplate(spec, "retries", "client", `{{.Name`)
+	assert.ErrorContains(t, spec.Err(), "invalid override of template client of plugin retries")
+}
@@ -2,6 +2,9 @@ package wiring
 
 import (
 	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -64,3 +67,35 @@ func assertIR(t *testing.T, app *ir.ApplicationNode, expected string) bool {
 	require.Equal(t, b, a, "Got unexpected application\n%v", app.String())
 	return true
 }
+
+// Restores the default namespace builders and the build parallelism when t finishes, for tests that change them
+func restoreBuilders(t *testing.T) {
+	restore := ir.SaveDefaultNamespaces()
+	parallelism := ir.BuildParallelism()
+	t.Cleanup(func() {
+		restore()
+		ir.SetBuildParallelism(parallelism)
+	})
+}
+
+// Reads the contents of every file within dir, keyed by their slash-separated paths relative to dir
+func readTree(t *testing.T, dir string) map[string]string {
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(contents)
+		return nil
+	})
+	require.NoError(t, err)
+	return files
+}
@@ -0,0 +1,65 @@
+package wiring
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/address"
+	"github.com/blueprint-uservices/blueprint/plugins/goproc"
+	"github.com/blueprint-uservices/blueprint/plugins/grpc"
+	"github.com/blueprint-uservices/blueprint/plugins/linuxcontainer"
+	"github.com/blueprint-uservices/blueprint/plugins/mongodb"
+	"github.com/blueprint-uservices/blueprint/plugins/passes/topology"
+	"github.com/blueprint-uservices/blueprint/plugins/redis"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+	"github.com/blueprint-uservices/blueprint/test/workflow/nosqldb"
+	wf "github.com/blueprint-uservices/blueprint/test/workflow/workflow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopology(t *testing.T) {
+	spec := newWiringSpec("TestTopology")
+
+	leaf_cache := redis.Container(spec, "leaf_cache")
+	leaf_db := mongodb.Container(spec, "leaf_db")
+	leaf := workflow.Service[*nosqldb.TestLeafServiceImplWithDB](spec, "leaf", leaf_cache, leaf_db)
+	nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+
+	for _, service := range []string{leaf, nonleaf} {
+		grpc.Deploy(spec, service)
+		goproc.Deploy(spec, service)
+		linuxcontainer.Deploy(spec, service)
+	}
+
+	app := assertBuildSuccess(t, spec, nonleaf+"_ctr")
+
+	pass := topology.NewTopologyPass("", "")
+	_, err := pass.Analyze(spec, app)
+	assert.NoError(t, err)
+
+	expected := `container leaf_ctr
+		  port 12345 leaf.grpc.bind_addr
+		  process leaf_proc
+		    service leaf uses leaf_cache.ctr, leaf_db.ctr
+		container nonleaf_ctr
+		  port 12345 nonleaf.grpc.bind_addr
+		  process nonleaf_proc
+		    service nonleaf
+		backend leaf_cache.ctr (redis)
+		  port 6379 leaf_cache.bind_addr (fixed)
+		  used by leaf
+		backend leaf_db.ctr (mongodb)
+		  port 27017 leaf_db.bind_addr (fixed)
+		  used by leaf`
+	assert.Equal(t, strings.ReplaceAll(expected, "\n\t\t", "\n"), pass.Report.String())
+
+	// Reporting the ports must not assign them in the IR
+	for _, ctr := range pass.Report.Containers {
+		assert.Len(t, ctr.Ports, 1)
+		assert.False(t, ctr.Ports[0].Fixed)
+	}
+	binds, _, _ := address.Split(app.GetAllIRNodes())
+	for _, bind := range binds {
+		assert.Zero(t, bind.Port, bind.Name())
+	}
+}
@@ -0,0 +1,97 @@
+package wiring
+
+import (
+	"testing"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/analysis"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/coreplugins/irgraph"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/faultinjector"
+	"github.com/blueprint-uservices/blueprint/plugins/faultinjector/probabilistic"
+	"github.com/blueprint-uservices/blueprint/plugins/grpc"
+	"github.com/blueprint-uservices/blueprint/plugins/passes/nofaults"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+	wf "github.com/blueprint-uservices/blueprint/test/workflow/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+Tests for IR transform passes
+*/
+
+type testTransformPass struct {
+	name string
+	deps analysis.PassDependencies
+	ran  *[]string
+}
+
+func (p *testTransformPass) Name() string                            { return p.name }
+func (p *testTransformPass) Dependencies() analysis.PassDependencies { return p.deps }
+func (p *testTransformPass) ImplementsTransformPass()                {}
+func (p *testTransformPass) Transform(wiring.WiringSpec, *ir.ApplicationNode) error {
+	*p.ran = append(*p.ran, p.name)
+	return nil
+}
+
+func TestTransformPassOrder(t *testing.T) {
+	var ran []string
+	passes := []analysis.IRTransformPass{
+		&testTransformPass{name: "a", deps: analysis.PassDependencies{Requires: []string{"c"}}, ran: &ran},
+		&testTransformPass{name: "b", deps: analysis.PassDependencies{After: []string{"missing"}}, ran: &ran},
+		&testTransformPass{name: "c", ran: &ran},
+		&testTransformPass{name: "d", deps: analysis.PassDependencies{Before: []string{"c"}}, ran: &ran},
+	}
+	require.NoError(t, analysis.RunTransformPasses(nil, &ir.ApplicationNode{}, passes))
+	assert.Equal(t, []string{"b", "d", "c", "a"}, ran)
+}
+
+func TestTransformPassOrderErrors(t *testing.T) {
+	var ran []string
+	_, err := analysis.OrderTransformPasses([]analysis.IRTransformPass{
+		&testTransformPass{name: "a", deps: analysis.PassDependencies{Requires: []string{"missing"}}, ran: &ran},
+	})
+	assert.ErrorContains(t, err, "requires pass missing")
+
+	_, err = analysis.OrderTransformPasses([]analysis.IRTransformPass{
+		&testTransformPass{name: "a", deps: analysis.PassDependencies{After: []string{"b"}}, ran: &ran},
+		&testTransformPass{name: "b", deps: analysis.PassDependencies{After: []string{"a"}}, ran: &ran},
+		&testTransformPass{name: "c", ran: &ran},
+	})
+	assert.ErrorContains(t, err, "transform passes a, b have cyclic dependencies")
+
+	_, err = analysis.OrderTransformPasses([]analysis.IRTransformPass{
+		&testTransformPass{name: "a", ran: &ran},
+		&testTransformPass{name: "a", ran: &ran},
+	})
+	assert.ErrorContains(t, err, "registered more than once")
+}
+
+func TestStripFaultInjectors(t *testing.T) {
+	spec := newWiringSpec("TestStripFaultInjectors")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+	faultinjector.AddProbabilisticFailures(spec, leaf, 10)
+	grpc.Deploy(spec, leaf)
+
+	app := assertBuildSuccess(t, spec, nonleaf)
+	injectors := 0
+	for _, node := range app.GetAllIRNodes() {
+		if _, isInjector := node.(*probabilistic.ServerWrapper); isInjector {
+			injectors++
+		}
+	}
+	require.Equal(t, 1, injectors)
+
+	require.NoError(t, analysis.RunTransformPasses(spec, app, []analysis.IRTransformPass{nofaults.NewStripFaultInjectorsPass()}))
+	for _, node := range app.GetAllIRNodes() {
+		_, isInjector := node.(*probabilistic.ServerWrapper)
+		assert.False(t, isInjector, "fault injector %v was not removed", node.Name())
+	}
+
+	// The grpc server now wraps the service directly
+	g := irgraph.FromIR(app)
+	assertEdge(t, g, "leaf.grpc_server", "leaf", irgraph.EdgeArg)
+}
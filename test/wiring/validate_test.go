@@ -0,0 +1,107 @@
+package wiring
+
+import (
+	"testing"
+
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/ir"
+	"github.com/blueprint-uservices/blueprint/blueprint/pkg/wiring"
+	"github.com/blueprint-uservices/blueprint/plugins/retries"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+	wf "github.com/blueprint-uservices/blueprint/test/workflow/workflow"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+Tests for validating a wiring spec, and for building it while reporting every problem.
+
+Validation should report every problem at once rather than stopping at the first.
+*/
+
+func TestValidSpec(t *testing.T) {
+	spec := newWiringSpec("TestValidSpec")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+
+	report := spec.Validate(nonleaf)
+	assert.NoError(t, report.Err())
+	assert.Empty(t, report.Issues)
+}
+
+func TestValidateReportsAllErrors(t *testing.T) {
+	spec := newWiringSpec("TestValidateReportsAllErrors")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", "laef")
+	retries.AddRetries(spec, "lef", 3)
+	spec.Alias("a", "b")
+	spec.Alias("b", "a")
+
+	report := spec.Validate(leaf, nonleaf)
+	errs := report.Filter(wiring.IssueError)
+	require.Len(t, errs, 3, report.String())
+
+	assert.True(t, report.Has("laef"))
+	assert.True(t, report.Has("lef.client.retrier"))
+	for _, issue := range errs {
+		assert.Contains(t, issue.Location(), "validate_test.go")
+	}
+	assert.Error(t, report.Err())
+}
+
+func TestValidateUnusedDefs(t *testing.T) {
+	spec := newWiringSpec("TestValidateUnusedDefs")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	workflow.Service[*wf.TestLeafServiceImpl](spec, "unused")
+
+	report := spec.Validate(leaf)
+	assert.NoError(t, report.Err())
+
+	warnings := report.Filter(wiring.IssueWarning)
+	require.Len(t, warnings, 1, report.String())
+	assert.Contains(t, warnings[0].Message, "unused.handler")
+}
+
+func TestValidateMissingNode(t *testing.T) {
+	spec := newWiringSpec("TestValidateMissingNode")
+
+	workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+
+	report := spec.Validate("laef")
+	assert.Error(t, report.Err())
+	assert.True(t, report.Has("laef"))
+}
+
+func TestBuildIRWithReport(t *testing.T) {
+	spec := newWiringSpec("TestBuildIRWithReport")
+
+	leaf := workflow.Service[*wf.TestLeafServiceImpl](spec, "leaf")
+	nonleaf := workflow.Service[wf.TestNonLeafService](spec, "nonleaf", leaf)
+
+	// Wraps nonleaf, counting how many times it is built
+	builds := 0
+	spec.Define("counted", new(ir.IRNode), func(namespace wiring.Namespace) (ir.IRNode, error) {
+		builds++
+		var node ir.IRNode
+		err := namespace.Get(nonleaf, &node)
+		return node, err
+	})
+
+	app, report := spec.BuildIRWithReport("counted")
+	assert.NoError(t, report.Err())
+	assert.Empty(t, report.Issues)
+	assert.Equal(t, 1, builds)
+
+	assertIR(t, app,
+		`TestBuildIRWithReport = BlueprintApplication() {
+			leaf = TestLeafService()
+			leaf.client = leaf
+			leaf.handler.visibility
+			nonleaf = TestNonLeafService(leaf.client)
+			nonleaf.client = nonleaf
+			nonleaf.handler.visibility
+		  }`)
+}
@@ -3,11 +3,15 @@ package wiring
 import (
 	"testing"
 
+	"github.com/blueprint-uservices/blueprint/plugins/golang/gocode"
+	"github.com/blueprint-uservices/blueprint/plugins/golang/gogen"
 	"github.com/blueprint-uservices/blueprint/plugins/workflow"
+	"github.com/blueprint-uservices/blueprint/plugins/workflow/workflowspec"
 	wf "github.com/blueprint-uservices/blueprint/test/workflow/workflow"
 	"golang.org/x/exp/slog"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 /*
@@ -87,3 +91,210 @@ func TestBadServiceConstructor2(t *testing.T) {
 		slog.Info("Application: \n" + app.String())
 	}
 }
+
+func TestGenericService(t *testing.T) {
+	spec := newWiringSpec("TestGenericService")
+
+	generic := workflow.Service[wf.TestGenericService](spec, "generic")
+
+	app := assertBuildSuccess(t, spec, generic)
+
+	assertIR(t, app,
+		`TestGenericService = BlueprintApplication() {
+			generic = TestGenericService()
+			generic.client = generic
+			generic.handler.visibility
+		  }`)
+
+	service, err := workflowspec.GetService[wf.TestGenericService]()
+	require.NoError(t, err)
+	assert.Equal(t, "TestGenericServiceImpl", service.Struct.Name)
+
+	// Instantiations of generic types are resolved with their type arguments
+	list := service.Iface.Methods["ListObjects"]
+	assert.Equal(t, "workflow.TestPage[int64]", list.Arguments[1].Type.String())
+	assert.Equal(t, "workflow.TestPage[workflow.TestLeafObject]", list.Returns[0].Type.String())
+	lookup := service.Iface.Methods["LookupObject"]
+	assert.Equal(t, "*workflow.TestPair[string, workflow.TestLeafObject]", lookup.Returns[0].Type.String())
+	assert.Equal(t, "*workflow.TestPair[string, workflow.TestLeafObject]", gogen.NewImports("example.com/other").NameOf(lookup.Returns[0].Type))
+
+	// The fields of generic structs can be instantiated with the type arguments
+	page, err := workflowspec.Get().Modules.FindStruct(service.Struct.File.Package.Name, "TestPage")
+	require.NoError(t, err)
+	instantiation := list.Returns[0].Type.(*gocode.GenericType)
+	assert.Equal(t, "[]workflow.TestLeafObject", page.Fields["Items"].InstantiatedType(instantiation.TypeArgs).String())
+	assert.Equal(t, "int64", page.Fields["Next"].InstantiatedType(instantiation.TypeArgs).String())
+}
+
+func TestEmbeddedInterfaceService(t *testing.T) {
+	spec := newWiringSpec("TestEmbeddedInterfaceService")
+
+	echo := workflow.Service[wf.TestEchoService](spec, "echo")
+
+	app := assertBuildSuccess(t, spec, echo)
+
+	assertIR(t, app,
+		`TestEmbeddedInterfaceService = BlueprintApplication() {
+			echo = TestEchoService()
+			echo.client = echo
+			echo.handler.visibility
+		  }`)
+
+	service, err := workflowspec.GetService[wf.TestEchoService]()
+	require.NoError(t, err)
+
+	// Methods of embedded interfaces are promoted into the service interface
+	iface := service.Iface.ServiceInterface(nil)
+	assert.Len(t, iface.Methods, 2)
+	assert.Contains(t, iface.Methods, "Ping")
+	assert.Contains(t, iface.Methods, "Echo")
+
+	// Embedded struct fields are named after their type
+	req, err := workflowspec.Get().Modules.FindStruct(service.Struct.File.Package.Name, "TestEnvelopedRequest")
+	require.NoError(t, err)
+	require.Len(t, req.FieldsList, 2)
+	assert.True(t, req.FieldsList[0].Embedded)
+	assert.Equal(t, "TestEnvelope", req.FieldsList[0].Name)
+	assert.True(t, workflowspec.Get().Modules.IsStruct(req.FieldsList[0].Type))
+}
+
+func TestAmbiguousService(t *testing.T) {
+	// TestCounterServiceImpl implements two service interfaces and has two constructors
+	spec := newWiringSpec("TestAmbiguousService")
+	counter := workflow.Service[*wf.TestCounterServiceImpl](spec, "counter")
+	err := assertBuildFailure(t, spec, counter)
+	assert.ErrorContains(t, err, "unable to choose a service interface for TestCounterServiceImpl: found 2 candidates "+
+		"github.com/blueprint-uservices/blueprint/test/workflow/workflow.TestCounterService, "+
+		"github.com/blueprint-uservices/blueprint/test/workflow/workflow.TestResettableCounterService")
+
+	spec = newWiringSpec("TestAmbiguousService")
+	counter = workflow.Service[*wf.TestCounterServiceImpl](spec, "counter")
+	workflow.SetInterface(spec, counter, "TestResettableCounterService")
+	err = assertBuildFailure(t, spec, counter)
+	assert.ErrorContains(t, err, "unable to choose a constructor for TestCounterServiceImpl: found 2 candidates "+
+		"github.com/blueprint-uservices/blueprint/test/workflow/workflow.NewTestCounterServiceImpl, "+
+		"github.com/blueprint-uservices/blueprint/test/workflow/workflow.NewTestCounterServiceImplFrom")
+
+	spec = newWiringSpec("TestAmbiguousService")
+	counter = workflow.Service[*wf.TestCounterServiceImpl](spec, "counter")
+	workflow.SetInterface(spec, counter, "TestResettableCounterService")
+	workflow.SetConstructor(spec, counter, "NewTestCounterServiceImplFrom2")
+	err = assertBuildFailure(t, spec, counter)
+	assert.ErrorContains(t, err, "NewTestCounterServiceImplFrom2 is not one of the candidates")
+}
+
+func TestChosenService(t *testing.T) {
+	spec := newWiringSpec("TestChosenService")
+
+	counter := workflow.Service[*wf.TestCounterServiceImpl](spec, "counter", "10")
+	workflow.SetInterface(spec, counter, "TestResettableCounterService")
+	workflow.SetConstructor(spec, counter, "github.com/blueprint-uservices/blueprint/test/workflow/workflow.NewTestCounterServiceImplFrom")
+
+	app := assertBuildSuccess(t, spec, counter)
+
+	assertIR(t, app,
+		`TestChosenService = BlueprintApplication() {
+			counter = TestResettableCounterService("10")
+			counter.client = counter
+			counter.handler.visibility
+		  }`)
+
+	service, err := workflowspec.GetServiceWithOptions[*wf.TestCounterServiceImpl](workflowspec.ServiceOptions{
+		Constructor: "NewTestCounterServiceImpl",
+		Interface:   "TestCounterService",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "NewTestCounterServiceImpl", service.Constructor.Name)
+	assert.Equal(t, "TestCounterService", service.Iface.Name)
+
+	// An interface can't be chosen for a service that is itself an interface
+	_, err = workflowspec.GetServiceWithOptions[wf.TestLeafService](workflowspec.ServiceOptions{Interface: "TestCounterService"})
+	assert.ErrorContains(t, err, "TestLeafService is itself a service interface, so interface TestCounterService cannot be chosen for it")
+}
+
+func TestChosenInterfaceConstructor(t *testing.T) {
+	// The constructors of TestGreeterService return the interface, so the struct is the one the constructor instantiates
+	service, err := workflowspec.GetServiceWithOptions[wf.TestGreeterService](workflowspec.ServiceOptions{Constructor: "NewTestFrenchGreeter"})
+	require.NoError(t, err)
+	assert.Equal(t, "TestFrenchGreeterImpl", service.Struct.Name)
+
+	service, err = workflowspec.GetServiceWithOptions[wf.TestGreeterService](workflowspec.ServiceOptions{Constructor: "NewTestEnglishGreeter"})
+	require.NoError(t, err)
+	assert.Equal(t, "TestEnglishGreeterImpl", service.Struct.Name)
+
+	// The struct is instantiated by a helper that the constructor returns the result of
+	service, err = workflowspec.GetServiceWithOptions[wf.TestGreeterService](workflowspec.ServiceOptions{Constructor: "NewTestFrenchGreeterFromHelper"})
+	require.NoError(t, err)
+	assert.Equal(t, "TestFrenchGreeterImpl", service.Struct.Name)
+
+	_, err = workflowspec.GetServiceWithOptions[wf.TestGreeterService](workflowspec.ServiceOptions{Constructor: "NewTestGreeter"})
+	assert.ErrorContains(t, err, "unable to determine which struct implementing TestGreeterService is returned by NewTestGreeter: found 2 candidates "+
+		"github.com/blueprint-uservices/blueprint/test/workflow/workflow.TestEnglishGreeterImpl, "+
+		"github.com/blueprint-uservices/blueprint/test/workflow/workflow.TestFrenchGreeterImpl")
+}
+
+func TestStreamingService(t *testing.T) {
+	spec := newWiringSpec("TestStreamingService")
+
+	feed := workflow.Service[wf.TestFeedService](spec, "feed")
+
+	app := assertBuildSuccess(t, spec, feed)
+
+	assertIR(t, app,
+		`TestStreamingService = BlueprintApplication() {
+			feed = TestFeedService()
+			feed.client = feed
+			feed.handler.visibility
+		  }`)
+
+	service, err := workflowspec.GetService[wf.TestFeedService]()
+	require.NoError(t, err)
+	assert.Equal(t, "<-chan workflow.TestLeafObject", service.Iface.Methods["Subscribe"].Returns[0].Type.String())
+	assert.Equal(t, "<-chan workflow.TestLeafObject", service.Iface.Methods["Publish"].Arguments[2].Type.String())
+	assert.Equal(t, "chan int64", service.Iface.Methods["Format"].Arguments[1].Type.String())
+
+	_, err = workflowspec.GetService[wf.TestBadFeedService]()
+	assert.ErrorContains(t, err, "retval chan<- int64 of TestBadFeedService.Sink must be a receive-only channel, i.e. <-chan int64")
+}
+
+func TestNamedTypesService(t *testing.T) {
+	spec := newWiringSpec("TestNamedTypesService")
+
+	tasks := workflow.Service[wf.TestTaskService](spec, "tasks")
+
+	app := assertBuildSuccess(t, spec, tasks)
+
+	assertIR(t, app,
+		`TestNamedTypesService = BlueprintApplication() {
+			tasks = TestTaskService()
+			tasks.client = tasks
+			tasks.handler.visibility
+		  }`)
+
+	pkg, err := workflowspec.Get().Modules.GetPackage(gocode.TypeOf[wf.TestStatus]().(*gocode.UserType).Package)
+	require.NoError(t, err)
+
+	status := pkg.NamedTypes["TestStatus"]
+	require.NotNil(t, status)
+	assert.Equal(t, "int", status.Underlying.String())
+	var values []string
+	for _, c := range status.Consts {
+		values = append(values, c.Name+"="+c.Value.String())
+	}
+	assert.Equal(t, []string{"TestStatusPending=0", "TestStatusRunning=1", "TestStatusDone=2", "TestStatusFailed=-1"}, values)
+
+	priority := pkg.NamedTypes["TestPriority"]
+	require.NotNil(t, priority)
+	values = nil
+	for _, c := range priority.Consts {
+		values = append(values, c.Name+"="+c.Value.String())
+	}
+	// TestPriorityDefault refers to TestPriorityLow, which is declared after it
+	assert.Equal(t, []string{"TestPriorityDefault=1", "TestPriorityLow=1", "TestPriorityHigh=2", "TestPriorityUrgent=2"}, values)
+
+	assert.Equal(t, "[]string", pkg.NamedTypes["TestTags"].Underlying.String())
+	assert.False(t, pkg.NamedTypes["TestTags"].IsAlias)
+	assert.Equal(t, "[]int64", pkg.NamedTypes["TestTaskIDs"].Underlying.String())
+	assert.True(t, pkg.NamedTypes["TestTaskIDs"].IsAlias)
+	assert.NotContains(t, pkg.NamedTypes, "TestTask")
+}
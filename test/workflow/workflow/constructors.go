@@ -0,0 +1,98 @@
+package workflow
+
+import (
+	"context"
+	"strconv"
+)
+
+/*
+A simple service used for testing the explicit choice of a service's constructor and interface.
+TestCounterServiceImpl has two constructors and implements two service interfaces.
+*/
+
+type (
+	TestCounterService interface {
+		Increment(ctx context.Context) (int64, error)
+	}
+
+	TestResettableCounterService interface {
+		TestCounterService
+		Reset(ctx context.Context) error
+	}
+
+	TestCounterServiceImpl struct {
+		start int64
+		count int64
+	}
+)
+
+func NewTestCounterServiceImpl(ctx context.Context) (*TestCounterServiceImpl, error) {
+	return &TestCounterServiceImpl{}, nil
+}
+
+// A test helper constructor that starts counting from start
+func NewTestCounterServiceImplFrom(ctx context.Context, start string) (*TestCounterServiceImpl, error) {
+	count, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &TestCounterServiceImpl{start: count, count: count}, nil
+}
+
+func (s *TestCounterServiceImpl) Increment(ctx context.Context) (int64, error) {
+	s.count++
+	return s.count, nil
+}
+
+func (s *TestCounterServiceImpl) Reset(ctx context.Context) error {
+	s.count = s.start
+	return nil
+}
+
+/*
+A simple service used for testing that a service's struct is the one instantiated by its chosen constructor.
+TestGreeterService has two implementations, and its constructors return the interface rather than a struct.
+*/
+
+type (
+	TestGreeterService interface {
+		Greet(ctx context.Context, name string) (string, error)
+	}
+
+	TestEnglishGreeterImpl struct{}
+
+	TestFrenchGreeterImpl struct{}
+)
+
+func NewTestEnglishGreeter(ctx context.Context) (TestGreeterService, error) {
+	return &TestEnglishGreeterImpl{}, nil
+}
+
+func NewTestFrenchGreeter(ctx context.Context) (TestGreeterService, error) {
+	return new(TestFrenchGreeterImpl), nil
+}
+
+// Delegates to a helper, so the service's struct is the one that the helper instantiates
+func NewTestFrenchGreeterFromHelper(ctx context.Context) (TestGreeterService, error) {
+	return newTestFrenchGreeterImpl(), nil
+}
+
+func newTestFrenchGreeterImpl() *TestFrenchGreeterImpl {
+	return &TestFrenchGreeterImpl{}
+}
+
+// Instantiates either implementation, so the service's struct can't be determined
+func NewTestGreeter(ctx context.Context, language string) (TestGreeterService, error) {
+	if language == "fr" {
+		return &TestFrenchGreeterImpl{}, nil
+	}
+	return &TestEnglishGreeterImpl{}, nil
+}
+
+func (g *TestEnglishGreeterImpl) Greet(ctx context.Context, name string) (string, error) {
+	return "Hello " + name, nil
+}
+
+func (g *TestFrenchGreeterImpl) Greet(ctx context.Context, name string) (string, error) {
+	return "Bonjour " + name, nil
+}
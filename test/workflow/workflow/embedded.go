@@ -0,0 +1,45 @@
+package workflow
+
+import (
+	"context"
+)
+
+/*
+A simple service used for testing service interfaces that embed other interfaces, and
+arguments that embed other structs.
+*/
+
+type (
+	TestPingService interface {
+		Ping(ctx context.Context, req TestEnvelopedRequest) (string, error)
+	}
+
+	TestEchoService interface {
+		TestPingService
+		Echo(ctx context.Context, msg string) (string, error)
+	}
+
+	TestEnvelope struct {
+		RequestID string
+		Caller    string
+	}
+
+	TestEnvelopedRequest struct {
+		TestEnvelope
+		Body string
+	}
+
+	TestEchoServiceImpl struct{}
+)
+
+func NewTestEchoServiceImpl(ctx context.Context) (TestEchoService, error) {
+	return &TestEchoServiceImpl{}, nil
+}
+
+func (s *TestEchoServiceImpl) Ping(ctx context.Context, req TestEnvelopedRequest) (string, error) {
+	return req.RequestID, nil
+}
+
+func (s *TestEchoServiceImpl) Echo(ctx context.Context, msg string) (string, error) {
+	return msg, nil
+}
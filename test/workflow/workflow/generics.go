@@ -0,0 +1,59 @@
+package workflow
+
+import (
+	"context"
+)
+
+/*
+A simple service used for testing services whose methods use instantiated generic types.
+*/
+
+type (
+	TestGenericService interface {
+		ListObjects(ctx context.Context, page TestPage[int64]) (TestPage[TestLeafObject], error)
+		LookupObject(ctx context.Context, id int64) (*TestPair[string, TestLeafObject], error)
+	}
+
+	TestPage[T any] struct {
+		Items []T
+		Next  int64
+	}
+
+	TestPair[K comparable, V any] struct {
+		Key   K
+		Value V
+	}
+
+	TestGenericServiceImpl struct {
+		objects TestPage[TestLeafObject]
+	}
+)
+
+func NewTestGenericServiceImpl(ctx context.Context) (TestGenericService, error) {
+	return &TestGenericServiceImpl{}, nil
+}
+
+func (p *TestPage[T]) Len() int {
+	return len(p.Items)
+}
+
+func (s *TestGenericServiceImpl) ListObjects(ctx context.Context, page TestPage[int64]) (TestPage[TestLeafObject], error) {
+	var objects TestPage[TestLeafObject]
+	for _, id := range page.Items {
+		obj, err := s.LookupObject(ctx, id)
+		if err != nil {
+			return objects, err
+		}
+		objects.Items = append(objects.Items, obj.Value)
+	}
+	return objects, nil
+}
+
+func (s *TestGenericServiceImpl) LookupObject(ctx context.Context, id int64) (*TestPair[string, TestLeafObject], error) {
+	for _, obj := range s.objects.Items {
+		if obj.ID == id {
+			return &TestPair[string, TestLeafObject]{Key: obj.Name, Value: obj}, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,129 @@
+package workflow
+
+import (
+	"context"
+	"sync"
+)
+
+/*
+A simple service used for testing arguments and retvals of named types that are neither structs nor
+interfaces, e.g. enums, aliases, and named slices and maps.
+*/
+
+type (
+	TestTaskService interface {
+		// Adds a task, returning its ID
+		AddTask(ctx context.Context, name TestTaskName, priority TestPriority, tags TestTags, labels TestLabels) (int64, error)
+
+		// Sets the status of a task
+		SetStatus(ctx context.Context, id int64, status TestStatus) error
+
+		// Returns the task with the given ID
+		GetTask(ctx context.Context, id int64) (TestTask, error)
+
+		// Returns the IDs of the tasks with the given status
+		FindTasks(ctx context.Context, status TestStatus) (TestTaskIDs, error)
+
+		// Counts the tasks with each status
+		CountTasks(ctx context.Context) (map[TestStatus]int, error)
+	}
+
+	// An enum whose zero value is a constant
+	TestStatus int
+
+	// An enum whose zero value is not a constant
+	TestPriority uint8
+
+	TestTaskName string
+
+	TestTags []string
+
+	TestLabels map[string]TestTags
+
+	TestTaskIDs = []int64
+
+	TestTask struct {
+		ID       int64
+		Name     TestTaskName
+		Priority TestPriority
+		Status   TestStatus
+		History  []TestStatus
+		Tags     TestTags
+		Labels   TestLabels
+	}
+
+	TestTaskServiceImpl struct {
+		lock  sync.Mutex
+		tasks []TestTask
+	}
+)
+
+const (
+	TestStatusPending TestStatus = iota
+	TestStatusRunning
+	TestStatusDone
+	TestStatusFailed = TestStatus(-1)
+)
+
+// The default priority, which is declared before the constant that it refers to
+const TestPriorityDefault = TestPriorityLow
+
+const (
+	TestPriorityLow TestPriority = iota + 1
+	TestPriorityHigh
+	TestPriorityUrgent = TestPriorityHigh
+)
+
+func NewTestTaskServiceImpl(ctx context.Context) (TestTaskService, error) {
+	return &TestTaskServiceImpl{}, nil
+}
+
+func (s *TestTaskServiceImpl) AddTask(ctx context.Context, name TestTaskName, priority TestPriority, tags TestTags, labels TestLabels) (int64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	id := int64(len(s.tasks))
+	s.tasks = append(s.tasks, TestTask{ID: id, Name: name, Priority: priority, Tags: tags, Labels: labels})
+	return id, nil
+}
+
+func (s *TestTaskServiceImpl) SetStatus(ctx context.Context, id int64, status TestStatus) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if id >= 0 && id < int64(len(s.tasks)) {
+		task := &s.tasks[id]
+		task.History = append(task.History, task.Status)
+		task.Status = status
+	}
+	return nil
+}
+
+func (s *TestTaskServiceImpl) GetTask(ctx context.Context, id int64) (TestTask, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if id >= 0 && id < int64(len(s.tasks)) {
+		return s.tasks[id], nil
+	}
+	return TestTask{}, nil
+}
+
+func (s *TestTaskServiceImpl) FindTasks(ctx context.Context, status TestStatus) (TestTaskIDs, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var ids TestTaskIDs
+	for _, task := range s.tasks {
+		if task.Status == status {
+			ids = append(ids, task.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (s *TestTaskServiceImpl) CountTasks(ctx context.Context) (map[TestStatus]int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	counts := make(map[TestStatus]int)
+	for _, task := range s.tasks {
+		counts[task.Status]++
+	}
+	return counts, nil
+}
@@ -0,0 +1,84 @@
+package workflow
+
+import (
+	"context"
+	"strconv"
+	"sync"
+)
+
+/*
+A simple service used for testing asynchronous and streaming service methods, whose arguments and
+retvals are channels.
+*/
+
+type (
+	TestFeedService interface {
+		// Streams the objects published to topic so far
+		Subscribe(ctx context.Context, topic string) (<-chan TestLeafObject, error)
+
+		// Publishes objects to topic, returning the number published
+		Publish(ctx context.Context, topic string, objects <-chan TestLeafObject) (int64, error)
+
+		// Formats each of ids as a string
+		Format(ctx context.Context, ids chan int64) (<-chan string, error)
+	}
+
+	// Not a valid service, because the caller could only send to the returned channel
+	TestBadFeedService interface {
+		Sink(ctx context.Context) (chan<- int64, error)
+	}
+
+	TestFeedServiceImpl struct {
+		lock   sync.Mutex
+		topics map[string][]TestLeafObject
+	}
+)
+
+func NewTestFeedServiceImpl(ctx context.Context) (TestFeedService, error) {
+	return &TestFeedServiceImpl{topics: make(map[string][]TestLeafObject)}, nil
+}
+
+func (s *TestFeedServiceImpl) Subscribe(ctx context.Context, topic string) (<-chan TestLeafObject, error) {
+	s.lock.Lock()
+	objects := s.topics[topic]
+	s.lock.Unlock()
+
+	ch := make(chan TestLeafObject)
+	go func() {
+		defer close(ch)
+		for _, obj := range objects {
+			select {
+			case ch <- obj:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func (s *TestFeedServiceImpl) Publish(ctx context.Context, topic string, objects <-chan TestLeafObject) (int64, error) {
+	var count int64
+	for obj := range objects {
+		s.lock.Lock()
+		s.topics[topic] = append(s.topics[topic], obj)
+		s.lock.Unlock()
+		count++
+	}
+	return count, nil
+}
+
+func (s *TestFeedServiceImpl) Format(ctx context.Context, ids chan int64) (<-chan string, error) {
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+		for id := range ids {
+			select {
+			case ch <- strconv.FormatInt(id, 10):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}